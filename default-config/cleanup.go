@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/cleanup"
+	"github.com/cloudogu/ecosystem-core/default-config/compatibility"
+	"github.com/cloudogu/ecosystem-core/default-config/component"
+	"k8s.io/client-go/dynamic"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const (
+	defaultCleanupTimeoutSeconds              = 900
+	defaultCleanupFinalizerGracePeriodSeconds = 300
+	defaultCleanupForceRemoveFinalizers       = false
+)
+
+// cleanupConfig configures the cleanup command, which deletes all components before the ecosystem is uninstalled.
+type cleanupConfig struct {
+	namespace             string
+	logLevel              string
+	logFormat             string
+	logAddSource          bool
+	timeout               time.Duration
+	finalizerGracePeriod  time.Duration
+	forceRemoveFinalizers bool
+
+	// envErr contains the environment variables with malformed values.
+	envErr error
+}
+
+func readCleanupConfig() cleanupConfig {
+	env := &envReader{}
+	return cleanupConfig{
+		namespace:             os.Getenv("NAMESPACE"),
+		logLevel:              os.Getenv("LOG_LEVEL"),
+		logFormat:             os.Getenv("LOG_FORMAT"),
+		logAddSource:          env.readBool("LOG_ADD_SOURCE", false),
+		timeout:               time.Duration(env.readInt("CLEANUP_TIMEOUT_SECONDS", defaultCleanupTimeoutSeconds)) * time.Second,
+		finalizerGracePeriod:  time.Duration(env.readInt("CLEANUP_FINALIZER_GRACE_PERIOD_SECONDS", defaultCleanupFinalizerGracePeriodSeconds)) * time.Second,
+		forceRemoveFinalizers: env.readBool("CLEANUP_FORCE_REMOVE_FINALIZERS", defaultCleanupForceRemoveFinalizers),
+		envErr:                env.err(),
+	}
+}
+
+func (c cleanupConfig) validate() error {
+	var errs []error
+	if c.envErr != nil {
+		errs = append(errs, c.envErr)
+	}
+	if c.namespace == "" {
+		errs = append(errs, errors.New("NAMESPACE must be set"))
+	}
+	if c.timeout <= 0 {
+		errs = append(errs, errors.New("CLEANUP_TIMEOUT_SECONDS must be positive"))
+	}
+	if c.finalizerGracePeriod < 0 {
+		errs = append(errs, errors.New("CLEANUP_FINALIZER_GRACE_PERIOD_SECONDS must not be negative"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", errInvalidJobConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func cleanupCommand(signalCtx context.Context, stopSignals context.CancelFunc) int {
+	err := runCleanup(signalCtx, readCleanupConfig())
+	stopSignals()
+
+	if err != nil {
+		class := classifyError(signalCtx, err)
+		code := exitCodes[class]
+		slog.Error("failed to clean up components", "err", err, "errorClass", class, "exitCode", code)
+		return code
+	}
+
+	slog.Info("cleanup completed")
+	return 0
+}
+
+func runCleanup(ctx context.Context, cfg cleanupConfig) error {
+	configureLogger(cfg.logLevel, cfg.logFormat, cfg.logAddSource)
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	clusterConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read kube config: %w", err)
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(clusterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	// the components are deleted in reverse order of the requirements of the compatibility matrix
+	matrix, err := compatibility.DefaultMatrix()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("deleting all components...", "namespace", cfg.namespace, "timeout", cfg.timeout)
+	cleaner := cleanup.NewCleaner(dynamicClient.Resource(component.Resource).Namespace(cfg.namespace), cleanup.Options{
+		Dependencies:          matrix.Dependencies(),
+		FinalizerGracePeriod:  cfg.finalizerGracePeriod,
+		ForceRemoveFinalizers: cfg.forceRemoveFinalizers,
+	})
+
+	return cleaner.DeleteComponents(ctx)
+}
@@ -0,0 +1,312 @@
+package cleanup
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/component"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+const crdComponentSuffix = "-crd"
+
+// removeFinalizersPatch is a JSON merge patch that removes all finalizers.
+var removeFinalizersPatch = []byte(`{"metadata":{"finalizers":null}}`)
+
+type componentClient interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Delete(ctx context.Context, name string, options metav1.DeleteOptions, subresources ...string) error
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error)
+}
+
+// Options configure the order of the deletion and the handling of components that are stuck on their finalizers.
+type Options struct {
+	// Dependencies maps the components to the components they require, e.g. the requirements of the compatibility
+	// matrix. A component is deleted before the components it requires.
+	Dependencies map[string][]string
+	// FinalizerGracePeriod is the time after which components of a wave that still exist are reported as stuck.
+	FinalizerGracePeriod time.Duration
+	// ForceRemoveFinalizers removes the finalizers of stuck components, so that they are deleted without being
+	// finalized by the k8s-component-operator.
+	ForceRemoveFinalizers bool
+}
+
+// Cleaner deletes the Component CRs of a namespace before the ecosystem is uninstalled.
+type Cleaner struct {
+	client componentClient
+	opts   Options
+}
+
+func NewCleaner(client componentClient, opts Options) *Cleaner {
+	return &Cleaner{client: client, opts: opts}
+}
+
+// DeleteComponents deletes all components and blocks until they are gone or ctx is done.
+// The components are deleted in waves in reverse dependency order: a component is deleted after all components that
+// require it. The components of CRDs are deleted after all other components, because the operators need their CRDs to
+// finalize their resources.
+func (c *Cleaner) DeleteComponents(ctx context.Context) error {
+	list, err := c.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list components: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, obj := range list.Items {
+		names = append(names, obj.GetName())
+	}
+
+	if len(names) == 0 {
+		slog.Info("no components to delete")
+		return nil
+	}
+
+	for i, wave := range deletionWaves(names, c.opts.Dependencies) {
+		slog.Info("deleting components...", "wave", i+1, "components", wave)
+		if err = c.deleteWave(ctx, wave); err != nil {
+			return err
+		}
+		slog.Info("...deleted components", "wave", i+1)
+	}
+
+	return nil
+}
+
+// deletionWaves groups the components in the order they must be deleted. Each wave contains the components that are
+// not required by the components of later waves. Dependencies on components that are not installed are ignored.
+// Components with cyclic dependencies are deleted together, but still before the components of CRDs.
+func deletionWaves(names []string, dependencies map[string][]string) [][]string {
+	required := requiredComponents(names, dependencies)
+	remaining := slices.Clone(names)
+
+	var waves [][]string
+	for len(remaining) > 0 {
+		var wave, rest []string
+		for _, name := range remaining {
+			if isRequiredByAny(name, remaining, required) {
+				rest = append(rest, name)
+			} else {
+				wave = append(wave, name)
+			}
+		}
+
+		if len(wave) == 0 {
+			wave, rest = breakCycle(rest)
+			slog.Warn("components have cyclic dependencies and are deleted together", "components", wave)
+		}
+
+		slices.Sort(wave)
+		waves = append(waves, wave)
+		remaining = rest
+	}
+
+	return waves
+}
+
+// requiredComponents maps the components to the installed components they require. Every component requires the
+// components of CRDs, which are not required by the components of CRDs themselves.
+func requiredComponents(names []string, dependencies map[string][]string) map[string][]string {
+	required := map[string][]string{}
+	for _, name := range names {
+		for _, other := range names {
+			if other == name {
+				continue
+			}
+
+			isCRD := strings.HasSuffix(other, crdComponentSuffix) && !strings.HasSuffix(name, crdComponentSuffix)
+			if isCRD || slices.Contains(dependencies[name], other) {
+				required[name] = append(required[name], other)
+			}
+		}
+	}
+
+	return required
+}
+
+// breakCycle splits the components with cyclic dependencies into the wave that is deleted together and the components
+// of CRDs, which are deleted afterward.
+func breakCycle(components []string) ([]string, []string) {
+	var wave, crds []string
+	for _, name := range components {
+		if strings.HasSuffix(name, crdComponentSuffix) {
+			crds = append(crds, name)
+		} else {
+			wave = append(wave, name)
+		}
+	}
+
+	if len(wave) == 0 {
+		return crds, nil
+	}
+
+	return wave, crds
+}
+
+func isRequiredByAny(name string, components []string, required map[string][]string) bool {
+	for _, other := range components {
+		if slices.Contains(required[other], name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Cleaner) deleteWave(ctx context.Context, wave []string) error {
+	for _, name := range wave {
+		err := c.client.Delete(ctx, name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete component %q: %w", name, err)
+		}
+	}
+
+	return c.waitForDeletion(ctx, wave)
+}
+
+// waitForDeletion watches the components until all of them are gone.
+// Components that still exist after the grace period are reported and, if configured, their finalizers are removed.
+func (c *Cleaner) waitForDeletion(ctx context.Context, wave []string) error {
+	var graceExpired <-chan time.Time
+	if c.opts.FinalizerGracePeriod > 0 {
+		timer := time.NewTimer(c.opts.FinalizerGracePeriod)
+		defer timer.Stop()
+		graceExpired = timer.C
+	}
+
+	for {
+		remaining, resourceVersion, err := c.listRemaining(ctx, wave)
+		if err != nil {
+			return err
+		}
+
+		if len(remaining) == 0 {
+			return nil
+		}
+
+		watcher, err := c.client.Watch(ctx, metav1.ListOptions{ResourceVersion: resourceVersion})
+		if err != nil {
+			return fmt.Errorf("failed to watch components: %w", err)
+		}
+
+		done, err := c.watchRemaining(ctx, watcher, remaining, &graceExpired)
+		watcher.Stop()
+		if done || err != nil {
+			return err
+		}
+		// the watch has been closed by the API server, it is resumed with a fresh list
+	}
+}
+
+func (c *Cleaner) listRemaining(ctx context.Context, wave []string) (map[string]*unstructured.Unstructured, string, error) {
+	list, err := c.client.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list components: %w", err)
+	}
+
+	remaining := map[string]*unstructured.Unstructured{}
+	for i := range list.Items {
+		if slices.Contains(wave, list.Items[i].GetName()) {
+			remaining[list.Items[i].GetName()] = &list.Items[i]
+		}
+	}
+
+	return remaining, list.GetResourceVersion(), nil
+}
+
+// watchRemaining returns true if all remaining components have been deleted and false if the watch has been closed.
+// graceExpired is set to nil after the stuck components have been handled, so that they are handled only once.
+func (c *Cleaner) watchRemaining(ctx context.Context, watcher watch.Interface, remaining map[string]*unstructured.Unstructured, graceExpired *<-chan time.Time) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("timed out waiting for the deletion of components: %s: %w", describeStuck(remaining), ctx.Err())
+		case <-*graceExpired:
+			*graceExpired = nil
+			if err := c.handleStuck(ctx, remaining); err != nil {
+				return false, err
+			}
+		case ev, ok := <-watcher.ResultChan():
+			if !ok || ev.Type == watch.Error {
+				return false, nil
+			}
+
+			obj, isComponent := ev.Object.(*unstructured.Unstructured)
+			if !isComponent {
+				continue
+			}
+
+			if _, inWave := remaining[obj.GetName()]; !inWave {
+				continue
+			}
+
+			if ev.Type == watch.Deleted {
+				delete(remaining, obj.GetName())
+				slog.Info("component deleted", "component", obj.GetName(), "remaining", len(remaining))
+			} else {
+				remaining[obj.GetName()] = obj
+			}
+
+			if len(remaining) == 0 {
+				return true, nil
+			}
+		}
+	}
+}
+
+func (c *Cleaner) handleStuck(ctx context.Context, remaining map[string]*unstructured.Unstructured) error {
+	for _, name := range sortedNames(remaining) {
+		obj := remaining[name]
+		slog.Warn("component is not deleted after grace period",
+			"component", name,
+			"gracePeriod", c.opts.FinalizerGracePeriod,
+			"finalizers", obj.GetFinalizers(),
+			"status", component.Status(obj),
+			"conditions", component.Conditions(obj),
+		)
+
+		if !c.opts.ForceRemoveFinalizers || len(obj.GetFinalizers()) == 0 {
+			continue
+		}
+
+		slog.Warn("removing finalizers of component", "component", name, "finalizers", obj.GetFinalizers())
+		_, err := c.client.Patch(ctx, name, types.MergePatchType, removeFinalizersPatch, metav1.PatchOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to remove finalizers of component %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func describeStuck(remaining map[string]*unstructured.Unstructured) string {
+	var descriptions []string
+	for _, name := range sortedNames(remaining) {
+		obj := remaining[name]
+		description := fmt.Sprintf("%s (status %q, finalizers %v", name, component.Status(obj), obj.GetFinalizers())
+		if conds := component.Conditions(obj); len(conds) > 0 {
+			description += ", conditions " + strings.Join(conds, "; ")
+		}
+		descriptions = append(descriptions, description+")")
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+func sortedNames(remaining map[string]*unstructured.Unstructured) []string {
+	names := make([]string, 0, len(remaining))
+	for name := range remaining {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
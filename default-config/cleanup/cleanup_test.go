@@ -0,0 +1,259 @@
+package cleanup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/component"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "ecosystem"
+
+func newComponent(name string, finalizers ...string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "k8s.cloudogu.com/v1",
+		"kind":       "Component",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": testNamespace,
+		},
+		"status": map[string]any{
+			"status": "installed",
+			"conditions": []any{
+				map[string]any{"type": "Ready", "status": "False", "reason": "Deleting", "message": "waiting for helm uninstall"},
+			},
+		},
+	}}
+	obj.SetFinalizers(finalizers)
+	return obj
+}
+
+func newFakeClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{component.Resource: "ComponentList"}, objects...)
+}
+
+// keepComponentsWithFinalizers emulates the API server, which only marks objects with finalizers as deleted.
+// Removing the finalizers deletes them.
+func keepComponentsWithFinalizers(t *testing.T, client *fake.FakeDynamicClient) {
+	t.Helper()
+	tracker := client.Tracker()
+	client.PrependReactor("delete", "components", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.DeleteAction).GetName()
+		obj, err := tracker.Get(component.Resource, testNamespace, name)
+		if err != nil {
+			return true, nil, err
+		}
+
+		deleted := obj.(*unstructured.Unstructured)
+		if len(deleted.GetFinalizers()) == 0 {
+			return false, nil, nil
+		}
+
+		now := metav1.Now()
+		deleted.SetDeletionTimestamp(&now)
+		return true, nil, tracker.Update(component.Resource, deleted, testNamespace)
+	})
+	client.PrependReactor("patch", "components", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.PatchAction).GetName()
+		assert.JSONEq(t, `{"metadata":{"finalizers":null}}`, string(action.(k8stesting.PatchAction).GetPatch()))
+		return true, nil, tracker.Delete(component.Resource, testNamespace, name)
+	})
+}
+
+func deletedComponents(client *fake.FakeDynamicClient) []string {
+	var names []string
+	for _, action := range client.Actions() {
+		if deleteAction, ok := action.(k8stesting.DeleteAction); ok {
+			names = append(names, deleteAction.GetName())
+		}
+	}
+	return names
+}
+
+func TestCleaner_DeleteComponents(t *testing.T) {
+	t.Run("should delete operators before their CRDs", func(t *testing.T) {
+		client := newFakeClient(
+			newComponent("k8s-dogu-operator-crd"),
+			newComponent("k8s-dogu-operator"),
+			newComponent("k8s-blueprint-operator-crd"),
+			newComponent("k8s-blueprint-operator"),
+			newComponent("k8s-ces-gateway"),
+		)
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{})
+
+		err := cleaner.DeleteComponents(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"k8s-blueprint-operator", "k8s-ces-gateway", "k8s-dogu-operator",
+			"k8s-blueprint-operator-crd", "k8s-dogu-operator-crd",
+		}, deletedComponents(client))
+		list, err := client.Resource(component.Resource).Namespace(testNamespace).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, list.Items)
+	})
+
+	t.Run("should delete operators before the operators they require", func(t *testing.T) {
+		client := newFakeClient(
+			newComponent("k8s-service-discovery"),
+			newComponent("k8s-exposition-crd"),
+			newComponent("k8s-ces-gateway"),
+		)
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{
+			Dependencies: map[string][]string{
+				"k8s-ces-gateway":       {"k8s-service-discovery"},
+				"k8s-service-discovery": {"k8s-exposition-crd"},
+			},
+		})
+
+		err := cleaner.DeleteComponents(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"k8s-ces-gateway", "k8s-service-discovery", "k8s-exposition-crd"}, deletedComponents(client))
+	})
+
+	t.Run("should succeed without components", func(t *testing.T) {
+		client := newFakeClient()
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{})
+
+		err := cleaner.DeleteComponents(context.Background())
+
+		require.NoError(t, err)
+		assert.Empty(t, deletedComponents(client))
+	})
+
+	t.Run("should wait for components to be finalized", func(t *testing.T) {
+		client := newFakeClient(newComponent("k8s-dogu-operator", "component-finalizer"), newComponent("k8s-dogu-operator-crd"))
+		keepComponentsWithFinalizers(t, client)
+		watcherStarted := make(chan struct{})
+		client.PrependWatchReactor("components", func(action k8stesting.Action) (bool, watch.Interface, error) {
+			close(watcherStarted)
+			return false, nil, nil
+		})
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{})
+
+		go func() {
+			// the k8s-component-operator finalizes the component
+			<-watcherStarted
+			assert.NoError(t, client.Tracker().Delete(component.Resource, testNamespace, "k8s-dogu-operator"))
+		}()
+		err := cleaner.DeleteComponents(context.Background())
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"k8s-dogu-operator", "k8s-dogu-operator-crd"}, deletedComponents(client))
+	})
+
+	t.Run("should report stuck components on timeout", func(t *testing.T) {
+		client := newFakeClient(newComponent("k8s-dogu-operator", "component-finalizer"), newComponent("k8s-dogu-operator-crd"))
+		keepComponentsWithFinalizers(t, client)
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{FinalizerGracePeriod: time.Millisecond})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := cleaner.DeleteComponents(ctx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, `k8s-dogu-operator (status "installed", finalizers [component-finalizer], conditions Ready=False (Deleting): waiting for helm uninstall)`)
+		// the CRD is not deleted while its operator still exists
+		assert.Equal(t, []string{"k8s-dogu-operator"}, deletedComponents(client))
+	})
+
+	t.Run("should remove finalizers of stuck components after grace period", func(t *testing.T) {
+		client := newFakeClient(newComponent("k8s-dogu-operator", "component-finalizer"), newComponent("k8s-dogu-operator-crd", "component-finalizer"))
+		keepComponentsWithFinalizers(t, client)
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{
+			FinalizerGracePeriod:  time.Millisecond,
+			ForceRemoveFinalizers: true,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := cleaner.DeleteComponents(ctx)
+
+		require.NoError(t, err)
+		var patched []string
+		for _, action := range client.Actions() {
+			if patchAction, ok := action.(k8stesting.PatchAction); ok {
+				patched = append(patched, patchAction.GetName())
+			}
+		}
+		assert.Equal(t, []string{"k8s-dogu-operator", "k8s-dogu-operator-crd"}, patched)
+	})
+
+	t.Run("should fail on error deleting component", func(t *testing.T) {
+		client := newFakeClient(newComponent("k8s-dogu-operator"))
+		client.PrependReactor("delete", "components", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(component.Resource.GroupResource(), "k8s-dogu-operator", assert.AnError)
+		})
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{})
+
+		err := cleaner.DeleteComponents(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `failed to delete component "k8s-dogu-operator"`)
+	})
+
+	t.Run("should fail on error listing components", func(t *testing.T) {
+		client := newFakeClient()
+		client.PrependReactor("list", "components", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		cleaner := NewCleaner(client.Resource(component.Resource).Namespace(testNamespace), Options{})
+
+		err := cleaner.DeleteComponents(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to list components")
+	})
+}
+
+func Test_deletionWaves(t *testing.T) {
+	assert.Equal(t, [][]string{{"a", "b"}, {"a-crd"}}, deletionWaves([]string{"a-crd", "b", "a"}, nil))
+	assert.Equal(t, [][]string{{"a-crd"}}, deletionWaves([]string{"a-crd"}, nil))
+	assert.Empty(t, deletionWaves(nil, nil))
+
+	t.Run("should delete components before the components they require", func(t *testing.T) {
+		dependencies := map[string][]string{
+			"k8s-ces-gateway":     {"k8s-service-discovery"},
+			"k8s-backup-operator": {"k8s-backup-operator-crd", "k8s-velero"},
+			"k8s-ces-assets":      {"k8s-dogu-operator", "k8s-warp-menu-entry-crd"},
+		}
+
+		waves := deletionWaves([]string{
+			"k8s-velero", "k8s-service-discovery", "k8s-ces-gateway", "k8s-backup-operator-crd",
+			"k8s-backup-operator", "k8s-dogu-operator", "k8s-ces-assets", "k8s-warp-menu-entry-crd",
+		}, dependencies)
+
+		assert.Equal(t, [][]string{
+			{"k8s-backup-operator", "k8s-ces-assets", "k8s-ces-gateway"},
+			{"k8s-dogu-operator", "k8s-service-discovery", "k8s-velero"},
+			{"k8s-backup-operator-crd", "k8s-warp-menu-entry-crd"},
+		}, waves)
+	})
+
+	t.Run("should ignore dependencies on components that are not installed", func(t *testing.T) {
+		waves := deletionWaves([]string{"k8s-ces-gateway"}, map[string][]string{"k8s-ces-gateway": {"k8s-service-discovery"}})
+
+		assert.Equal(t, [][]string{{"k8s-ces-gateway"}}, waves)
+	})
+
+	t.Run("should delete components with cyclic dependencies together", func(t *testing.T) {
+		waves := deletionWaves([]string{"a", "b", "c", "a-crd"}, map[string][]string{"a": {"b"}, "b": {"a"}})
+
+		assert.Equal(t, [][]string{{"c"}, {"a", "b"}, {"a-crd"}}, waves)
+	})
+}
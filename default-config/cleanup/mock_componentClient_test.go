@@ -0,0 +1,301 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package cleanup
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	types "k8s.io/apimachinery/pkg/types"
+
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	watch "k8s.io/apimachinery/pkg/watch"
+)
+
+// mockComponentClient is an autogenerated mock type for the componentClient type
+type mockComponentClient struct {
+	mock.Mock
+}
+
+type mockComponentClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockComponentClient) EXPECT() *mockComponentClient_Expecter {
+	return &mockComponentClient_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function with given fields: ctx, name, options, subresources
+func (_m *mockComponentClient) Delete(ctx context.Context, name string, options v1.DeleteOptions, subresources ...string) error {
+	_va := make([]interface{}, len(subresources))
+	for _i := range subresources {
+		_va[_i] = subresources[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, name, options)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, v1.DeleteOptions, ...string) error); ok {
+		r0 = rf(ctx, name, options, subresources...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// mockComponentClient_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type mockComponentClient_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - options v1.DeleteOptions
+//   - subresources ...string
+func (_e *mockComponentClient_Expecter) Delete(ctx interface{}, name interface{}, options interface{}, subresources ...interface{}) *mockComponentClient_Delete_Call {
+	return &mockComponentClient_Delete_Call{Call: _e.mock.On("Delete",
+		append([]interface{}{ctx, name, options}, subresources...)...)}
+}
+
+func (_c *mockComponentClient_Delete_Call) Run(run func(ctx context.Context, name string, options v1.DeleteOptions, subresources ...string)) *mockComponentClient_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(v1.DeleteOptions), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockComponentClient_Delete_Call) Return(_a0 error) *mockComponentClient_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockComponentClient_Delete_Call) RunAndReturn(run func(context.Context, string, v1.DeleteOptions, ...string) error) *mockComponentClient_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, opts
+func (_m *mockComponentClient) List(ctx context.Context, opts v1.ListOptions) (*unstructured.UnstructuredList, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *unstructured.UnstructuredList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1.ListOptions) (*unstructured.UnstructuredList, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1.ListOptions) *unstructured.UnstructuredList); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.UnstructuredList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockComponentClient_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type mockComponentClient_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts v1.ListOptions
+func (_e *mockComponentClient_Expecter) List(ctx interface{}, opts interface{}) *mockComponentClient_List_Call {
+	return &mockComponentClient_List_Call{Call: _e.mock.On("List", ctx, opts)}
+}
+
+func (_c *mockComponentClient_List_Call) Run(run func(ctx context.Context, opts v1.ListOptions)) *mockComponentClient_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1.ListOptions))
+	})
+	return _c
+}
+
+func (_c *mockComponentClient_List_Call) Return(_a0 *unstructured.UnstructuredList, _a1 error) *mockComponentClient_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockComponentClient_List_Call) RunAndReturn(run func(context.Context, v1.ListOptions) (*unstructured.UnstructuredList, error)) *mockComponentClient_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Patch provides a mock function with given fields: ctx, name, pt, data, options, subresources
+func (_m *mockComponentClient) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options v1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	_va := make([]interface{}, len(subresources))
+	for _i := range subresources {
+		_va[_i] = subresources[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, name, pt, data, options)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 *unstructured.Unstructured
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) (*unstructured.Unstructured, error)); ok {
+		return rf(ctx, name, pt, data, options, subresources...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) *unstructured.Unstructured); ok {
+		r0 = rf(ctx, name, pt, data, options, subresources...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.Unstructured)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) error); ok {
+		r1 = rf(ctx, name, pt, data, options, subresources...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockComponentClient_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type mockComponentClient_Patch_Call struct {
+	*mock.Call
+}
+
+// Patch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - pt types.PatchType
+//   - data []byte
+//   - options v1.PatchOptions
+//   - subresources ...string
+func (_e *mockComponentClient_Expecter) Patch(ctx interface{}, name interface{}, pt interface{}, data interface{}, options interface{}, subresources ...interface{}) *mockComponentClient_Patch_Call {
+	return &mockComponentClient_Patch_Call{Call: _e.mock.On("Patch",
+		append([]interface{}{ctx, name, pt, data, options}, subresources...)...)}
+}
+
+func (_c *mockComponentClient_Patch_Call) Run(run func(ctx context.Context, name string, pt types.PatchType, data []byte, options v1.PatchOptions, subresources ...string)) *mockComponentClient_Patch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-5)
+		for i, a := range args[5:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(types.PatchType), args[3].([]byte), args[4].(v1.PatchOptions), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockComponentClient_Patch_Call) Return(_a0 *unstructured.Unstructured, _a1 error) *mockComponentClient_Patch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockComponentClient_Patch_Call) RunAndReturn(run func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) (*unstructured.Unstructured, error)) *mockComponentClient_Patch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Watch provides a mock function with given fields: ctx, opts
+func (_m *mockComponentClient) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	ret := _m.Called(ctx, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Watch")
+	}
+
+	var r0 watch.Interface
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, v1.ListOptions) (watch.Interface, error)); ok {
+		return rf(ctx, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, v1.ListOptions) watch.Interface); ok {
+		r0 = rf(ctx, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(watch.Interface)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, v1.ListOptions) error); ok {
+		r1 = rf(ctx, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockComponentClient_Watch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Watch'
+type mockComponentClient_Watch_Call struct {
+	*mock.Call
+}
+
+// Watch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - opts v1.ListOptions
+func (_e *mockComponentClient_Expecter) Watch(ctx interface{}, opts interface{}) *mockComponentClient_Watch_Call {
+	return &mockComponentClient_Watch_Call{Call: _e.mock.On("Watch", ctx, opts)}
+}
+
+func (_c *mockComponentClient_Watch_Call) Run(run func(ctx context.Context, opts v1.ListOptions)) *mockComponentClient_Watch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(v1.ListOptions))
+	})
+	return _c
+}
+
+func (_c *mockComponentClient_Watch_Call) Return(_a0 watch.Interface, _a1 error) *mockComponentClient_Watch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockComponentClient_Watch_Call) RunAndReturn(run func(context.Context, v1.ListOptions) (watch.Interface, error)) *mockComponentClient_Watch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockComponentClient creates a new instance of mockComponentClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockComponentClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockComponentClient {
+	mock := &mockComponentClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readCleanupConfig(t *testing.T) {
+	t.Run("success with defaults", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "ecosystem")
+
+		cfg := readCleanupConfig()
+
+		assert.Equal(t, "ecosystem", cfg.namespace)
+		assert.Equal(t, 15*time.Minute, cfg.timeout)
+		assert.Equal(t, 5*time.Minute, cfg.finalizerGracePeriod)
+		assert.False(t, cfg.forceRemoveFinalizers)
+	})
+	t.Run("success with finalizer options", func(t *testing.T) {
+		t.Setenv("CLEANUP_TIMEOUT_SECONDS", "60")
+		t.Setenv("CLEANUP_FINALIZER_GRACE_PERIOD_SECONDS", "10")
+		t.Setenv("CLEANUP_FORCE_REMOVE_FINALIZERS", "true")
+
+		cfg := readCleanupConfig()
+
+		assert.Equal(t, time.Minute, cfg.timeout)
+		assert.Equal(t, 10*time.Second, cfg.finalizerGracePeriod)
+		assert.True(t, cfg.forceRemoveFinalizers)
+	})
+	t.Run("should fail the validation for malformed values", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "ecosystem")
+		t.Setenv("CLEANUP_TIMEOUT_SECONDS", "1m")
+
+		cfg := readCleanupConfig()
+
+		assert.Equal(t, 15*time.Minute, cfg.timeout)
+		err := cfg.validate()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, `CLEANUP_TIMEOUT_SECONDS must be an integer, got "1m"`)
+	})
+}
+
+func Test_cleanupConfig_validate(t *testing.T) {
+	t.Run("should accept valid config", func(t *testing.T) {
+		cfg := cleanupConfig{namespace: "ecosystem", timeout: time.Minute}
+
+		require.NoError(t, cfg.validate())
+	})
+
+	t.Run("should reject invalid config", func(t *testing.T) {
+		cfg := cleanupConfig{finalizerGracePeriod: -time.Second}
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "NAMESPACE must be set")
+		assert.ErrorContains(t, err, "CLEANUP_TIMEOUT_SECONDS must be positive")
+		assert.ErrorContains(t, err, "CLEANUP_FINALIZER_GRACE_PERIOD_SECONDS must not be negative")
+	})
+}
+
+func Test_runCleanup(t *testing.T) {
+	t.Run("should fail on invalid config", func(t *testing.T) {
+		err := runCleanup(context.Background(), cleanupConfig{})
+
+		require.Error(t, err)
+		assert.Equal(t, errorClassValidation, classifyError(context.Background(), err))
+	})
+}
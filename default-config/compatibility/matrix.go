@@ -0,0 +1,153 @@
+package compatibility
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/cloudogu/ecosystem-core/default-config/versions"
+	"sigs.k8s.io/yaml"
+)
+
+// EcosystemCore is the name of the chart in the matrix. Its version is the version of the chart.
+const EcosystemCore = "ecosystem-core"
+
+//go:embed matrix.yaml
+var defaultMatrix []byte
+
+// Matrix contains the rules for the versions of components that are installed together.
+type Matrix struct {
+	rules []rule
+}
+
+type rule struct {
+	component      string
+	versions       versions.Constraint
+	requires       map[string]versions.Constraint
+	compatibleWith map[string]versions.Constraint
+}
+
+type rawRule struct {
+	Component      string            `json:"component"`
+	Versions       string            `json:"versions"`
+	Requires       map[string]string `json:"requires"`
+	CompatibleWith map[string]string `json:"compatibleWith"`
+}
+
+// DefaultMatrix returns the matrix that is shipped with the chart.
+func DefaultMatrix() (Matrix, error) {
+	return ParseMatrix(defaultMatrix)
+}
+
+func ParseMatrix(raw []byte) (Matrix, error) {
+	var rawRules []rawRule
+	if err := yaml.UnmarshalStrict(raw, &rawRules); err != nil {
+		return Matrix{}, fmt.Errorf("failed to parse compatibility matrix: %w", err)
+	}
+
+	matrix := Matrix{rules: make([]rule, 0, len(rawRules))}
+	for i, raw := range rawRules {
+		if raw.Component == "" {
+			return Matrix{}, fmt.Errorf("rule %d: component must be set", i)
+		}
+
+		var err error
+		r := rule{component: raw.Component}
+		if r.versions, err = versions.ParseConstraint(raw.Versions); err != nil {
+			return Matrix{}, fmt.Errorf("rule %d (%s): %w", i, raw.Component, err)
+		}
+		if r.requires, err = parseConstraints(raw.Requires); err != nil {
+			return Matrix{}, fmt.Errorf("rule %d (%s): %w", i, raw.Component, err)
+		}
+		if r.compatibleWith, err = parseConstraints(raw.CompatibleWith); err != nil {
+			return Matrix{}, fmt.Errorf("rule %d (%s): %w", i, raw.Component, err)
+		}
+		matrix.rules = append(matrix.rules, r)
+	}
+
+	return matrix, nil
+}
+
+func parseConstraints(raw map[string]string) (map[string]versions.Constraint, error) {
+	constraints := make(map[string]versions.Constraint, len(raw))
+	for component, constraint := range raw {
+		parsed, err := versions.ParseConstraint(constraint)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", component, err)
+		}
+		constraints[component] = parsed
+	}
+	return constraints, nil
+}
+
+// Dependencies maps the components to the components they require in any of their versions, sorted by name.
+func (m Matrix) Dependencies() map[string][]string {
+	dependencies := map[string][]string{}
+	for _, r := range m.rules {
+		for dependency := range r.requires {
+			if !slices.Contains(dependencies[r.component], dependency) {
+				dependencies[r.component] = append(dependencies[r.component], dependency)
+			}
+		}
+	}
+
+	for _, required := range dependencies {
+		slices.Sort(required)
+	}
+
+	return dependencies
+}
+
+// Check verifies the versions of the installed components, which maps the components to their versions.
+// Components without a version, e.g. "latest", cannot be checked and are skipped. The error describes every violation.
+func (m Matrix) Check(components map[string]string) error {
+	parsed := map[string]versions.Version{}
+	for component, raw := range components {
+		version, err := versions.ParseVersion(raw)
+		if err != nil {
+			slog.Warn("skipping compatibility check of component without version", "component", component, "version", raw)
+			continue
+		}
+		parsed[component] = version
+	}
+
+	var errs []error
+	for _, r := range m.rules {
+		version, ok := parsed[r.component]
+		if !ok || !r.versions.Check(version) {
+			continue
+		}
+
+		for _, dependency := range slices.Sorted(maps.Keys(r.requires)) {
+			constraint := r.requires[dependency]
+			if _, installed := components[dependency]; !installed {
+				errs = append(errs, fmt.Errorf("%s %s requires %s %s, which is not installed", r.component, version, dependency, constraint))
+				continue
+			}
+			if err := checkDependency(r.component, version, dependency, constraint, parsed); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		for _, dependency := range slices.Sorted(maps.Keys(r.compatibleWith)) {
+			if err := checkDependency(r.component, version, dependency, r.compatibleWith[dependency], parsed); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkDependency verifies the version of the dependency if it is installed with a version.
+func checkDependency(component string, version versions.Version, dependency string, constraint versions.Constraint, installed map[string]versions.Version) error {
+	dependencyVersion, ok := installed[dependency]
+	if !ok || constraint.Check(dependencyVersion) {
+		return nil
+	}
+
+	return fmt.Errorf("%s %s requires %s %s, but %s is installed", component, version, dependency, constraint, dependencyVersion)
+}
@@ -0,0 +1,152 @@
+package compatibility
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const testMatrix = `
+- component: k8s-dogu-operator
+  versions: ">=3.0.0"
+  requires:
+    k8s-dogu-operator-crd: ">=2.13.0"
+  compatibleWith:
+    k8s-ces-assets: ">=3.0.0"
+- component: ecosystem-core
+  versions: ">=4.2.0"
+  compatibleWith:
+    k8s-component-operator: ">=1.14.0"
+`
+
+func TestMatrix_Check(t *testing.T) {
+	matrix, err := ParseMatrix([]byte(testMatrix))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		components map[string]string
+		wantErrs   []string
+	}{
+		{"compatible", map[string]string{"k8s-dogu-operator": "3.27.0", "k8s-dogu-operator-crd": "2.13.0", "k8s-ces-assets": "3.0.0"}, nil},
+		{"optional component not installed", map[string]string{"k8s-dogu-operator": "3.27.0", "k8s-dogu-operator-crd": "2.14.0"}, nil},
+		{"rule does not apply", map[string]string{"k8s-dogu-operator": "2.0.0", "ecosystem-core": "4.1.0", "k8s-component-operator": "1.12.2"}, nil},
+		{"component without version", map[string]string{"k8s-dogu-operator": "latest"}, nil},
+		{"required component without version", map[string]string{"k8s-dogu-operator": "3.27.0", "k8s-dogu-operator-crd": "latest"}, nil},
+		{
+			"required component not installed",
+			map[string]string{"k8s-dogu-operator": "3.27.0"},
+			[]string{"k8s-dogu-operator 3.27.0 requires k8s-dogu-operator-crd >=2.13.0, which is not installed"},
+		},
+		{
+			"incompatible versions",
+			map[string]string{"k8s-dogu-operator": "3.27.0", "k8s-dogu-operator-crd": "2.11.0", "k8s-ces-assets": "2.0.2", "ecosystem-core": "4.8.1", "k8s-component-operator": "1.12.2"},
+			[]string{
+				"k8s-dogu-operator 3.27.0 requires k8s-dogu-operator-crd >=2.13.0, but 2.11.0 is installed",
+				"k8s-dogu-operator 3.27.0 requires k8s-ces-assets >=3.0.0, but 2.0.2 is installed",
+				"ecosystem-core 4.8.1 requires k8s-component-operator >=1.14.0, but 1.12.2 is installed",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := matrix.Check(tt.components)
+
+			if len(tt.wantErrs) == 0 {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			for _, want := range tt.wantErrs {
+				assert.ErrorContains(t, err, want)
+			}
+		})
+	}
+}
+
+func TestParseMatrix(t *testing.T) {
+	tests := map[string]struct {
+		raw     string
+		wantErr string
+	}{
+		"invalid yaml":        {"- component: [", "failed to parse compatibility matrix"},
+		"unknown field":       {"- component: a\n  versions: 1.0.0\n  require: {}\n", "failed to parse compatibility matrix"},
+		"missing component":   {"- versions: 1.0.0\n", "rule 0: component must be set"},
+		"invalid versions":    {"- component: a\n  versions: latest\n", "rule 0 (a): invalid constraint"},
+		"invalid requirement": {"- component: a\n  versions: 1.0.0\n  requires:\n    b: latest\n", "rule 0 (a): component b: invalid constraint"},
+		"invalid compatible":  {"- component: a\n  versions: 1.0.0\n  compatibleWith:\n    b: latest\n", "rule 0 (a): component b: invalid constraint"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseMatrix([]byte(tt.raw))
+
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMatrix_Dependencies(t *testing.T) {
+	matrix, err := ParseMatrix([]byte(testMatrix + `
+- component: k8s-dogu-operator
+  versions: ">=3.27.0"
+  requires:
+    k8s-warp-menu-entry-crd: ">=1.0.0"
+    k8s-dogu-operator-crd: ">=2.14.0"
+`))
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"k8s-dogu-operator": {"k8s-dogu-operator-crd", "k8s-warp-menu-entry-crd"},
+	}, matrix.Dependencies())
+}
+
+// TestDefaultMatrix_shippedValues verifies that the components of the values.yaml of the chart are compatible,
+// also with the optional components and stacks.
+func TestDefaultMatrix_shippedValues(t *testing.T) {
+	matrix, err := DefaultMatrix()
+	require.NoError(t, err)
+
+	raw, err := os.ReadFile("../../k8s/helm/values.yaml")
+	require.NoError(t, err)
+	// the default-config image is released with the chart
+	var image struct {
+		DefaultConfig struct {
+			Image struct {
+				Tag string `json:"tag"`
+			} `json:"image"`
+		} `json:"defaultConfig"`
+	}
+	require.NoError(t, yaml.Unmarshal(raw, &image))
+	chartVersion := image.DefaultConfig.Image.Tag
+
+	variants := map[string]func(values *Values){
+		"defaults":            func(*Values) {},
+		"lop-idp":             func(values *Values) { values.UseLopIdp = true },
+		"without backup":      func(values *Values) { values.Backup.Enabled = false },
+		"with backup":         func(values *Values) { values.Backup.Enabled = true },
+		"with monitoring":     func(values *Values) { values.Monitoring.Enabled = true },
+		"external components": func(values *Values) { values.ComponentOperator.Enabled = new(bool) },
+	}
+	for name, modify := range variants {
+		t.Run(name, func(t *testing.T) {
+			values, err := ParseValues(raw)
+			require.NoError(t, err)
+			modify(&values)
+
+			assert.NoError(t, matrix.Check(values.EffectiveComponents(chartVersion)))
+		})
+	}
+
+	t.Run("should detect that k8s-ces-control requires the monitoring stack", func(t *testing.T) {
+		values, err := ParseValues(raw)
+		require.NoError(t, err)
+		values.Monitoring.Enabled = false
+
+		err = matrix.Check(values.EffectiveComponents(chartVersion))
+
+		assert.ErrorContains(t, err, "requires k8s-prometheus >=75.0.0, which is not installed")
+	})
+}
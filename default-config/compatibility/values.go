@@ -0,0 +1,97 @@
+package compatibility
+
+import (
+	"fmt"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+)
+
+// componentOperator is a dependency of the chart instead of a component. Its version is the tag of its image.
+const componentOperator = "k8s-component-operator"
+
+// Values are the parts of the chart values that select the installed components.
+type Values struct {
+	UseLopIdp         bool                 `json:"use-lop-idp"`
+	ComponentOperator ComponentOperator    `json:"k8s-component-operator"`
+	Components        map[string]Component `json:"components"`
+	Backup            Stack                `json:"backup"`
+	Monitoring        Stack                `json:"monitoring"`
+}
+
+type ComponentOperator struct {
+	// Enabled defaults to true like the condition of the dependency.
+	Enabled *bool `json:"enabled"`
+	Manager struct {
+		Image struct {
+			Tag string `json:"tag"`
+		} `json:"image"`
+	} `json:"manager"`
+}
+
+// Stack is an optional group of components like the backup or monitoring stack.
+type Stack struct {
+	Enabled    bool                 `json:"enabled"`
+	Components map[string]Component `json:"components"`
+}
+
+type Component struct {
+	// Name overrides the key of the component.
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Disabled bool   `json:"disabled"`
+}
+
+// lopIdpComponents are enabled by use-lop-idp.
+var lopIdpComponents = []string{"k8s-auth-registration-crd", "lop-idp", "postfix"}
+
+// ParseValues parses the values as YAML or JSON.
+func ParseValues(raw []byte) (Values, error) {
+	var values Values
+	if err := yaml.Unmarshal(raw, &values); err != nil {
+		return Values{}, fmt.Errorf("failed to parse values: %w", err)
+	}
+
+	return values, nil
+}
+
+// EffectiveComponents returns the versions of the components the chart installs with the values, like the templates
+// of the chart do: use-lop-idp enables its components, disabled components and the components of disabled stacks are
+// skipped. The chart itself is returned as EcosystemCore if its version is set.
+func (v Values) EffectiveComponents(chartVersion string) map[string]string {
+	components := map[string]string{}
+	if chartVersion != "" {
+		components[EcosystemCore] = chartVersion
+	}
+	if v.ComponentOperator.Enabled == nil || *v.ComponentOperator.Enabled {
+		components[componentOperator] = v.ComponentOperator.Manager.Image.Tag
+	}
+
+	stacks := []map[string]Component{v.Components}
+	if v.Backup.Enabled {
+		stacks = append(stacks, v.Backup.Components)
+	}
+	if v.Monitoring.Enabled {
+		stacks = append(stacks, v.Monitoring.Components)
+	}
+
+	for i, stack := range stacks {
+		for key, component := range stack {
+			// only the root components are enabled by use-lop-idp
+			if i == 0 && v.UseLopIdp && slices.Contains(lopIdpComponents, key) {
+				component.Disabled = false
+			}
+			if component.Disabled {
+				continue
+			}
+
+			name := component.Name
+			if name == "" {
+				name = key
+			}
+			components[name] = component.Version
+		}
+	}
+
+	return components
+}
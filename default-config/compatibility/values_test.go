@@ -0,0 +1,85 @@
+package compatibility
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testValues = `
+use-lop-idp: false
+k8s-component-operator:
+  manager:
+    image:
+      tag: 1.14.0
+components:
+  k8s-dogu-operator:
+    version: 3.27.0
+  lop-idp:
+    disabled: true
+    version: 1.3.2
+  renamed:
+    name: k8s-ces-gateway
+    version: 3.3.3
+backup:
+  enabled: true
+  components:
+    k8s-backup-operator:
+      version: 3.2.0
+monitoring:
+  enabled: false
+  components:
+    k8s-prometheus:
+      version: 75.3.5-7
+`
+
+func TestValues_EffectiveComponents(t *testing.T) {
+	t.Run("should skip disabled components and stacks", func(t *testing.T) {
+		values, err := ParseValues([]byte(testValues))
+		require.NoError(t, err)
+
+		components := values.EffectiveComponents("4.8.1")
+
+		assert.Equal(t, map[string]string{
+			"ecosystem-core":         "4.8.1",
+			"k8s-component-operator": "1.14.0",
+			"k8s-dogu-operator":      "3.27.0",
+			"k8s-ces-gateway":        "3.3.3",
+			"k8s-backup-operator":    "3.2.0",
+		}, components)
+	})
+
+	t.Run("should enable the lop-idp components", func(t *testing.T) {
+		values, err := ParseValues([]byte(testValues))
+		require.NoError(t, err)
+		values.UseLopIdp = true
+		values.Monitoring.Enabled = true
+
+		components := values.EffectiveComponents("")
+
+		assert.Equal(t, "1.3.2", components["lop-idp"])
+		assert.Equal(t, "75.3.5-7", components["k8s-prometheus"])
+		assert.NotContains(t, components, "ecosystem-core")
+	})
+
+	t.Run("should skip the disabled component operator", func(t *testing.T) {
+		values, err := ParseValues([]byte("k8s-component-operator:\n  enabled: false\n"))
+		require.NoError(t, err)
+
+		assert.Empty(t, values.EffectiveComponents(""))
+	})
+
+	t.Run("should parse json", func(t *testing.T) {
+		values, err := ParseValues([]byte(`{"components":{"k8s-dogu-operator":{"version":"3.27.0"}},"k8s-component-operator":{"enabled":false}}`))
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]string{"k8s-dogu-operator": "3.27.0"}, values.EffectiveComponents(""))
+	})
+
+	t.Run("should fail on invalid values", func(t *testing.T) {
+		_, err := ParseValues([]byte("components: []\n"))
+
+		assert.ErrorContains(t, err, "failed to parse values")
+	})
+}
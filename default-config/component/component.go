@@ -0,0 +1,57 @@
+package component
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Resource is the resource of the Component CRs managed by the k8s-component-operator.
+var Resource = schema.GroupVersionResource{Group: "k8s.cloudogu.com", Version: "v1", Resource: "components"}
+
+const (
+	// StatusInstalled is the status of a component whose Helm release has been installed or upgraded.
+	StatusInstalled = "installed"
+	// HealthAvailable is the health of a component whose workloads are ready.
+	HealthAvailable = "available"
+)
+
+// Status returns the installation status of the component, e.g. "installing" or "installed".
+func Status(component *unstructured.Unstructured) string {
+	status, _, _ := unstructured.NestedString(component.Object, "status", "status")
+	return status
+}
+
+// Health returns the health of the component, e.g. "available" or "unavailable".
+// It is empty if the k8s-component-operator does not report the health of components.
+func Health(component *unstructured.Unstructured) string {
+	health, _, _ := unstructured.NestedString(component.Object, "status", "health")
+	return health
+}
+
+// Conditions formats the status conditions of the component as "Type=Status (Reason): Message".
+func Conditions(component *unstructured.Unstructured) []string {
+	rawConditions, _, _ := unstructured.NestedSlice(component.Object, "status", "conditions")
+
+	var result []string
+	for _, rawCondition := range rawConditions {
+		condition, ok := rawCondition.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "%v=%v", condition["type"], condition["status"])
+		if reason, ok := condition["reason"].(string); ok && reason != "" {
+			fmt.Fprintf(&b, " (%s)", reason)
+		}
+		if message, ok := condition["message"].(string); ok && message != "" {
+			fmt.Fprintf(&b, ": %s", message)
+		}
+		result = append(result, b.String())
+	}
+
+	return result
+}
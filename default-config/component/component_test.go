@@ -0,0 +1,36 @@
+package component
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestAccessors(t *testing.T) {
+	t.Run("should read status", func(t *testing.T) {
+		obj := &unstructured.Unstructured{Object: map[string]any{
+			"status": map[string]any{
+				"status": "installed",
+				"health": "unavailable",
+				"conditions": []any{
+					map[string]any{"type": "Ready", "status": "False", "reason": "PodsNotReady", "message": "0/1 pods ready"},
+					map[string]any{"type": "Installed", "status": "True"},
+					"invalid",
+				},
+			},
+		}}
+
+		assert.Equal(t, "installed", Status(obj))
+		assert.Equal(t, "unavailable", Health(obj))
+		assert.Equal(t, []string{"Ready=False (PodsNotReady): 0/1 pods ready", "Installed=True"}, Conditions(obj))
+	})
+
+	t.Run("should return empty values without status", func(t *testing.T) {
+		obj := &unstructured.Unstructured{Object: map[string]any{}}
+
+		assert.Empty(t, Status(obj))
+		assert.Empty(t, Health(obj))
+		assert.Empty(t, Conditions(obj))
+	})
+}
@@ -0,0 +1,144 @@
+package component
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+type componentClient interface {
+	List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+}
+
+// Gate waits until the given components are installed and healthy,
+// so that the defaults are applied to an ecosystem whose operators are running.
+type Gate struct {
+	client     componentClient
+	components []string
+}
+
+func NewGate(client componentClient, components []string) *Gate {
+	return &Gate{client: client, components: components}
+}
+
+// WaitUntilReady watches the components until all of them are ready or ctx is done.
+// On timeout, the error reports the state of each component that is not ready.
+func (g *Gate) WaitUntilReady(ctx context.Context) error {
+	slog.Info("waiting for components to be ready...", "components", g.components)
+
+	for {
+		list, err := g.client.List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to list components: %w", err)
+		}
+
+		found := map[string]*unstructured.Unstructured{}
+		for i := range list.Items {
+			found[list.Items[i].GetName()] = &list.Items[i]
+		}
+
+		if g.allReady(found) {
+			slog.Info("...all components are ready")
+			return nil
+		}
+
+		watcher, err := g.client.Watch(ctx, metav1.ListOptions{ResourceVersion: list.GetResourceVersion()})
+		if err != nil {
+			return fmt.Errorf("failed to watch components: %w", err)
+		}
+
+		done, err := g.watch(ctx, watcher, found)
+		watcher.Stop()
+		if done || err != nil {
+			return err
+		}
+		// the watch has been closed by the API server, it is resumed with a fresh list
+	}
+}
+
+// watch returns true if all components are ready and false if the watch has been closed.
+func (g *Gate) watch(ctx context.Context, watcher watch.Interface, found map[string]*unstructured.Unstructured) (bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return false, fmt.Errorf("components are not ready: %s: %w", g.describeNotReady(found), ctx.Err())
+		case ev, ok := <-watcher.ResultChan():
+			if !ok || ev.Type == watch.Error {
+				return false, nil
+			}
+
+			obj, isComponent := ev.Object.(*unstructured.Unstructured)
+			if !isComponent || !slices.Contains(g.components, obj.GetName()) {
+				continue
+			}
+
+			wasReady := isReady(found[obj.GetName()])
+			if ev.Type == watch.Deleted {
+				delete(found, obj.GetName())
+			} else {
+				found[obj.GetName()] = obj
+			}
+
+			if !wasReady && isReady(found[obj.GetName()]) {
+				slog.Info("component is ready", "component", obj.GetName(), "version", installedVersion(obj))
+			}
+
+			if g.allReady(found) {
+				slog.Info("...all components are ready")
+				return true, nil
+			}
+		}
+	}
+}
+
+func (g *Gate) allReady(found map[string]*unstructured.Unstructured) bool {
+	for _, name := range g.components {
+		if !isReady(found[name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (g *Gate) describeNotReady(found map[string]*unstructured.Unstructured) string {
+	var descriptions []string
+	for _, name := range g.components {
+		obj := found[name]
+		switch {
+		case obj == nil:
+			descriptions = append(descriptions, name+" (not found)")
+		case !isReady(obj):
+			description := fmt.Sprintf("%s (status %q, health %q", name, Status(obj), Health(obj))
+			if conds := Conditions(obj); len(conds) > 0 {
+				description += ", conditions " + strings.Join(conds, "; ")
+			}
+			descriptions = append(descriptions, description+")")
+		}
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+// isReady returns true if the component is installed and available.
+// Components without health are ready once installed, because older k8s-component-operators do not report the health.
+func isReady(obj *unstructured.Unstructured) bool {
+	if obj == nil || obj.GetDeletionTimestamp() != nil || Status(obj) != StatusInstalled {
+		return false
+	}
+
+	health := Health(obj)
+	return health == "" || health == HealthAvailable
+}
+
+func installedVersion(obj *unstructured.Unstructured) string {
+	version, _, _ := unstructured.NestedString(obj.Object, "status", "installedVersion")
+	return version
+}
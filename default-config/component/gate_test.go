@@ -0,0 +1,138 @@
+package component
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "ecosystem"
+
+func newComponent(name string, status string, health string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "k8s.cloudogu.com/v1",
+		"kind":       "Component",
+		"metadata": map[string]any{
+			"name":      name,
+			"namespace": testNamespace,
+		},
+		"status": map[string]any{
+			"status":           status,
+			"health":           health,
+			"installedVersion": "1.0.0",
+		},
+	}}
+}
+
+func newFakeClient(objects ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{Resource: "ComponentList"}, objects...)
+}
+
+// onWatch calls f once the gate watches the components.
+func onWatch(client *fake.FakeDynamicClient, f func()) {
+	started := make(chan struct{})
+	client.PrependWatchReactor("components", func(action k8stesting.Action) (bool, watch.Interface, error) {
+		select {
+		case <-started:
+		default:
+			close(started)
+		}
+		return false, nil, nil
+	})
+	go func() {
+		<-started
+		f()
+	}()
+}
+
+func TestGate_WaitUntilReady(t *testing.T) {
+	t.Run("should succeed if components are ready", func(t *testing.T) {
+		client := newFakeClient(
+			newComponent("k8s-dogu-operator", StatusInstalled, HealthAvailable),
+			newComponent("k8s-service-discovery", StatusInstalled, ""),
+			newComponent("k8s-ces-gateway", "installing", ""),
+		)
+		gate := NewGate(client.Resource(Resource).Namespace(testNamespace), []string{"k8s-dogu-operator", "k8s-service-discovery"})
+
+		err := gate.WaitUntilReady(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should wait until components are ready", func(t *testing.T) {
+		client := newFakeClient(newComponent("k8s-dogu-operator", "installing", "unavailable"))
+		components := client.Resource(Resource).Namespace(testNamespace)
+		onWatch(client, func() {
+			_, err := components.Update(context.Background(), newComponent("k8s-dogu-operator", StatusInstalled, "unavailable"), metav1.UpdateOptions{})
+			assert.NoError(t, err)
+			_, err = components.Create(context.Background(), newComponent("k8s-service-discovery", StatusInstalled, HealthAvailable), metav1.CreateOptions{})
+			assert.NoError(t, err)
+			_, err = components.Update(context.Background(), newComponent("k8s-dogu-operator", StatusInstalled, HealthAvailable), metav1.UpdateOptions{})
+			assert.NoError(t, err)
+		})
+		gate := NewGate(components, []string{"k8s-dogu-operator", "k8s-service-discovery"})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		err := gate.WaitUntilReady(ctx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should report components that are not ready on timeout", func(t *testing.T) {
+		notReady := newComponent("k8s-dogu-operator", "installing", "unavailable")
+		require.NoError(t, unstructured.SetNestedSlice(notReady.Object, []any{
+			map[string]any{"type": "Ready", "status": "False", "reason": "PodsNotReady"},
+		}, "status", "conditions"))
+		client := newFakeClient(notReady, newComponent("k8s-ces-gateway", StatusInstalled, HealthAvailable))
+		gate := NewGate(client.Resource(Resource).Namespace(testNamespace), []string{"k8s-dogu-operator", "k8s-ces-gateway", "k8s-service-discovery"})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := gate.WaitUntilReady(ctx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, `components are not ready: k8s-dogu-operator (status "installing", health "unavailable", conditions Ready=False (PodsNotReady)), k8s-service-discovery (not found)`)
+	})
+
+	t.Run("should not accept deleted component", func(t *testing.T) {
+		deleted := newComponent("k8s-dogu-operator", StatusInstalled, HealthAvailable)
+		now := metav1.Now()
+		deleted.SetDeletionTimestamp(&now)
+		client := newFakeClient(deleted)
+		gate := NewGate(client.Resource(Resource).Namespace(testNamespace), []string{"k8s-dogu-operator"})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		err := gate.WaitUntilReady(ctx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should fail on error listing components", func(t *testing.T) {
+		client := newFakeClient()
+		client.PrependReactor("list", "components", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		gate := NewGate(client.Resource(Resource).Namespace(testNamespace), []string{"k8s-dogu-operator"})
+
+		err := gate.WaitUntilReady(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to list components")
+	})
+}
@@ -5,18 +5,28 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/cloudogu/ecosystem-core/default-config/tracing"
 )
 
-const passwordLength = 20
+const (
+	passwordLength       = 20
+	ldapDogu             = "ldap"
+	ldapAdminPasswordKey = "admin_password"
+	useInternalIPKey     = "k8s/use_internal_ip"
+	internalIPKey        = "k8s/internal_ip"
+)
 
 var globalDefaults = map[string]string{
-	"domain":              "ces.localdomain",
-	"admin_group":         "cesAdmin",
-	"mail_address":        "",
-	"certificate/type":    "",
-	"default_dogu":        "cas",
-	"k8s/use_internal_ip": "false",
-	"k8s/internal_ip":     "",
+	"domain":           "ces.localdomain",
+	"admin_group":      "cesAdmin",
+	"mail_address":     "",
+	"certificate/type": "",
+	"default_dogu":     "cas",
+	useInternalIPKey:   "false",
+	internalIPKey:      "",
 
 	"password-policy/must_contain_capital_letter":    "true",
 	"password-policy/must_contain_lower_case_letter": "true",
@@ -62,9 +72,40 @@ type DefaultConfigApplier struct {
 	globalConfigWriter globalConfigWriter
 	doguConfigWriter   doguConfigWriter
 	passwordGenerator  passwordGenerator
+	secretClient       secretClient
 	initialDomain      string
 	initialFQDN        string
-	useLopIdp          bool
+	initialInternalIP  string
+	lopIdp             *LopIdp
+	profile            Profile
+	externalLdap       *ExternalLdap
+	smtpRelay          *SMTPRelay
+	proxy              *Proxy
+	extraDefaults      *ExtraDefaults
+	blueprint          *Blueprint
+	timeouts           Timeouts
+	summary            *report.Summary
+}
+
+// ApplierOptions configure the defaults of the DefaultConfigApplier. Nil pointers disable the respective feature.
+type ApplierOptions struct {
+	// InitialDomain, InitialFQDN and InitialInternalIP are written to the global config if set.
+	InitialDomain     string
+	InitialFQDN       string
+	InitialInternalIP string
+	// LopIdp replaces the dogu defaults of the ldap dogu with those of the LOP IdP.
+	LopIdp *LopIdp
+	// ExternalLdap replaces the dogu defaults of the ldap dogu with those of an external directory.
+	ExternalLdap  *ExternalLdap
+	Profile       Profile
+	SMTPRelay     *SMTPRelay
+	Proxy         *Proxy
+	ExtraDefaults *ExtraDefaults
+	// Blueprint takes precedence over all defaults.
+	Blueprint *Blueprint
+	// InitialAdmin stores the generated credentials of the LDAP admin in a Secret.
+	InitialAdmin *InitialAdmin
+	Timeouts     Timeouts
 }
 
 func NewDefaultConfigApplier(
@@ -72,54 +113,247 @@ func NewDefaultConfigApplier(
 	doguConfigRepo doguConfigRepo,
 	sensitiveDoguConfigRepo doguConfigRepo,
 	secretClient secretClient,
-	initialDomain string,
-	initialFQDN string,
-	useLopIdp bool,
+	opts ApplierOptions,
+	summary *report.Summary,
+	recorder *event.Recorder,
 ) *DefaultConfigApplier {
-	gcw := newCesGlobalConfigWriter(globalConfigRepo, secretClient)
+	gcw := newCesGlobalConfigWriter(globalConfigRepo, secretClient, opts.Timeouts.Certificate, opts.Profile.RejectSelfSignedCertificate, summary, recorder)
 
 	dcw := &cesDoguConfigWriter{
 		doguConfigRepo:          doguConfigRepo,
 		sensitiveDoguConfigRepo: sensitiveDoguConfigRepo,
+		summary:                 summary,
+		recorder:                recorder,
+		initialAdmin:            newInitialAdminWriter(secretClient, opts.InitialAdmin, recorder),
 	}
 
 	return &DefaultConfigApplier{
 		globalConfigWriter: gcw,
 		doguConfigWriter:   dcw,
 		passwordGenerator:  &adminPasswordGenerator{},
-		initialDomain:      initialDomain,
-		initialFQDN:        initialFQDN,
-		useLopIdp:          useLopIdp,
+		secretClient:       secretClient,
+		initialDomain:      opts.InitialDomain,
+		initialFQDN:        opts.InitialFQDN,
+		initialInternalIP:  opts.InitialInternalIP,
+		lopIdp:             opts.LopIdp,
+		profile:            opts.Profile,
+		externalLdap:       opts.ExternalLdap,
+		smtpRelay:          opts.SMTPRelay,
+		proxy:              opts.Proxy,
+		extraDefaults:      opts.ExtraDefaults,
+		blueprint:          opts.Blueprint,
+		timeouts:           opts.Timeouts,
+		summary:            summary,
 	}
 }
 
 func (dca *DefaultConfigApplier) ApplyDefaultConfig(ctx context.Context) error {
-	// clone to avoid side effects
-	globalConfig := maps.Clone(globalDefaults)
+	if dca.profile.Name != "" {
+		slog.Info("Applying the defaults of the profile", "profile", dca.profile.Name)
+	}
+
+	dca.summary.EnterPhase(report.PhaseGlobalConfig)
+	err := withTimeout(ctx, dca.timeouts.GlobalConfig, func(ctx context.Context) error {
+		ctx, span := tracing.Start(ctx, string(report.PhaseGlobalConfig))
+		globalConfig, err := dca.globalDefaults(ctx)
+		if err == nil {
+			err = dca.globalConfigWriter.applyDefaultGlobalConfig(ctx, globalConfig)
+		}
+		tracing.End(span, err)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply default global config: %w", err)
+	}
+
+	dca.summary.EnterPhase(report.PhaseDoguConfig)
+	err = withTimeout(ctx, dca.timeouts.DoguConfig, func(ctx context.Context) error {
+		ctx, span := tracing.Start(ctx, string(report.PhaseDoguConfig))
+		doguConfig, sensitiveDoguConfig, err := dca.doguDefaults(ctx)
+		if err == nil {
+			err = dca.doguConfigWriter.applyDefaultDoguConfig(ctx, doguConfig, sensitiveDoguConfig)
+		}
+		tracing.End(span, err)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply default dogu config: %w", err)
+	}
+
+	return nil
+}
+
+// globalDefaults returns the global defaults of all layers and the credentials of the proxy overridden by the blueprint.
+func (dca *DefaultConfigApplier) globalDefaults(ctx context.Context) (map[string]string, error) {
+	globalConfig, _ := mergeGlobalLayers(dca.globalLayers())
+	if dca.proxy != nil {
+		// the credentials are not part of a layer, so that they never appear in the plan
+		credentials, err := dca.proxy.credentialsConfig(ctx, dca.secretClient)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(globalConfig, credentials)
+	}
+	// the blueprint operator enforces the config of the blueprint anyway, other values would be replaced later
+	dca.blueprint.applyGlobal(globalConfig)
+
+	return globalConfig, nil
+}
+
+// globalLayers returns the layers of the global defaults in the order of their precedence.
+func (dca *DefaultConfigApplier) globalLayers() []globalLayer {
+	layers := []globalLayer{
+		{name: LayerBuiltin, config: globalDefaults},
+		{name: LayerProfile, config: dca.profile.GlobalConfig},
+	}
+	if dca.smtpRelay != nil && dca.smtpRelay.MailAddress != "" {
+		layers = append(layers, globalLayer{name: LayerSMTPRelay, config: map[string]string{mailAddressKey: dca.smtpRelay.MailAddress}})
+	}
+	if dca.proxy != nil {
+		layers = append(layers, globalLayer{name: LayerProxy, config: dca.proxy.globalConfig()})
+	}
+	if dca.extraDefaults != nil {
+		layers = append(layers, globalLayer{name: LayerChart, config: dca.extraDefaults.GlobalConfig})
+	}
+
+	initialConfig := map[string]string{}
 	if dca.initialDomain != "" {
-		globalConfig["domain"] = dca.initialDomain
+		initialConfig["domain"] = dca.initialDomain
 	}
 	if dca.initialFQDN != "" {
-		globalConfig["fqdn"] = dca.initialFQDN
+		initialConfig["fqdn"] = dca.initialFQDN
+	}
+	if dca.initialInternalIP != "" {
+		initialConfig[internalIPKey] = dca.initialInternalIP
 	}
 
-	if err := dca.globalConfigWriter.applyDefaultGlobalConfig(ctx, globalConfig); err != nil {
-		return fmt.Errorf("failed to apply default global config: %w", err)
+	return append(layers, globalLayer{name: LayerInitial, config: initialConfig})
+}
+
+// doguLayers returns the layers of the dogu defaults in the order of their precedence. The first layer contains the
+// defaults of the LOP IdP, the external LDAP or the ldap dogu.
+func (dca *DefaultConfigApplier) doguLayers() []doguLayer {
+	var layers []doguLayer
+	switch {
+	case dca.lopIdp != nil:
+		layers = append(layers, doguLayer{name: LayerLopIdp, config: lopIdpDoguDefaults(dca.lopIdp, dca.initialFQDN)})
+	case dca.externalLdap != nil:
+		layers = append(layers, doguLayer{name: LayerExternalLdap, config: externalLdapDoguDefaults(dca.externalLdap)})
+	default:
+		layers = append(layers, doguLayer{name: LayerBuiltin, config: doguDefaults})
 	}
 
-	if dca.useLopIdp {
-		slog.Info("Not applying default dogu configs, because LOP IdP is used...")
-		return nil
+	layers = append(layers, doguLayer{name: LayerProfile, config: dca.profile.DoguConfig})
+	if dca.smtpRelay != nil {
+		layers = append(layers, doguLayer{name: LayerSMTPRelay, config: map[string]map[string]string{postfixDogu: dca.smtpRelay.postfixConfig()}})
 	}
+	if dca.extraDefaults != nil {
+		layers = append(layers, doguLayer{name: LayerChart, config: dca.extraDefaults.DoguConfig})
+	}
+
+	return layers
+}
 
-	sensitiveDoguDefaults := map[string]map[string]string{
-		"ldap": {
-			"admin_password": dca.passwordGenerator.generatePassword(passwordLength),
+// doguDefaults returns the dogu defaults of all layers overridden by the blueprint and the sensitive dogu defaults
+// overridden by the blueprint.
+func (dca *DefaultConfigApplier) doguDefaults(ctx context.Context) (map[string]map[string]string, map[string]map[string]string, error) {
+	sensitiveDefaults, err := dca.sensitiveAuthenticationDefaults(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the merged maps are copies, which can be modified
+	doguConfig, _ := mergeDoguLayers(dca.doguLayers())
+	if dca.lopIdp != nil && dca.lopIdp.ClientSecret.Name != "" {
+		if err = dca.applyLopIdp(ctx, doguConfig, sensitiveDefaults); err != nil {
+			return nil, nil, err
+		}
+	}
+	if dca.externalLdap != nil {
+		if err = dca.applyExternalLdap(ctx, doguConfig, sensitiveDefaults); err != nil {
+			return nil, nil, err
+		}
+	}
+	if dca.smtpRelay != nil {
+		if err = dca.applySMTPRelay(ctx, sensitiveDefaults); err != nil {
+			return nil, nil, err
+		}
+	}
+	dca.blueprint.applyDogu(doguConfig, sensitiveDefaults)
+
+	return doguConfig, sensitiveDefaults, nil
+}
+
+// sensitiveAuthenticationDefaults returns the sensitive dogu defaults for the LOP IdP, the external LDAP or the ldap dogu.
+func (dca *DefaultConfigApplier) sensitiveAuthenticationDefaults(ctx context.Context) (map[string]map[string]string, error) {
+	if dca.lopIdp != nil {
+		// the client credentials are set by applyLopIdp
+		slog.Info("Applying the dogu defaults of the LOP IdP profile...")
+		return map[string]map[string]string{}, nil
+	}
+
+	if dca.externalLdap != nil {
+		// the bind credentials are set by applyExternalLdap
+		slog.Info("Applying the dogu defaults for the external LDAP...", "host", dca.externalLdap.Host)
+		return map[string]map[string]string{}, nil
+	}
+
+	return map[string]map[string]string{
+		ldapDogu: {
+			ldapAdminPasswordKey: dca.passwordGenerator.generatePassword(passwordLength),
 		},
+	}, nil
+}
+
+// applyLopIdp sets the client credentials of CAS at the LOP IdP. CAS reads the client ID from its dogu config and only
+// the client secret from its sensitive config.
+func (dca *DefaultConfigApplier) applyLopIdp(ctx context.Context, doguConfig, sensitiveDoguConfig map[string]map[string]string) error {
+	clientID, clientSecret, err := dca.lopIdp.clientCredentials(ctx, dca.secretClient)
+	if err != nil {
+		return err
 	}
 
-	if err := dca.doguConfigWriter.applyDefaultDoguConfig(ctx, doguDefaults, sensitiveDoguDefaults); err != nil {
-		return fmt.Errorf("failed to apply default dogu config: %w", err)
+	if doguConfig[casDogu] == nil {
+		doguConfig[casDogu] = map[string]string{}
+	}
+	doguConfig[casDogu][casOidcClientIDKey] = clientID
+	sensitiveDoguConfig[casDogu] = map[string]string{casOidcClientSecretKey: clientSecret}
+
+	return nil
+}
+
+// applyExternalLdap sets the bind credentials of the external LDAP. CAS reads the bind DN from its dogu config and only
+// the password from its sensitive config.
+func (dca *DefaultConfigApplier) applyExternalLdap(ctx context.Context, doguConfig, sensitiveDoguConfig map[string]map[string]string) error {
+	bindDN, password, err := dca.externalLdap.bindCredentials(ctx, dca.secretClient)
+	if err != nil {
+		return err
+	}
+
+	if doguConfig[casDogu] == nil {
+		doguConfig[casDogu] = map[string]string{}
+	}
+	doguConfig[casDogu][casLdapConnectionDNKey] = bindDN
+	sensitiveDoguConfig[casDogu] = map[string]string{casLdapPasswordKey: password}
+
+	return nil
+}
+
+// applySMTPRelay checks the relay if configured and sets its SASL credentials. The postfix config of the relay is a
+// layer of the dogu defaults.
+func (dca *DefaultConfigApplier) applySMTPRelay(ctx context.Context, sensitiveDoguConfig map[string]map[string]string) error {
+	if dca.smtpRelay.Check {
+		if err := dca.smtpRelay.checkConnectivity(ctx); err != nil {
+			return err
+		}
+	}
+
+	sensitivePostfixConfig, err := dca.smtpRelay.postfixSensitiveConfig(ctx, dca.secretClient)
+	if err != nil {
+		return err
+	}
+	if sensitivePostfixConfig != nil {
+		sensitiveDoguConfig[postfixDogu] = sensitivePostfixConfig
 	}
 
 	return nil
@@ -2,13 +2,41 @@ package config
 
 import (
 	"context"
+	"fmt"
 	"maps"
 	"testing"
+	"time"
 
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
+func newTestRecorder() (*event.Recorder, *fake.Clientset) {
+	clientSet := fake.NewClientset()
+	return event.NewRecorder(clientSet.CoreV1().Events("ecosystem"), "ecosystem", "default-config-abcde"), clientSet
+}
+
+// recordedEvents returns the created events in the order of their creation.
+func recordedEvents(t *testing.T, clientSet *fake.Clientset) []string {
+	t.Helper()
+	var events []string
+	for _, action := range clientSet.Actions() {
+		if create, ok := action.(k8stesting.CreateAction); ok {
+			e, ok := create.GetObject().(*corev1.Event)
+			require.True(t, ok)
+			events = append(events, fmt.Sprintf("%s %s/%s: %s", e.Reason, e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Message))
+		}
+	}
+	return events
+}
+
 func TestDefaultConfigApplier_ApplyDefaultConfig(t *testing.T) {
 	testCtx := context.Background()
 	t.Run("should apply default config", func(t *testing.T) {
@@ -98,6 +126,146 @@ func TestDefaultConfigApplier_ApplyDefaultConfig(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("should apply the extra defaults of the chart over the profile and below the initial domain", func(t *testing.T) {
+		mockPg := newMockPasswordGenerator(t)
+		mockPg.EXPECT().generatePassword(passwordLength).Return("password")
+
+		expectedGlobalConfig := maps.Clone(globalDefaults)
+		expectedGlobalConfig["domain"] = "example.com"
+		expectedGlobalConfig["password-policy/min_length"] = "20"
+		expectedGlobalConfig["proxy/enabled"] = "true"
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, expectedGlobalConfig).Return(nil)
+
+		expectedDoguConfig := map[string]map[string]string{
+			"postfix": maps.Clone(doguDefaults["postfix"]),
+			"ldap":    maps.Clone(doguDefaults["ldap"]),
+			"cas":     maps.Clone(doguDefaults["cas"]),
+			"redmine": {"logging/root": "INFO"},
+		}
+		expectedDoguConfig["ldap"]["admin_mail"] = "admin@example.com"
+		expectedSensitiveConfig := map[string]map[string]string{"ldap": {"admin_password": "password"}}
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, expectedDoguConfig, expectedSensitiveConfig).Return(nil)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  mockPg,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			initialDomain:      "example.com",
+			profile:            Profile{Name: "production", GlobalConfig: map[string]string{"password-policy/min_length": "16"}},
+			extraDefaults: &ExtraDefaults{
+				GlobalConfig: map[string]string{"domain": "chart.example.com", "password-policy/min_length": "20", "proxy/enabled": "true"},
+				DoguConfig: map[string]map[string]string{
+					"ldap":    {"admin_mail": "admin@example.com"},
+					"redmine": {"logging/root": "INFO"},
+				},
+			},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should apply the proxy with the credentials of the secret", func(t *testing.T) {
+		mockPg := newMockPasswordGenerator(t)
+		mockPg.EXPECT().generatePassword(passwordLength).Return("password")
+
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "proxy-credentials", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"username": []byte("ces"), "password": []byte("secret")},
+		}, nil)
+
+		expectedGlobalConfig := maps.Clone(globalDefaults)
+		expectedGlobalConfig["proxy/enabled"] = "true"
+		expectedGlobalConfig["proxy/server"] = "proxy.example.com"
+		expectedGlobalConfig["proxy/port"] = "3128"
+		expectedGlobalConfig["proxy/no_proxy_hosts"] = "localhost"
+		expectedGlobalConfig["proxy/username"] = "ces"
+		expectedGlobalConfig["proxy/password"] = "secret"
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, expectedGlobalConfig).Return(nil)
+
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, doguDefaults, map[string]map[string]string{"ldap": {"admin_password": "password"}}).Return(nil)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  mockPg,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			secretClient:       mockSecretClient,
+			proxy: &Proxy{
+				Server:            "proxy.example.com",
+				Port:              3128,
+				NoProxyHosts:      []string{"localhost"},
+				CredentialsSecret: SecretRef{Name: "proxy-credentials", UsernameKey: "username", PasswordKey: "password"},
+			},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail to read the credentials of the proxy", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "proxy-credentials", metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  newMockPasswordGenerator(t),
+			globalConfigWriter: newMockGlobalConfigWriter(t),
+			doguConfigWriter:   newMockDoguConfigWriter(t),
+			secretClient:       mockSecretClient,
+			proxy:              &Proxy{Server: "proxy.example.com", Port: 3128, CredentialsSecret: SecretRef{Name: "proxy-credentials"}},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to apply default global config: failed to read proxy credentials")
+	})
+
+	t.Run("should apply the blueprint config over the initial fqdn and the defaults", func(t *testing.T) {
+		mockPg := newMockPasswordGenerator(t)
+		mockPg.EXPECT().generatePassword(passwordLength).Return("password")
+
+		expectedGlobalConfig := maps.Clone(globalDefaults)
+		expectedGlobalConfig["fqdn"] = "blueprint.example.com"
+		delete(expectedGlobalConfig, "admin_group")
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, expectedGlobalConfig).Return(nil)
+
+		expectedDoguConfig := map[string]map[string]string{
+			"postfix": {"relayhost": "mail.example.com"},
+			"ldap":    maps.Clone(doguDefaults["ldap"]),
+			"cas":     maps.Clone(doguDefaults["cas"]),
+		}
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, expectedDoguConfig, map[string]map[string]string{}).Return(nil)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  mockPg,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			initialFQDN:        "instance.example.com",
+			blueprint: newBlueprint("blueprint", blueprintConfig{
+				Global: []blueprintConfigEntry{
+					{Key: "fqdn", Value: ptr("blueprint.example.com")},
+					{Key: "admin_group", Absent: true},
+				},
+				Dogus: map[string][]blueprintConfigEntry{
+					"postfix": {{Key: "relayhost", Value: ptr("mail.example.com")}},
+					"ldap":    {{Key: "admin_password", Sensitive: true}},
+				},
+			}),
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+	})
+
 	t.Run("should fail to apply default global config", func(t *testing.T) {
 		mockPg := newMockPasswordGenerator(t)
 
@@ -148,16 +316,81 @@ func TestDefaultConfigApplier_ApplyDefaultConfig(t *testing.T) {
 		assert.ErrorContains(t, err, "failed to apply default dogu config:")
 	})
 
-	t.Run("should not apply dogu configs when lop-idp is in use", func(t *testing.T) {
+	t.Run("should limit phases by timeouts", func(t *testing.T) {
+		mockPg := newMockPasswordGenerator(t)
+		mockPg.EXPECT().generatePassword(passwordLength).Return("password")
+
+		hasDeadline := func(ctx context.Context) bool {
+			_, ok := ctx.Deadline()
+			return ok
+		}
+
 		mockGcw := newMockGlobalConfigWriter(t)
-		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, globalDefaults).Return(nil)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(mock.MatchedBy(hasDeadline), globalDefaults).Return(nil)
+
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(mock.MatchedBy(hasDeadline), doguDefaults, mock.Anything).Return(nil)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  mockPg,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			timeouts:           Timeouts{GlobalConfig: time.Minute, DoguConfig: time.Minute},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail with deadline exceeded when global config phase times out", func(t *testing.T) {
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(mock.Anything, globalDefaults).RunAndReturn(func(ctx context.Context, _ map[string]string) error {
+			<-ctx.Done()
+			// the registry does not wrap the context error
+			return assert.AnError
+		})
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  newMockPasswordGenerator(t),
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   newMockDoguConfigWriter(t),
+			timeouts:           Timeouts{GlobalConfig: time.Millisecond},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to apply default global config:")
+	})
+
+	t.Run("should apply the dogu configs of the lop-idp profile without generating the ldap password", func(t *testing.T) {
+		expectedGlobalConfig := maps.Clone(globalDefaults)
+		expectedGlobalConfig["domain"] = "example.com"
+		expectedGlobalConfig["fqdn"] = "ces.example.com"
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, expectedGlobalConfig).Return(nil)
 
 		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, mock.Anything, map[string]map[string]string{}).
+			RunAndReturn(func(_ context.Context, doguConfig map[string]map[string]string, _ map[string]map[string]string) error {
+				assert.NotContains(t, doguConfig, "ldap")
+				assert.Equal(t, "n/a", doguConfig["postfix"]["relayhost"])
+				assert.Equal(t, "true", doguConfig["cas"]["oidc/enabled"])
+				assert.Equal(t, "https://ces.example.com/lop-idp/.well-known/openid-configuration", doguConfig["cas"]["oidc/discovery_uri"])
+				assert.NotContains(t, doguConfig["cas"], "ldap/ds_type")
+				return nil
+			})
 
 		dca := &DefaultConfigApplier{
+			passwordGenerator:  newMockPasswordGenerator(t),
 			globalConfigWriter: mockGcw,
 			doguConfigWriter:   mockDcw,
-			useLopIdp:          true,
+			initialDomain:      "example.com",
+			initialFQDN:        "ces.example.com",
+			lopIdp:             &LopIdp{},
 		}
 
 		err := dca.ApplyDefaultConfig(testCtx)
@@ -165,6 +398,218 @@ func TestDefaultConfigApplier_ApplyDefaultConfig(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("should apply the client credentials and the discovery uri of the lop-idp", func(t *testing.T) {
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, mock.Anything).Return(nil)
+
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "lop-idp-cas", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"clientId": []byte("ces-cas"), "clientSecret": []byte("secret")},
+		}, nil)
+
+		expectedSensitiveConfig := map[string]map[string]string{
+			"cas": {"oidc/client_secret": "secret"},
+		}
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, mock.Anything, expectedSensitiveConfig).
+			RunAndReturn(func(_ context.Context, doguConfig map[string]map[string]string, _ map[string]map[string]string) error {
+				assert.Equal(t, "ces-cas", doguConfig["cas"]["oidc/client_id"])
+				assert.Equal(t, "https://idp.example.com/.well-known/openid-configuration", doguConfig["cas"]["oidc/discovery_uri"])
+				assert.NotContains(t, doguConfig["cas"], "oidc/client_secret")
+				return nil
+			})
+
+		lopIdp, err := ParseLopIdp(`{"discoveryUri": "https://idp.example.com/.well-known/openid-configuration", "clientSecret": {"name": "lop-idp-cas"}}`)
+		require.NoError(t, err)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  newMockPasswordGenerator(t),
+			secretClient:       mockSecretClient,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			initialDomain:      "example.com",
+			initialFQDN:        "ces.example.com",
+			lopIdp:             lopIdp,
+		}
+
+		err = dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should apply the defaults overridden by the profile", func(t *testing.T) {
+		mockPg := newMockPasswordGenerator(t)
+		mockPg.EXPECT().generatePassword(passwordLength).Return("password")
+
+		expectedGlobalConfig := maps.Clone(globalDefaults)
+		expectedGlobalConfig["password-policy/min_length"] = "8"
+		expectedGlobalConfig["domain"] = "example.com"
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, expectedGlobalConfig).Return(nil)
+
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, mock.Anything, mock.Anything).
+			RunAndReturn(func(_ context.Context, doguConfig map[string]map[string]string, _ map[string]map[string]string) error {
+				assert.Equal(t, "mail.example.com", doguConfig["postfix"]["relayhost"])
+				assert.Equal(t, "admin", doguConfig["ldap"]["admin_username"])
+				assert.Equal(t, "value", doguConfig["redmine"]["key"])
+				return nil
+			})
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  mockPg,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			initialDomain:      "example.com",
+			profile: Profile{
+				Name:         "custom",
+				GlobalConfig: map[string]string{"password-policy/min_length": "8", "domain": "profile.example.com"},
+				DoguConfig: map[string]map[string]string{
+					"postfix": {"relayhost": "mail.example.com"},
+					"redmine": {"key": "value"},
+				},
+			},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "n/a", doguDefaults["postfix"]["relayhost"], "built-in defaults must not be modified")
+	})
+
+	t.Run("should apply the cas config of the external ldap without generating the ldap password", func(t *testing.T) {
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, globalDefaults).Return(nil)
+
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "ldap-bind", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"username": []byte("CN=cas,DC=example,DC=com"), "password": []byte("secret")},
+		}, nil)
+
+		expectedSensitiveConfig := map[string]map[string]string{
+			"cas": {"ldap/password": "secret"},
+		}
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, mock.Anything, expectedSensitiveConfig).
+			RunAndReturn(func(_ context.Context, doguConfig map[string]map[string]string, _ map[string]map[string]string) error {
+				assert.NotContains(t, doguConfig, "ldap")
+				assert.Equal(t, "n/a", doguConfig["postfix"]["relayhost"])
+				assert.Equal(t, "external", doguConfig["cas"]["ldap/ds_type"])
+				assert.Equal(t, "dc.example.com", doguConfig["cas"]["ldap/host"])
+				assert.Equal(t, "636", doguConfig["cas"]["ldap/port"])
+				assert.Equal(t, "ssl", doguConfig["cas"]["ldap/encryption"])
+				assert.Equal(t, "DC=example,DC=com", doguConfig["cas"]["ldap/base_dn"])
+				assert.Equal(t, "CN=cas,DC=example,DC=com", doguConfig["cas"]["ldap/connection_dn"])
+				assert.Equal(t, "sAMAccountName", doguConfig["cas"]["ldap/attribute_id"])
+				return nil
+			})
+
+		externalLdap, err := ParseExternalLdap(`{"host": "dc.example.com", "encryption": "ssl", "baseDn": "DC=example,DC=com", "bindSecret": {"name": "ldap-bind"}}`)
+		require.NoError(t, err)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  newMockPasswordGenerator(t),
+			secretClient:       mockSecretClient,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			externalLdap:       externalLdap,
+		}
+
+		err = dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should apply the config of the smtp relay", func(t *testing.T) {
+		mockPg := newMockPasswordGenerator(t)
+		mockPg.EXPECT().generatePassword(passwordLength).Return("password")
+
+		expectedGlobalConfig := maps.Clone(globalDefaults)
+		expectedGlobalConfig["mail_address"] = "ces@example.com"
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, expectedGlobalConfig).Return(nil)
+
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "smtp-relay", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"username": []byte("ces"), "password": []byte("secret")},
+		}, nil)
+
+		expectedSensitiveConfig := map[string]map[string]string{
+			"ldap":    {"admin_password": "password"},
+			"postfix": {"sasl_username": "ces", "sasl_password": "secret"},
+		}
+		mockDcw := newMockDoguConfigWriter(t)
+		mockDcw.EXPECT().applyDefaultDoguConfig(testCtx, mock.Anything, expectedSensitiveConfig).
+			RunAndReturn(func(_ context.Context, doguConfig map[string]map[string]string, _ map[string]map[string]string) error {
+				assert.Equal(t, map[string]string{
+					"relayhost":               "[mail.example.com]:587",
+					"smtp_tls_security_level": "encrypt",
+					"sender_canonical":        "/.+/ ces@example.com",
+				}, doguConfig["postfix"])
+				assert.Equal(t, "embedded", doguConfig["cas"]["ldap/ds_type"])
+				return nil
+			})
+
+		smtpRelay, err := ParseSMTPRelay(`{"host": "mail.example.com", "tls": "encrypt", "senderAddress": "ces@example.com", "credentialsSecret": {"name": "smtp-relay"}}`)
+		require.NoError(t, err)
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  mockPg,
+			secretClient:       mockSecretClient,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   mockDcw,
+			smtpRelay:          smtpRelay,
+			profile:            Profile{DoguConfig: map[string]map[string]string{"postfix": {"relayhost": "profile.example.com"}}},
+		}
+
+		err = dca.ApplyDefaultConfig(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"relayhost": "n/a"}, doguDefaults["postfix"], "built-in defaults must not be modified")
+	})
+
+	t.Run("should fail if the smtp relay is not reachable", func(t *testing.T) {
+		mockPg := newMockPasswordGenerator(t)
+		mockPg.EXPECT().generatePassword(passwordLength).Return("password")
+
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, globalDefaults).Return(nil)
+
+		host, port := startSMTPStandIn(t, "554 no service")
+
+		dca := &DefaultConfigApplier{
+			passwordGenerator:  mockPg,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   newMockDoguConfigWriter(t),
+			smtpRelay:          &SMTPRelay{Host: host, Port: port, TLS: "may", Check: true},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSMTPRelayUnreachable)
+	})
+
+	t.Run("should fail if the bind secret of the external ldap cannot be read", func(t *testing.T) {
+		mockGcw := newMockGlobalConfigWriter(t)
+		mockGcw.EXPECT().applyDefaultGlobalConfig(testCtx, globalDefaults).Return(nil)
+
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "ldap-bind", metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		dca := &DefaultConfigApplier{
+			secretClient:       mockSecretClient,
+			globalConfigWriter: mockGcw,
+			doguConfigWriter:   newMockDoguConfigWriter(t),
+			externalLdap:       &ExternalLdap{Host: "dc.example.com", BindSecret: SecretRef{Name: "ldap-bind"}},
+		}
+
+		err := dca.ApplyDefaultConfig(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to apply default dogu config: failed to read bind credentials: failed to get secret ldap-bind")
+	})
 }
 
 func TestNewDefaultConfigApplier(t *testing.T) {
@@ -173,7 +618,25 @@ func TestNewDefaultConfigApplier(t *testing.T) {
 	mockSensitiveDoguRepo := newMockDoguConfigRepo(t)
 	mockSecClient := newMockSecretClient(t)
 
-	applier := NewDefaultConfigApplier(mockGlobalRepo, mockDoguRepo, mockSensitiveDoguRepo, mockSecClient, "example.com", "instance.example.com", false)
+	timeouts := Timeouts{GlobalConfig: time.Minute, DoguConfig: 2 * time.Minute, Certificate: 3 * time.Minute}
+
+	summary := report.NewSummary()
+	recorder, _ := newTestRecorder()
+
+	opts := ApplierOptions{
+		InitialDomain: "example.com",
+		InitialFQDN:   "instance.example.com",
+		Profile:       Profile{Name: "production", RejectSelfSignedCertificate: true},
+		ExternalLdap:  &ExternalLdap{Host: "dc.example.com"},
+		SMTPRelay:     &SMTPRelay{Host: "mail.example.com"},
+		Proxy:         &Proxy{Server: "proxy.example.com"},
+		ExtraDefaults: &ExtraDefaults{GlobalConfig: map[string]string{"proxy/enabled": "true"}},
+		Blueprint:     &Blueprint{Name: "blueprint"},
+		InitialAdmin:  &InitialAdmin{SecretName: "initial-admin", TTL: time.Hour},
+		Timeouts:      timeouts,
+	}
+
+	applier := NewDefaultConfigApplier(mockGlobalRepo, mockDoguRepo, mockSensitiveDoguRepo, mockSecClient, opts, summary, recorder)
 
 	require.NotNil(t, applier)
 	assert.NotNil(t, applier.passwordGenerator)
@@ -187,5 +650,24 @@ func TestNewDefaultConfigApplier(t *testing.T) {
 	assert.Equal(t, mockSensitiveDoguRepo, applier.doguConfigWriter.(*cesDoguConfigWriter).sensitiveDoguConfigRepo)
 	assert.Equal(t, "example.com", applier.initialDomain)
 	assert.Equal(t, "instance.example.com", applier.initialFQDN)
-	assert.False(t, applier.useLopIdp)
+	assert.Nil(t, applier.lopIdp)
+	assert.Equal(t, "production", applier.profile.Name)
+	assert.Equal(t, "dc.example.com", applier.externalLdap.Host)
+	assert.Equal(t, "mail.example.com", applier.smtpRelay.Host)
+	assert.Equal(t, map[string]string{"proxy/enabled": "true"}, applier.extraDefaults.GlobalConfig)
+	assert.Equal(t, "proxy.example.com", applier.proxy.Server)
+	assert.Equal(t, "blueprint", applier.blueprint.Name)
+	initialAdmin := applier.doguConfigWriter.(*cesDoguConfigWriter).initialAdmin
+	require.NotNil(t, initialAdmin)
+	assert.Equal(t, "initial-admin", initialAdmin.secretName)
+	assert.Equal(t, time.Hour, initialAdmin.ttl)
+	assert.Equal(t, mockSecClient, applier.secretClient)
+	assert.True(t, applier.globalConfigWriter.(*cesGlobalConfigWriter).rejectSelfSigned)
+	assert.Equal(t, timeouts, applier.timeouts)
+	assert.Equal(t, 3*time.Minute, applier.globalConfigWriter.(*cesGlobalConfigWriter).certificateTimeout)
+	assert.Same(t, summary, applier.summary)
+	assert.Same(t, summary, applier.globalConfigWriter.(*cesGlobalConfigWriter).summary)
+	assert.Same(t, summary, applier.doguConfigWriter.(*cesDoguConfigWriter).summary)
+	assert.Same(t, recorder, applier.globalConfigWriter.(*cesGlobalConfigWriter).recorder)
+	assert.Same(t, recorder, applier.doguConfigWriter.(*cesDoguConfigWriter).recorder)
 }
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// BlueprintResource is the resource of the Blueprint CRs of the k8s-blueprint-operator.
+var BlueprintResource = schema.GroupVersionResource{Group: "k8s.cloudogu.com", Version: "v2", Resource: "blueprints"}
+
+type blueprintGetter interface {
+	Get(ctx context.Context, name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error)
+}
+
+// blueprintConfig is the config section of the blueprint in the spec of a Blueprint CR.
+type blueprintConfig struct {
+	Dogus  map[string][]blueprintConfigEntry `json:"dogus"`
+	Global []blueprintConfigEntry            `json:"global"`
+}
+
+// blueprintConfigEntry is a config key of a blueprint. The value can also be referenced in a Secret or ConfigMap,
+// these references are resolved by the k8s-blueprint-operator only.
+type blueprintConfigEntry struct {
+	Key       string  `json:"key"`
+	Value     *string `json:"value"`
+	Absent    bool    `json:"absent"`
+	Sensitive bool    `json:"sensitive"`
+}
+
+// Blueprint is the config of a Blueprint CR, which takes precedence over all other defaults of the job.
+// Keys that the blueprint declares without a literal value, i.e. absent or referenced keys, are owned by the
+// blueprint: no default is applied for them. All methods can be called on a nil Blueprint.
+type Blueprint struct {
+	Name                string
+	GlobalConfig        map[string]string
+	DoguConfig          map[string]map[string]string
+	SensitiveDoguConfig map[string]map[string]string
+	// ownedGlobalKeys and ownedDoguKeys are the keys without a literal value.
+	ownedGlobalKeys []string
+	ownedDoguKeys   map[string][]string
+}
+
+// LoadBlueprint reads the config of the Blueprint CR with the name. It returns nil if the Blueprint CR does not exist,
+// so that the job applies its own defaults.
+func LoadBlueprint(ctx context.Context, client blueprintGetter, name string) (*Blueprint, error) {
+	resource, err := client.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		slog.Warn("Blueprint does not exist. Applying the defaults without the blueprint config.", "blueprint", name)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blueprint %q: %w", name, err)
+	}
+
+	rawConfig, found, err := unstructured.NestedMap(resource.Object, "spec", "blueprint", "config")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config of blueprint %q: %w", name, err)
+	}
+
+	var config blueprintConfig
+	if found {
+		if err = runtime.DefaultUnstructuredConverter.FromUnstructured(rawConfig, &config); err != nil {
+			return nil, fmt.Errorf("failed to read config of blueprint %q: %w", name, err)
+		}
+	}
+
+	return newBlueprint(name, config), nil
+}
+
+func newBlueprint(name string, config blueprintConfig) *Blueprint {
+	blueprint := &Blueprint{
+		Name:                name,
+		GlobalConfig:        map[string]string{},
+		DoguConfig:          map[string]map[string]string{},
+		SensitiveDoguConfig: map[string]map[string]string{},
+		ownedDoguKeys:       map[string][]string{},
+	}
+
+	for _, entry := range config.Global {
+		if entry.Absent || entry.Value == nil {
+			blueprint.ownedGlobalKeys = append(blueprint.ownedGlobalKeys, entry.Key)
+			continue
+		}
+		blueprint.GlobalConfig[entry.Key] = *entry.Value
+	}
+
+	for dogu, entries := range config.Dogus {
+		for _, entry := range entries {
+			if entry.Absent || entry.Value == nil {
+				blueprint.ownedDoguKeys[dogu] = append(blueprint.ownedDoguKeys[dogu], entry.Key)
+				continue
+			}
+
+			layer := blueprint.DoguConfig
+			if entry.Sensitive {
+				layer = blueprint.SensitiveDoguConfig
+			}
+			if layer[dogu] == nil {
+				layer[dogu] = map[string]string{}
+			}
+			layer[dogu][entry.Key] = *entry.Value
+		}
+	}
+
+	return blueprint
+}
+
+// applyGlobal overrides the global defaults with the global config of the blueprint.
+func (b *Blueprint) applyGlobal(defaults map[string]string) {
+	if b == nil {
+		return
+	}
+
+	for _, key := range b.ownedGlobalKeys {
+		delete(defaults, key)
+	}
+	maps.Copy(defaults, b.GlobalConfig)
+	slog.Info("Applied the global config of the blueprint", "blueprint", b.Name, "keys", len(b.GlobalConfig), "ownedKeys", len(b.ownedGlobalKeys))
+}
+
+// applyDogu overrides the dogu defaults and the sensitive dogu defaults with the dogu config of the blueprint.
+// The maps of the defaults are modified.
+func (b *Blueprint) applyDogu(defaults, sensitiveDefaults map[string]map[string]string) {
+	if b == nil {
+		return
+	}
+
+	for dogu, keys := range b.ownedDoguKeys {
+		for _, key := range keys {
+			delete(defaults[dogu], key)
+			delete(sensitiveDefaults[dogu], key)
+		}
+	}
+	for dogu, config := range b.DoguConfig {
+		for key := range config {
+			// a key is either sensitive or not
+			delete(sensitiveDefaults[dogu], key)
+		}
+		mergeDoguLayer(defaults, dogu, config)
+	}
+	for dogu, config := range b.SensitiveDoguConfig {
+		for key := range config {
+			delete(defaults[dogu], key)
+		}
+		mergeDoguLayer(sensitiveDefaults, dogu, config)
+	}
+
+	removeEmptyDogus(defaults)
+	removeEmptyDogus(sensitiveDefaults)
+	slog.Info("Applied the dogu config of the blueprint", "blueprint", b.Name, "dogus", len(b.DoguConfig), "sensitiveDogus", len(b.SensitiveDoguConfig))
+}
+
+func mergeDoguLayer(defaults map[string]map[string]string, dogu string, config map[string]string) {
+	if defaults[dogu] == nil {
+		defaults[dogu] = map[string]string{}
+	}
+	maps.Copy(defaults[dogu], config)
+}
+
+func removeEmptyDogus(defaults map[string]map[string]string) {
+	maps.DeleteFunc(defaults, func(_ string, config map[string]string) bool {
+		return len(config) == 0
+	})
+}
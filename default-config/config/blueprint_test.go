@@ -0,0 +1,191 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func ptr(value string) *string {
+	return &value
+}
+
+func newTestBlueprint(config map[string]any) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "k8s.cloudogu.com/v2",
+		"kind":       "Blueprint",
+		"metadata":   map[string]any{"name": "blueprint"},
+		"spec": map[string]any{
+			"blueprint": map[string]any{
+				"dogus":  []any{map[string]any{"name": "official/ldap", "version": "2.6.8-1"}},
+				"config": config,
+			},
+		},
+	}}
+}
+
+func TestLoadBlueprint(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should read the config of the blueprint", func(t *testing.T) {
+		client := newMockBlueprintGetter(t)
+		client.EXPECT().Get(testCtx, "blueprint", metav1.GetOptions{}).Return(newTestBlueprint(map[string]any{
+			"global": []any{
+				map[string]any{"key": "fqdn", "value": "ces.example.com"},
+				map[string]any{"key": "admin_group", "absent": true},
+			},
+			"dogus": map[string]any{
+				"ldap": []any{
+					map[string]any{"key": "admin_mail", "value": "admin@example.com"},
+					map[string]any{"key": "admin_password", "sensitive": true, "secretRef": map[string]any{"name": "ldap-admin", "key": "password"}},
+				},
+				"postfix": []any{
+					map[string]any{"key": "sasl_password", "sensitive": true, "value": "secret"},
+				},
+			},
+		}), nil)
+
+		blueprint, err := LoadBlueprint(testCtx, client, "blueprint")
+
+		require.NoError(t, err)
+		require.NotNil(t, blueprint)
+		assert.Equal(t, "blueprint", blueprint.Name)
+		assert.Equal(t, map[string]string{"fqdn": "ces.example.com"}, blueprint.GlobalConfig)
+		assert.Equal(t, []string{"admin_group"}, blueprint.ownedGlobalKeys)
+		assert.Equal(t, map[string]map[string]string{"ldap": {"admin_mail": "admin@example.com"}}, blueprint.DoguConfig)
+		assert.Equal(t, map[string]map[string]string{"postfix": {"sasl_password": "secret"}}, blueprint.SensitiveDoguConfig)
+		assert.Equal(t, map[string][]string{"ldap": {"admin_password"}}, blueprint.ownedDoguKeys)
+	})
+
+	t.Run("should return an empty blueprint without config", func(t *testing.T) {
+		client := newMockBlueprintGetter(t)
+		resource := newTestBlueprint(nil)
+		unstructured.RemoveNestedField(resource.Object, "spec", "blueprint", "config")
+		client.EXPECT().Get(testCtx, "blueprint", metav1.GetOptions{}).Return(resource, nil)
+
+		blueprint, err := LoadBlueprint(testCtx, client, "blueprint")
+
+		require.NoError(t, err)
+		require.NotNil(t, blueprint)
+		assert.Empty(t, blueprint.GlobalConfig)
+		assert.Empty(t, blueprint.DoguConfig)
+		assert.Empty(t, blueprint.SensitiveDoguConfig)
+	})
+
+	t.Run("should return nil if the blueprint does not exist", func(t *testing.T) {
+		client := newMockBlueprintGetter(t)
+		client.EXPECT().Get(testCtx, "blueprint", metav1.GetOptions{}).Return(nil, apierrors.NewNotFound(schema.GroupResource{Group: "k8s.cloudogu.com", Resource: "blueprints"}, "blueprint"))
+
+		blueprint, err := LoadBlueprint(testCtx, client, "blueprint")
+
+		require.NoError(t, err)
+		assert.Nil(t, blueprint)
+	})
+
+	t.Run("should fail to get the blueprint", func(t *testing.T) {
+		client := newMockBlueprintGetter(t)
+		client.EXPECT().Get(testCtx, "blueprint", metav1.GetOptions{}).Return(nil, assert.AnError)
+
+		_, err := LoadBlueprint(testCtx, client, "blueprint")
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, `failed to get blueprint "blueprint"`)
+	})
+
+	t.Run("should fail on an invalid config", func(t *testing.T) {
+		client := newMockBlueprintGetter(t)
+		client.EXPECT().Get(testCtx, "blueprint", metav1.GetOptions{}).Return(newTestBlueprint(map[string]any{
+			"global": "fqdn=ces.example.com",
+		}), nil)
+
+		_, err := LoadBlueprint(testCtx, client, "blueprint")
+
+		assert.ErrorContains(t, err, `failed to read config of blueprint "blueprint"`)
+	})
+}
+
+func TestBlueprint_applyGlobal(t *testing.T) {
+	t.Run("should override and remove the defaults", func(t *testing.T) {
+		blueprint := newBlueprint("blueprint", blueprintConfig{Global: []blueprintConfigEntry{
+			{Key: "domain", Value: ptr("example.com")},
+			{Key: "mail_address", Absent: true},
+			{Key: "default_dogu"},
+		}})
+		defaults := map[string]string{"domain": "ces.localdomain", "mail_address": "", "default_dogu": "cas", "admin_group": "cesAdmin"}
+
+		blueprint.applyGlobal(defaults)
+
+		assert.Equal(t, map[string]string{"domain": "example.com", "admin_group": "cesAdmin"}, defaults)
+	})
+
+	t.Run("should not change the defaults without blueprint", func(t *testing.T) {
+		var blueprint *Blueprint
+		defaults := map[string]string{"domain": "ces.localdomain"}
+
+		blueprint.applyGlobal(defaults)
+
+		assert.Equal(t, map[string]string{"domain": "ces.localdomain"}, defaults)
+	})
+}
+
+func TestBlueprint_applyDogu(t *testing.T) {
+	t.Run("should override and remove the dogu defaults", func(t *testing.T) {
+		blueprint := newBlueprint("blueprint", blueprintConfig{Dogus: map[string][]blueprintConfigEntry{
+			"ldap": {
+				{Key: "admin_mail", Value: ptr("admin@example.com")},
+				{Key: "admin_password", Sensitive: true, Value: ptr("secret")},
+			},
+			"postfix": {{Key: "relayhost", Absent: true}},
+			"cas":     {{Key: "ldap/host", Sensitive: true, Value: ptr("ldap.example.com")}},
+			"redmine": {{Key: "logging/root", Value: ptr("INFO")}},
+		}})
+		defaults := map[string]map[string]string{
+			"ldap":    {"admin_mail": "admin@ces.invalid", "admin_username": "admin"},
+			"postfix": {"relayhost": "n/a"},
+			"cas":     {"ldap/host": "ldap", "ldap/port": "389"},
+		}
+		sensitiveDefaults := map[string]map[string]string{
+			"ldap": {"admin_password": "generated"},
+		}
+
+		blueprint.applyDogu(defaults, sensitiveDefaults)
+
+		assert.Equal(t, map[string]map[string]string{
+			"ldap":    {"admin_mail": "admin@example.com", "admin_username": "admin"},
+			"cas":     {"ldap/port": "389"},
+			"redmine": {"logging/root": "INFO"},
+		}, defaults)
+		assert.Equal(t, map[string]map[string]string{
+			"ldap": {"admin_password": "secret"},
+			"cas":  {"ldap/host": "ldap.example.com"},
+		}, sensitiveDefaults)
+	})
+
+	t.Run("should move a sensitive default to the dogu config", func(t *testing.T) {
+		blueprint := newBlueprint("blueprint", blueprintConfig{Dogus: map[string][]blueprintConfigEntry{
+			"postfix": {{Key: "sasl_username", Value: ptr("relay")}},
+		}})
+		defaults := map[string]map[string]string{}
+		sensitiveDefaults := map[string]map[string]string{"postfix": {"sasl_username": "other"}}
+
+		blueprint.applyDogu(defaults, sensitiveDefaults)
+
+		assert.Equal(t, map[string]map[string]string{"postfix": {"sasl_username": "relay"}}, defaults)
+		assert.Empty(t, sensitiveDefaults)
+	})
+
+	t.Run("should not change the defaults without blueprint", func(t *testing.T) {
+		var blueprint *Blueprint
+		defaults := map[string]map[string]string{"postfix": {"relayhost": "n/a"}}
+
+		blueprint.applyDogu(defaults, map[string]map[string]string{})
+
+		assert.Equal(t, map[string]map[string]string{"postfix": {"relayhost": "n/a"}}, defaults)
+	})
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cloudogu/ecosystem-core/default-config/logging"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"sigs.k8s.io/yaml"
+)
+
+// Layers of the defaults in the order of their precedence. A layer overrides the keys of the layers before it.
+// The config of the blueprint is applied over all layers.
+const (
+	LayerBuiltin      = "built-in"
+	LayerLopIdp       = "lop-idp"
+	LayerExternalLdap = "external-ldap"
+	LayerProfile      = "profile"
+	LayerSMTPRelay    = "smtp-relay"
+	LayerProxy        = "proxy"
+	LayerChart        = "chart"
+	LayerInitial      = "initial"
+)
+
+// ErrExtraDefaultsConfig is returned if the extra defaults of the chart are invalid.
+var ErrExtraDefaultsConfig = errors.New("invalid extra defaults")
+
+var (
+	doguNamePattern  = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+	configKeyPattern = regexp.MustCompile(`^[A-Za-z0-9._-]+(/[A-Za-z0-9._-]+)*$`)
+)
+
+// ExtraDefaults are additional global and dogu defaults from the values of the chart.
+type ExtraDefaults struct {
+	GlobalConfig map[string]string            `json:"globalConfig"`
+	DoguConfig   map[string]map[string]string `json:"doguConfig"`
+}
+
+// ParseExtraDefaults parses the extra defaults from JSON. It returns nil if raw is empty.
+func ParseExtraDefaults(raw string) (*ExtraDefaults, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	extra := &ExtraDefaults{}
+	if err := yaml.UnmarshalStrict([]byte(raw), extra); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExtraDefaultsConfig, err)
+	}
+
+	if err := extra.validate(); err != nil {
+		return nil, err
+	}
+
+	return extra, nil
+}
+
+func (e *ExtraDefaults) validate() error {
+	var errs []error
+	for _, key := range slices.Sorted(maps.Keys(e.GlobalConfig)) {
+		if !configKeyPattern.MatchString(key) {
+			errs = append(errs, fmt.Errorf("global key %q must consist of segments of letters, digits, '.', '_' and '-' separated by '/'", key))
+		}
+	}
+	for _, dogu := range slices.Sorted(maps.Keys(e.DoguConfig)) {
+		if !doguNamePattern.MatchString(dogu) {
+			errs = append(errs, fmt.Errorf("dogu %q must be a simple dogu name like \"cas\"", dogu))
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(e.DoguConfig[dogu])) {
+			if !configKeyPattern.MatchString(key) {
+				errs = append(errs, fmt.Errorf("key %q of dogu %q must consist of segments of letters, digits, '.', '_' and '-' separated by '/'", key, dogu))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrExtraDefaultsConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+type globalLayer struct {
+	name   string
+	config map[string]string
+}
+
+type doguLayer struct {
+	name   string
+	config map[string]map[string]string
+}
+
+// mergeGlobalLayers returns a new map with the keys of all layers and the names of the layers the values are taken from.
+func mergeGlobalLayers(layers []globalLayer) (config map[string]string, sources map[string]string) {
+	config, sources = map[string]string{}, map[string]string{}
+	for _, layer := range layers {
+		for key, value := range layer.config {
+			config[key] = value
+			sources[key] = layer.name
+		}
+	}
+	return config, sources
+}
+
+// mergeDoguLayers returns new maps with the keys of all layers and the names of the layers the values are taken from.
+func mergeDoguLayers(layers []doguLayer) (config map[string]map[string]string, sources map[string]map[string]string) {
+	config, sources = map[string]map[string]string{}, map[string]map[string]string{}
+	for _, layer := range layers {
+		for dogu, doguConfig := range layer.config {
+			if config[dogu] == nil {
+				config[dogu], sources[dogu] = map[string]string{}, map[string]string{}
+			}
+			for key, value := range doguConfig {
+				config[dogu][key] = value
+				sources[dogu][key] = layer.name
+			}
+		}
+	}
+	return config, sources
+}
+
+// PlannedKey is a resolved default of the dry-run plan.
+type PlannedKey struct {
+	// Repo is report.RepoGlobal or report.RepoDogu.
+	Repo  string
+	Dogu  string
+	Key   string
+	Value string
+	// Layer is the layer the value is taken from.
+	Layer string
+}
+
+// Plan returns the resolved global and dogu defaults and the layers they are taken from, sorted by dogu and key.
+// It does not access the cluster, so the sensitive dogu config and the config of the blueprint are not part of it.
+// Keys that are already set in the cluster are not changed by the job. The plan is written to the log of the job, so
+// the values of the proxy layer and of sensitive keys are redacted.
+func (dca *DefaultConfigApplier) Plan() []PlannedKey {
+	var plan []PlannedKey
+
+	globalConfig, globalSources := mergeGlobalLayers(dca.globalLayers())
+	for key, value := range globalConfig {
+		plan = append(plan, PlannedKey{Repo: report.RepoGlobal, Key: key, Value: plannedValue(key, value, globalSources[key]), Layer: globalSources[key]})
+	}
+
+	doguConfig, doguSources := mergeDoguLayers(dca.doguLayers())
+	for dogu, config := range doguConfig {
+		for key, value := range config {
+			plan = append(plan, PlannedKey{Repo: report.RepoDogu, Dogu: dogu, Key: key, Value: plannedValue(key, value, doguSources[dogu][key]), Layer: doguSources[dogu][key]})
+		}
+	}
+
+	slices.SortFunc(plan, func(a, b PlannedKey) int {
+		return cmp.Or(strings.Compare(a.Dogu, b.Dogu), strings.Compare(a.Key, b.Key))
+	})
+	return plan
+}
+
+// plannedValue returns the value of the plan. The proxy must never be logged, see Proxy. Only the last segment of a
+// key is checked, so that e.g. ldap/password is redacted, but not the password-policy/* keys.
+func plannedValue(key, value, layer string) string {
+	if layer == LayerProxy || logging.IsSensitive(path.Base(key)) {
+		return logging.Redacted
+	}
+
+	return value
+}
+
+// WritePlan writes the plan as table.
+func WritePlan(w io.Writer, plan []PlannedKey) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONFIG\tDOGU\tKEY\tVALUE\tLAYER")
+	for _, key := range plan {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%q\t%s\n", key.Repo, cmp.Or(key.Dogu, "-"), key.Key, key.Value, key.Layer)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExtraDefaults(t *testing.T) {
+	t.Run("should return nil if empty", func(t *testing.T) {
+		extra, err := ParseExtraDefaults("")
+
+		require.NoError(t, err)
+		assert.Nil(t, extra)
+	})
+
+	t.Run("should parse the extra defaults", func(t *testing.T) {
+		extra, err := ParseExtraDefaults(`{"globalConfig": {"proxy/enabled": "true"}, "doguConfig": {"redmine": {"logging/root": "INFO"}}}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"proxy/enabled": "true"}, extra.GlobalConfig)
+		assert.Equal(t, map[string]map[string]string{"redmine": {"logging/root": "INFO"}}, extra.DoguConfig)
+	})
+
+	t.Run("should convert numbers and booleans to strings", func(t *testing.T) {
+		extra, err := ParseExtraDefaults(`{"globalConfig": {"proxy/port": 3128, "proxy/enabled": true}}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"proxy/port": "3128", "proxy/enabled": "true"}, extra.GlobalConfig)
+	})
+
+	t.Run("should fail on unknown fields", func(t *testing.T) {
+		_, err := ParseExtraDefaults(`{"global": {"proxy/enabled": "true"}}`)
+
+		require.ErrorIs(t, err, ErrExtraDefaultsConfig)
+	})
+
+	t.Run("should fail on invalid keys and dogu names", func(t *testing.T) {
+		_, err := ParseExtraDefaults(`{"globalConfig": {"/proxy": "true", "proxy//port": "3128", "": "x"}, "doguConfig": {"official/cas": {"a": "b"}, "redmine": {"logging root": "INFO"}}}`)
+
+		require.ErrorIs(t, err, ErrExtraDefaultsConfig)
+		assert.ErrorContains(t, err, `global key "/proxy" must consist of segments`)
+		assert.ErrorContains(t, err, `global key "proxy//port" must consist of segments`)
+		assert.ErrorContains(t, err, `global key "" must consist of segments`)
+		assert.ErrorContains(t, err, `dogu "official/cas" must be a simple dogu name`)
+		assert.ErrorContains(t, err, `key "logging root" of dogu "redmine" must consist of segments`)
+	})
+}
+
+func Test_mergeGlobalLayers(t *testing.T) {
+	builtin := map[string]string{"domain": "ces.localdomain", "admin_group": "cesAdmin"}
+
+	config, sources := mergeGlobalLayers([]globalLayer{
+		{name: LayerBuiltin, config: builtin},
+		{name: LayerProfile, config: nil},
+		{name: LayerChart, config: map[string]string{"domain": "chart.example.com", "proxy/enabled": "true"}},
+		{name: LayerInitial, config: map[string]string{"domain": "example.com"}},
+	})
+
+	assert.Equal(t, map[string]string{"domain": "example.com", "admin_group": "cesAdmin", "proxy/enabled": "true"}, config)
+	assert.Equal(t, map[string]string{"domain": LayerInitial, "admin_group": LayerBuiltin, "proxy/enabled": LayerChart}, sources)
+	assert.Equal(t, "ces.localdomain", builtin["domain"], "the layers must not be modified")
+}
+
+func Test_mergeDoguLayers(t *testing.T) {
+	builtin := map[string]map[string]string{"postfix": {"relayhost": "n/a"}}
+
+	config, sources := mergeDoguLayers([]doguLayer{
+		{name: LayerBuiltin, config: builtin},
+		{name: LayerSMTPRelay, config: map[string]map[string]string{"postfix": {"relayhost": "[mail.example.com]:587"}}},
+		{name: LayerChart, config: map[string]map[string]string{"redmine": {"logging/root": "INFO"}}},
+	})
+
+	assert.Equal(t, map[string]map[string]string{
+		"postfix": {"relayhost": "[mail.example.com]:587"},
+		"redmine": {"logging/root": "INFO"},
+	}, config)
+	assert.Equal(t, map[string]map[string]string{
+		"postfix": {"relayhost": LayerSMTPRelay},
+		"redmine": {"logging/root": LayerChart},
+	}, sources)
+	assert.Equal(t, "n/a", builtin["postfix"]["relayhost"], "the layers must not be modified")
+}
+
+func TestDefaultConfigApplier_Plan(t *testing.T) {
+	dca := &DefaultConfigApplier{
+		initialFQDN:       "ces.example.com",
+		initialInternalIP: "10.0.0.10",
+		profile:           Profile{GlobalConfig: map[string]string{"password-policy/min_length": "16"}},
+		extraDefaults: &ExtraDefaults{
+			GlobalConfig: map[string]string{"fqdn": "chart.example.com", "proxy/enabled": "true"},
+			DoguConfig:   map[string]map[string]string{"ldap": {"admin_mail": "admin@example.com"}, "redmine": {"api_token": "token"}, "cas": {"ldap/password": "secret"}},
+		},
+		externalLdap: &ExternalLdap{Host: "dc.example.com", Port: 389, Encryption: "none", BaseDN: "dc=example,dc=com"},
+		proxy:        &Proxy{Server: "proxy.example.com", Port: 3128, CredentialsSecret: SecretRef{Name: "proxy-credentials"}},
+	}
+
+	plan := dca.Plan()
+
+	assert.Contains(t, plan, PlannedKey{Repo: "global", Key: "fqdn", Value: "ces.example.com", Layer: LayerInitial})
+	assert.Contains(t, plan, PlannedKey{Repo: "global", Key: "k8s/internal_ip", Value: "10.0.0.10", Layer: LayerInitial})
+	assert.Contains(t, plan, PlannedKey{Repo: "global", Key: "proxy/enabled", Value: "true", Layer: LayerChart})
+	assert.Contains(t, plan, PlannedKey{Repo: "global", Key: "password-policy/min_length", Value: "16", Layer: LayerProfile})
+	assert.Contains(t, plan, PlannedKey{Repo: "global", Key: "domain", Value: "ces.localdomain", Layer: LayerBuiltin})
+	assert.Contains(t, plan, PlannedKey{Repo: "dogu", Dogu: "cas", Key: "ldap/host", Value: "dc.example.com", Layer: LayerExternalLdap})
+	assert.Contains(t, plan, PlannedKey{Repo: "dogu", Dogu: "ldap", Key: "admin_mail", Value: "admin@example.com", Layer: LayerChart})
+	assert.Contains(t, plan, PlannedKey{Repo: "global", Key: "proxy/server", Value: "[REDACTED]", Layer: LayerProxy})
+	assert.Contains(t, plan, PlannedKey{Repo: "global", Key: "proxy/port", Value: "[REDACTED]", Layer: LayerProxy})
+	assert.Contains(t, plan, PlannedKey{Repo: "dogu", Dogu: "redmine", Key: "api_token", Value: "[REDACTED]", Layer: LayerChart})
+	assert.Contains(t, plan, PlannedKey{Repo: "dogu", Dogu: "cas", Key: "ldap/password", Value: "[REDACTED]", Layer: LayerChart})
+	for _, key := range plan {
+		assert.NotEqual(t, ldapAdminPasswordKey, key.Key, "the sensitive config must not be part of the plan")
+		assert.NotEqual(t, proxyPasswordKey, key.Key, "the credentials of the proxy must not be part of the plan")
+	}
+
+	assert.Equal(t, "", plan[0].Dogu, "the global config is planned first")
+	assert.Equal(t, "redmine", plan[len(plan)-1].Dogu)
+}
+
+func TestWritePlan(t *testing.T) {
+	var out bytes.Buffer
+
+	err := WritePlan(&out, []PlannedKey{
+		{Repo: "global", Key: "proxy/enabled", Value: "true", Layer: LayerChart},
+		{Repo: "dogu", Dogu: "postfix", Key: "relayhost", Value: "", Layer: LayerBuiltin},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "CONFIG  DOGU     KEY            VALUE   LAYER\n"+
+		"global  -        proxy/enabled  \"true\"  chart\n"+
+		"dogu    postfix  relayhost      \"\"      built-in\n", out.String())
+}
@@ -4,10 +4,15 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 
 	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
 	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/cloudogu/ecosystem-core/default-config/tracing"
 	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type doguConfigRepo interface {
@@ -19,68 +24,117 @@ type doguConfigRepo interface {
 type cesDoguConfigWriter struct {
 	doguConfigRepo          doguConfigRepo
 	sensitiveDoguConfigRepo doguConfigRepo
+	summary                 *report.Summary
+	recorder                *event.Recorder
+	// initialAdmin stores the generated credentials of the LDAP admin for the first login. Nil disables it.
+	initialAdmin *initialAdminWriter
 }
 
 func (dcw *cesDoguConfigWriter) applyDefaultDoguConfig(ctx context.Context, defaultDoguConfig map[string]map[string]string, sensitiveDefaultDoguConfig map[string]map[string]string) error {
 	slog.Info("Applying default dogu config...")
-	if err := applyDefaultsForRepo(ctx, defaultDoguConfig, dcw.doguConfigRepo); err != nil {
+	counts, err := applyDefaultsForRepo(ctx, defaultDoguConfig, dcw.doguConfigRepo, func(dogu string, created []string, skipped int) {
+		dcw.recorder.Normal(ctx, event.DoguConfig(dogu), event.ReasonDefaultsApplied, "Created %d default keys, skipped %d existing keys", len(created), skipped)
+	})
+	dcw.summary.AddKeys(report.RepoDogu, counts)
+	if err != nil {
 		return fmt.Errorf("failed to apply default dogu config: %w", err)
 	}
 
 	slog.Info("Applying default sensitive dogu config...")
-	if err := applyDefaultsForRepo(ctx, sensitiveDefaultDoguConfig, dcw.sensitiveDoguConfigRepo); err != nil {
+	passwordGenerated := false
+	counts, err = applyDefaultsForRepo(ctx, sensitiveDefaultDoguConfig, dcw.sensitiveDoguConfigRepo, func(dogu string, created []string, skipped int) {
+		obj := event.SensitiveDoguConfig(dogu)
+		dcw.recorder.Normal(ctx, obj, event.ReasonDefaultsApplied, "Created %d sensitive default keys, skipped %d existing keys", len(created), skipped)
+		if dogu == ldapDogu && slices.Contains(created, ldapAdminPasswordKey) {
+			dcw.recorder.Normal(ctx, obj, event.ReasonPasswordGenerated, "Generated initial password for key %q", ldapAdminPasswordKey)
+			passwordGenerated = true
+		}
+	})
+	dcw.summary.AddKeys(report.RepoSensitiveDogu, counts)
+	if err != nil {
 		return fmt.Errorf("failed to apply default sensitive dogu config: %w", err)
 	}
 
-	return nil
+	if dcw.initialAdmin == nil {
+		return nil
+	}
+	if passwordGenerated {
+		username := defaultDoguConfig[ldapDogu][ldapAdminUsernameKey]
+		return dcw.initialAdmin.write(ctx, username, sensitiveDefaultDoguConfig[ldapDogu][ldapAdminPasswordKey])
+	}
+
+	return dcw.initialAdmin.deleteExpired(ctx)
 }
 
-func applyDefaultsForRepo(ctx context.Context, defaultDoguConfig map[string]map[string]string, repo doguConfigRepo) error {
+// applyDefaultsForRepo returns the counts of the keys that have been saved, even if it fails for a later dogu.
+// onSaved is called with the names of the created keys after the config of a dogu has been saved.
+func applyDefaultsForRepo(ctx context.Context, defaultDoguConfig map[string]map[string]string, repo doguConfigRepo, onSaved func(dogu string, created []string, skipped int)) (report.KeyCounts, error) {
+	var counts report.KeyCounts
 	for dogu, doguDefaultConfig := range defaultDoguConfig {
 		slog.Info("Applying default dogu config...", "dogu", dogu)
 
-		doguName := cesLibDogu.SimpleName(dogu)
-		doguConfig, err := repo.Get(ctx, doguName)
+		spanCtx, span := tracing.Start(ctx, "apply-dogu-defaults", attribute.String("dogu", dogu))
+		created, skipped, err := applyDefaultsForDogu(spanCtx, dogu, doguDefaultConfig, repo)
+		span.SetAttributes(attribute.Int("keys.created", len(created)), attribute.Int("keys.skipped", skipped))
+		tracing.End(span, err)
 		if err != nil {
-			if !cesLibErr.IsNotFoundError(err) {
-				return fmt.Errorf("error reading dogu config for dogu %q: %w", dogu, err)
-			}
-
-			doguConfig, err = repo.Create(ctx, regLibConfig.CreateDoguConfig(doguName, make(regLibConfig.Entries)))
-			if err != nil {
-				return fmt.Errorf("error creating new dogu config for dogu %q: %w", dogu, err)
-			}
+			return counts, err
 		}
 
-		for key, value := range doguDefaultConfig {
-			cKey := regLibConfig.Key(key)
-			cValue := regLibConfig.Value(value)
+		counts.Created += len(created)
+		counts.Skipped += skipped
+		onSaved(dogu, created, skipped)
+		slog.Info("...Successfully applied default-values to dogu config.", "dogu", dogu)
+	}
+	return counts, nil
+}
 
-			_, exists := doguConfig.Get(cKey)
-			if exists {
-				slog.Debug("Dogu config key already exists. Skipping...", "dogu", dogu, "key", cKey.String())
-				continue
-			}
+func applyDefaultsForDogu(ctx context.Context, dogu string, doguDefaultConfig map[string]string, repo doguConfigRepo) (created []string, skipped int, err error) {
+	doguName := cesLibDogu.SimpleName(dogu)
+	doguConfig, err := repo.Get(ctx, doguName)
+	if err != nil {
+		if !cesLibErr.IsNotFoundError(err) {
+			return nil, 0, fmt.Errorf("error reading dogu config for dogu %q: %w", dogu, err)
+		}
 
-			slog.Debug("Setting dogu config key", "dogu", dogu, "key", cKey.String())
-			newDoguConfig, err := doguConfig.Set(cKey, cValue)
-			if err != nil {
-				return fmt.Errorf("failed to set dogu config key %q for dogu %q: %w", cKey, dogu, err)
-			}
+		doguConfig, err = repo.Create(ctx, regLibConfig.CreateDoguConfig(doguName, make(regLibConfig.Entries)))
+		if cesLibErr.IsAlreadyExistsError(err) {
+			// another writer created the dogu config in the meantime
+			doguConfig, err = repo.Get(ctx, doguName)
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("error creating new dogu config for dogu %q: %w", dogu, err)
+		}
+	}
 
-			doguConfig = regLibConfig.DoguConfig{
-				DoguName: doguName,
-				Config:   newDoguConfig,
-			}
+	for key, value := range doguDefaultConfig {
+		cKey := regLibConfig.Key(key)
+		cValue := regLibConfig.Value(value)
 
+		_, exists := doguConfig.Get(cKey)
+		if exists {
+			slog.Debug("Dogu config key already exists. Skipping...", "dogu", dogu, "key", cKey.String())
+			skipped++
+			continue
 		}
 
-		_, err = repo.SaveOrMerge(ctx, doguConfig)
+		slog.Debug("Setting dogu config key", "dogu", dogu, "key", cKey.String())
+		newDoguConfig, err := doguConfig.Set(cKey, cValue)
 		if err != nil {
-			return fmt.Errorf("failed to save new dogu config for dogu %q: %w", dogu, err)
+			return nil, 0, fmt.Errorf("failed to set dogu config key %q for dogu %q: %w", cKey, dogu, err)
 		}
 
-		slog.Info("...Successfully applied default-values to dogu config.", "dogu", dogu)
+		doguConfig = regLibConfig.DoguConfig{
+			DoguName: doguName,
+			Config:   newDoguConfig,
+		}
+		created = append(created, cKey.String())
+	}
+
+	_, err = repo.SaveOrMerge(ctx, doguConfig)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to save new dogu config for dogu %q: %w", dogu, err)
 	}
-	return nil
+
+	return created, skipped, nil
 }
@@ -2,16 +2,27 @@ package config
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
 	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
 	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+func ignoreSaved(string, []string, int) {}
+
 func Test_applyDefaultsForRepo(t *testing.T) {
 	testCtx := context.Background()
 
@@ -55,9 +66,10 @@ func Test_applyDefaultsForRepo(t *testing.T) {
 			return cfg, nil
 		})
 
-		err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo)
+		counts, err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo, ignoreSaved)
 
 		require.NoError(t, err)
+		assert.Equal(t, report.KeyCounts{Created: 3}, counts)
 	})
 
 	t.Run("should not apply config key if already exists", func(t *testing.T) {
@@ -94,9 +106,18 @@ func Test_applyDefaultsForRepo(t *testing.T) {
 			return cfg, nil
 		})
 
-		err = applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo)
+		saved := map[string][]string{}
+		counts, err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo, func(dogu string, created []string, skipped int) {
+			slices.Sort(created)
+			saved[dogu] = created
+			if dogu == "ldap" {
+				assert.Equal(t, 1, skipped)
+			}
+		})
 
 		require.NoError(t, err)
+		assert.Equal(t, report.KeyCounts{Created: 2, Skipped: 1}, counts)
+		assert.Equal(t, map[string][]string{"ldap": {"key"}, "cas": {"other"}}, saved)
 	})
 
 	t.Run("should create new global config if not exists", func(t *testing.T) {
@@ -130,9 +151,69 @@ func Test_applyDefaultsForRepo(t *testing.T) {
 			return cfg, nil
 		})
 
-		err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo)
+		counts, err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo, ignoreSaved)
 
 		require.NoError(t, err)
+		assert.Equal(t, report.KeyCounts{Created: 3}, counts)
+	})
+
+	t.Run("should read dogu config again if it was created concurrently", func(t *testing.T) {
+		emptyLdapConfig := regLibConfig.CreateDoguConfig("ldap", make(regLibConfig.Entries))
+		concurrentLdapConfig := regLibConfig.CreateDoguConfig("ldap", regLibConfig.Entries{"key": "concurrent"})
+
+		mockRepo := newMockDoguConfigRepo(t)
+		mockRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(emptyLdapConfig, cesLibErr.NewNotFoundError(assert.AnError)).Once()
+		mockRepo.EXPECT().Create(testCtx, emptyLdapConfig).Return(emptyLdapConfig, cesLibErr.NewAlreadyExistsError(assert.AnError))
+		mockRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(concurrentLdapConfig, nil).Once()
+
+		mockRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).RunAndReturn(func(ctx context.Context, cfg regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error) {
+			val, exists := cfg.Get("key")
+			assert.True(t, exists)
+			assert.Equal(t, "concurrent", val.String())
+
+			return cfg, nil
+		})
+
+		counts, err := applyDefaultsForRepo(testCtx, map[string]map[string]string{"ldap": {"key": "value"}}, mockRepo, ignoreSaved)
+
+		require.NoError(t, err)
+		assert.Equal(t, report.KeyCounts{Skipped: 1}, counts)
+	})
+
+	t.Run("should trace each dogu", func(t *testing.T) {
+		spanRecorder := tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spanRecorder)))
+		// the initial global provider cannot be set again, but a noop provider behaves the same
+		defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+		existingLdapConfig, err := regLibConfig.CreateDoguConfig("ldap", make(regLibConfig.Entries)).Set("foo", "alreadyExists")
+		require.NoError(t, err)
+		emptyCasConfig := regLibConfig.CreateDoguConfig("cas", make(regLibConfig.Entries))
+
+		mockRepo := newMockDoguConfigRepo(t)
+		mockRepo.EXPECT().Get(mock.Anything, cesLibDogu.SimpleName("ldap")).Return(regLibConfig.DoguConfig{DoguName: "ldap", Config: existingLdapConfig}, nil)
+		mockRepo.EXPECT().Get(mock.Anything, cesLibDogu.SimpleName("cas")).Return(emptyCasConfig, nil)
+		mockRepo.EXPECT().SaveOrMerge(mock.Anything, mock.Anything).RunAndReturn(func(ctx context.Context, cfg regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error) {
+			return cfg, nil
+		})
+
+		_, err = applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo, ignoreSaved)
+
+		require.NoError(t, err)
+		spans := spanRecorder.Ended()
+		require.Len(t, spans, 2)
+		attrsByDogu := map[string][]attribute.KeyValue{}
+		for _, span := range spans {
+			assert.Equal(t, "apply-dogu-defaults", span.Name())
+			for _, attr := range span.Attributes() {
+				if attr.Key == "dogu" {
+					attrsByDogu[attr.Value.AsString()] = span.Attributes()
+				}
+			}
+		}
+		assert.Contains(t, attrsByDogu["ldap"], attribute.Int("keys.created", 1))
+		assert.Contains(t, attrsByDogu["ldap"], attribute.Int("keys.skipped", 1))
+		assert.Contains(t, attrsByDogu["cas"], attribute.Int("keys.created", 1))
 	})
 
 	t.Run("should fail to apply default global config on error getting config", func(t *testing.T) {
@@ -141,11 +222,12 @@ func Test_applyDefaultsForRepo(t *testing.T) {
 		mockRepo := newMockDoguConfigRepo(t)
 		mockRepo.EXPECT().Get(testCtx, mock.Anything).Return(emptyConfig, assert.AnError)
 
-		err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo)
+		counts, err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo, ignoreSaved)
 
 		require.Error(t, err)
 		assert.ErrorIs(t, err, assert.AnError)
 		assert.ErrorContains(t, err, "error reading dogu config")
+		assert.Zero(t, counts)
 	})
 
 	t.Run("should fail to apply default global config on error creating config", func(t *testing.T) {
@@ -155,11 +237,12 @@ func Test_applyDefaultsForRepo(t *testing.T) {
 		mockRepo.EXPECT().Get(testCtx, mock.Anything).Return(emptyConfig, cesLibErr.NewNotFoundError(assert.AnError))
 		mockRepo.EXPECT().Create(testCtx, mock.Anything).Return(emptyConfig, assert.AnError)
 
-		err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo)
+		counts, err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo, ignoreSaved)
 
 		require.Error(t, err)
 		assert.ErrorIs(t, err, assert.AnError)
 		assert.ErrorContains(t, err, "error creating new dogu config for dogu")
+		assert.Zero(t, counts)
 	})
 
 	t.Run("should fail to apply default global config on error saving config", func(t *testing.T) {
@@ -190,11 +273,12 @@ func Test_applyDefaultsForRepo(t *testing.T) {
 			return cfg, assert.AnError
 		})
 
-		err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo)
+		counts, err := applyDefaultsForRepo(testCtx, defaultDoguConfig, mockRepo, ignoreSaved)
 
 		require.Error(t, err)
 		assert.ErrorIs(t, err, assert.AnError)
 		assert.ErrorContains(t, err, "failed to save new dogu config for dogu")
+		assert.Zero(t, counts)
 	})
 }
 
@@ -235,6 +319,120 @@ func Test_cesDoguConfigWriter_applyDefaultDoguConfig(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("should emit events without secret values", func(t *testing.T) {
+		casConfig := regLibConfig.CreateDoguConfig("cas", make(regLibConfig.Entries))
+		ldapConfig := regLibConfig.CreateDoguConfig("ldap", make(regLibConfig.Entries))
+
+		mockDoguRepo := newMockDoguConfigRepo(t)
+		mockDoguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("cas")).Return(casConfig, nil)
+		mockDoguRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(casConfig, nil)
+
+		mockSensitiveDoguRepo := newMockDoguConfigRepo(t)
+		mockSensitiveDoguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(ldapConfig, nil)
+		mockSensitiveDoguRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(ldapConfig, nil)
+
+		recorder, clientSet := newTestRecorder()
+		dcw := cesDoguConfigWriter{
+			doguConfigRepo:          mockDoguRepo,
+			sensitiveDoguConfigRepo: mockSensitiveDoguRepo,
+			recorder:                recorder,
+		}
+
+		err := dcw.applyDefaultDoguConfig(testCtx, defaultDoguConfig, map[string]map[string]string{
+			"ldap": {"admin_password": "topSecret"},
+		})
+
+		require.NoError(t, err)
+		events := recordedEvents(t, clientSet)
+		assert.Equal(t, []string{
+			"DefaultsApplied ConfigMap/cas-config: Created 1 default keys, skipped 0 existing keys",
+			"DefaultsApplied Secret/ldap-config: Created 1 sensitive default keys, skipped 0 existing keys",
+			`PasswordGenerated Secret/ldap-config: Generated initial password for key "admin_password"`,
+		}, events)
+		for _, e := range events {
+			assert.NotContains(t, e, "topSecret")
+		}
+	})
+
+	t.Run("should not emit password event if password already exists", func(t *testing.T) {
+		existingLdapConfig := regLibConfig.CreateDoguConfig("ldap", regLibConfig.Entries{"admin_password": "existing"})
+
+		mockDoguRepo := newMockDoguConfigRepo(t)
+
+		mockSensitiveDoguRepo := newMockDoguConfigRepo(t)
+		mockSensitiveDoguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(existingLdapConfig, nil)
+		mockSensitiveDoguRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(existingLdapConfig, nil)
+
+		recorder, clientSet := newTestRecorder()
+		dcw := cesDoguConfigWriter{
+			doguConfigRepo:          mockDoguRepo,
+			sensitiveDoguConfigRepo: mockSensitiveDoguRepo,
+			recorder:                recorder,
+		}
+
+		err := dcw.applyDefaultDoguConfig(testCtx, map[string]map[string]string{}, map[string]map[string]string{
+			"ldap": {"admin_password": "topSecret"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"DefaultsApplied Secret/ldap-config: Created 0 sensitive default keys, skipped 1 existing keys",
+		}, recordedEvents(t, clientSet))
+	})
+
+	t.Run("should store generated admin credentials", func(t *testing.T) {
+		ldapConfig := regLibConfig.CreateDoguConfig("ldap", make(regLibConfig.Entries))
+
+		mockDoguRepo := newMockDoguConfigRepo(t)
+		mockDoguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(ldapConfig, nil)
+		mockDoguRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(ldapConfig, nil)
+
+		mockSensitiveDoguRepo := newMockDoguConfigRepo(t)
+		mockSensitiveDoguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(ldapConfig, nil)
+		mockSensitiveDoguRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(ldapConfig, nil)
+
+		initialAdmin, secretClient := newTestInitialAdminWriter()
+		dcw := cesDoguConfigWriter{
+			doguConfigRepo:          mockDoguRepo,
+			sensitiveDoguConfigRepo: mockSensitiveDoguRepo,
+			initialAdmin:            initialAdmin,
+		}
+
+		err := dcw.applyDefaultDoguConfig(testCtx, map[string]map[string]string{
+			"ldap": {"admin_username": "admin"},
+		}, map[string]map[string]string{
+			"ldap": {"admin_password": "topSecret"},
+		})
+
+		require.NoError(t, err)
+		secret, err := secretClient.Get(testCtx, "initial-admin", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"username": "admin", "password": "topSecret"}, secret.StringData)
+	})
+
+	t.Run("should delete expired admin credentials if password already exists", func(t *testing.T) {
+		existingLdapConfig := regLibConfig.CreateDoguConfig("ldap", regLibConfig.Entries{"admin_password": "existing"})
+
+		mockSensitiveDoguRepo := newMockDoguConfigRepo(t)
+		mockSensitiveDoguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(existingLdapConfig, nil)
+		mockSensitiveDoguRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(existingLdapConfig, nil)
+
+		initialAdmin, secretClient := newTestInitialAdminWriter(initialAdminSecret("2026-01-01T00:00:00Z"))
+		dcw := cesDoguConfigWriter{
+			doguConfigRepo:          newMockDoguConfigRepo(t),
+			sensitiveDoguConfigRepo: mockSensitiveDoguRepo,
+			initialAdmin:            initialAdmin,
+		}
+
+		err := dcw.applyDefaultDoguConfig(testCtx, map[string]map[string]string{}, map[string]map[string]string{
+			"ldap": {"admin_password": "topSecret"},
+		})
+
+		require.NoError(t, err)
+		_, err = secretClient.Get(testCtx, "initial-admin", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
 	t.Run("should fail to apply default dogu & sensitive config on error in dogu config", func(t *testing.T) {
 		mockDoguRepo := newMockDoguConfigRepo(t)
 		mockDoguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("cas")).Return(emptyCasConfig, assert.AnError)
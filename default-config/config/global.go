@@ -4,11 +4,16 @@ import (
 	"context"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"log/slog"
 	"slices"
+	"time"
 
 	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/cloudogu/ecosystem-core/default-config/tracing"
 	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,6 +29,10 @@ const (
 	certificateExternalValue   = "external"
 )
 
+// ErrSelfSignedCertificate is returned if the profile rejects self-signed certificates, but the ecosystem-certificate
+// is missing or issued by the local issuer.
+var ErrSelfSignedCertificate = errors.New("self-signed certificates are rejected by the profile")
+
 type globalConfigRepo interface {
 	Get(ctx context.Context) (regLibConfig.GlobalConfig, error)
 	Create(ctx context.Context, globalConfig regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error)
@@ -31,18 +40,26 @@ type globalConfigRepo interface {
 }
 
 type cesGlobalConfigWriter struct {
-	globalConfigRepo globalConfigRepo
-	secretClient     secretClient
-	parseCertificate func(der []byte) (*x509.Certificate, error)
-	pemDecode        func(data []byte) (p *pem.Block, rest []byte)
+	globalConfigRepo   globalConfigRepo
+	secretClient       secretClient
+	certificateTimeout time.Duration
+	rejectSelfSigned   bool
+	summary            *report.Summary
+	recorder           *event.Recorder
+	parseCertificate   func(der []byte) (*x509.Certificate, error)
+	pemDecode          func(data []byte) (p *pem.Block, rest []byte)
 }
 
-func newCesGlobalConfigWriter(globalConfigRepo globalConfigRepo, secretClient secretClient) *cesGlobalConfigWriter {
+func newCesGlobalConfigWriter(globalConfigRepo globalConfigRepo, secretClient secretClient, certificateTimeout time.Duration, rejectSelfSigned bool, summary *report.Summary, recorder *event.Recorder) *cesGlobalConfigWriter {
 	return &cesGlobalConfigWriter{
-		globalConfigRepo: globalConfigRepo,
-		secretClient:     secretClient,
-		parseCertificate: x509.ParseCertificate,
-		pemDecode:        pem.Decode,
+		globalConfigRepo:   globalConfigRepo,
+		secretClient:       secretClient,
+		certificateTimeout: certificateTimeout,
+		rejectSelfSigned:   rejectSelfSigned,
+		summary:            summary,
+		recorder:           recorder,
+		parseCertificate:   x509.ParseCertificate,
+		pemDecode:          pem.Decode,
 	}
 }
 
@@ -56,11 +73,21 @@ func (gcw *cesGlobalConfigWriter) applyDefaultGlobalConfig(ctx context.Context,
 		}
 
 		globalConfig, err = gcw.globalConfigRepo.Create(ctx, regLibConfig.CreateGlobalConfig(make(regLibConfig.Entries)))
+		if cesLibErr.IsAlreadyExistsError(err) {
+			// another writer created the global config in the meantime
+			globalConfig, err = gcw.globalConfigRepo.Get(ctx)
+		}
 		if err != nil {
 			return fmt.Errorf("error creating new global config: %w", err)
 		}
 	}
 
+	proxyConfigured := slices.ContainsFunc(proxyKeys, func(key string) bool {
+		_, exists := globalConfig.Get(regLibConfig.Key(key))
+		return exists
+	})
+
+	var counts report.KeyCounts
 	for key, value := range defaultGlobalConfig {
 		cKey := regLibConfig.Key(key)
 		cValue := regLibConfig.Value(value)
@@ -68,6 +95,12 @@ func (gcw *cesGlobalConfigWriter) applyDefaultGlobalConfig(ctx context.Context,
 		_, exists := globalConfig.Get(cKey)
 		if exists {
 			slog.Info("Global config key already exists. Skipping...", "key", cKey.String())
+			counts.Skipped++
+			continue
+		}
+		if proxyConfigured && slices.Contains(proxyKeys, key) {
+			slog.Info("Global config key belongs to an already configured proxy. Skipping...", "key", cKey.String())
+			counts.Skipped++
 			continue
 		}
 
@@ -87,13 +120,15 @@ func (gcw *cesGlobalConfigWriter) applyDefaultGlobalConfig(ctx context.Context,
 		}
 
 		globalConfig = regLibConfig.GlobalConfig{Config: newGlobalConfig}
-
+		counts.Created++
 	}
 
 	_, err = gcw.globalConfigRepo.SaveOrMerge(ctx, globalConfig)
 	if err != nil {
 		return fmt.Errorf("failed to save global config: %w", err)
 	}
+	gcw.summary.AddKeys(report.RepoGlobal, counts)
+	gcw.recorder.Normal(ctx, event.GlobalConfig(), event.ReasonDefaultsApplied, "Created %d default keys, skipped %d existing keys", counts.Created, counts.Skipped)
 
 	slog.Info("...Successfully applied default-values to global config.")
 
@@ -101,16 +136,32 @@ func (gcw *cesGlobalConfigWriter) applyDefaultGlobalConfig(ctx context.Context,
 }
 
 func (gcw *cesGlobalConfigWriter) getCertificateType(ctx context.Context) (string, error) {
-	external, cErr := gcw.isExternalCertificate(ctx)
+	gcw.summary.EnterPhase(report.PhaseCertificate)
+	var external bool
+	cErr := withTimeout(ctx, gcw.certificateTimeout, func(ctx context.Context) error {
+		ctx, span := tracing.Start(ctx, string(report.PhaseCertificate))
+		var err error
+		external, err = gcw.isExternalCertificate(ctx)
+		tracing.End(span, err)
+		return err
+	})
 	if cErr != nil {
 		return "", fmt.Errorf("failed to verify external certificate: %w", cErr)
 	}
+	gcw.summary.EnterPhase(report.PhaseGlobalConfig)
+
+	if !external && gcw.rejectSelfSigned {
+		return "", fmt.Errorf("%w: install an external certificate as secret %s", ErrSelfSignedCertificate, ecosystemCertificateName)
+	}
 
+	certType := certificateSelfSignedValue
 	if external {
-		return certificateExternalValue, nil
+		certType = certificateExternalValue
 	}
 
-	return certificateSelfSignedValue, nil
+	gcw.recorder.Normal(ctx, event.GlobalConfig(), event.ReasonCertificateTypeDetected, "Detected certificate type %q", certType)
+
+	return certType, nil
 }
 
 func (gcw *cesGlobalConfigWriter) isExternalCertificate(ctx context.Context) (bool, error) {
@@ -6,14 +6,17 @@ import (
 	"crypto/x509/pkix"
 	"encoding/pem"
 	"testing"
+	"time"
 
 	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
 	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -81,13 +84,58 @@ func Test_cesGlobalConfigWriter_applyDefaultGlobalConfig(t *testing.T) {
 			return cfg, nil
 		})
 
+		summary := report.NewSummary()
 		gcw := cesGlobalConfigWriter{
 			globalConfigRepo: mockRepo,
+			summary:          summary,
 		}
 
 		err = gcw.applyDefaultGlobalConfig(testCtx, defaultConfig)
 
 		require.NoError(t, err)
+		assert.Equal(t, report.KeyCounts{Created: 1, Skipped: 1}, summary.Keys(report.RepoGlobal))
+	})
+
+	t.Run("should not apply the proxy keys if a proxy is already configured", func(t *testing.T) {
+		defaultConfig := map[string]string{
+			"key":                  "value",
+			"proxy/enabled":        "true",
+			"proxy/server":         "proxy.example.com",
+			"proxy/port":           "3128",
+			"proxy/no_proxy_hosts": "",
+		}
+
+		existingConfig := regLibConfig.CreateGlobalConfig(make(regLibConfig.Entries))
+		newExisting, err := existingConfig.Set("proxy/server", "other.example.com")
+		require.NoError(t, err)
+		existingConfig = regLibConfig.GlobalConfig{Config: newExisting}
+
+		mockRepo := newMockGlobalConfigRepo(t)
+		mockRepo.EXPECT().Get(testCtx).Return(existingConfig, nil)
+
+		mockRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).RunAndReturn(func(ctx context.Context, cfg regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error) {
+			assert.Len(t, cfg.GetAll(), 2)
+
+			val, exists := cfg.Get("proxy/server")
+			assert.True(t, exists)
+			assert.Equal(t, "other.example.com", val.String())
+
+			_, exists = cfg.Get("proxy/port")
+			assert.False(t, exists)
+
+			return cfg, nil
+		})
+
+		summary := report.NewSummary()
+		gcw := cesGlobalConfigWriter{
+			globalConfigRepo: mockRepo,
+			summary:          summary,
+		}
+
+		err = gcw.applyDefaultGlobalConfig(testCtx, defaultConfig)
+
+		require.NoError(t, err)
+		assert.Equal(t, report.KeyCounts{Created: 1, Skipped: 4}, summary.Keys(report.RepoGlobal))
 	})
 
 	t.Run("should create new global config if not exists", func(t *testing.T) {
@@ -125,6 +173,36 @@ func Test_cesGlobalConfigWriter_applyDefaultGlobalConfig(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("should read global config again if it was created concurrently", func(t *testing.T) {
+		defaultConfig := map[string]string{
+			"key": "value",
+		}
+
+		emptyConfig := regLibConfig.CreateGlobalConfig(make(regLibConfig.Entries))
+		concurrentConfig := regLibConfig.CreateGlobalConfig(regLibConfig.Entries{"key": "concurrent"})
+
+		mockRepo := newMockGlobalConfigRepo(t)
+		mockRepo.EXPECT().Get(testCtx).Return(emptyConfig, cesLibErr.NewNotFoundError(assert.AnError)).Once()
+		mockRepo.EXPECT().Create(testCtx, emptyConfig).Return(emptyConfig, cesLibErr.NewAlreadyExistsError(assert.AnError))
+		mockRepo.EXPECT().Get(testCtx).Return(concurrentConfig, nil).Once()
+
+		mockRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).RunAndReturn(func(ctx context.Context, cfg regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error) {
+			val, exists := cfg.Get("key")
+			assert.True(t, exists)
+			assert.Equal(t, "concurrent", val.String())
+
+			return cfg, nil
+		})
+
+		gcw := cesGlobalConfigWriter{
+			globalConfigRepo: mockRepo,
+		}
+
+		err := gcw.applyDefaultGlobalConfig(testCtx, defaultConfig)
+
+		require.NoError(t, err)
+	})
+
 	t.Run("should fail to apply default global config on error getting config", func(t *testing.T) {
 		defaultConfig := map[string]string{
 			"key": "value",
@@ -251,9 +329,11 @@ func Test_cesGlobalConfigWriter_applyDefaultGlobalConfig(t *testing.T) {
 			}, nil
 		}
 
+		recorder, clientSet := newTestRecorder()
 		gcw := cesGlobalConfigWriter{
 			globalConfigRepo: mockRepo,
 			secretClient:     secretClientMock,
+			recorder:         recorder,
 			parseCertificate: parseMock,
 			pemDecode: func(data []byte) (p *pem.Block, rest []byte) {
 				return &pem.Block{
@@ -267,6 +347,10 @@ func Test_cesGlobalConfigWriter_applyDefaultGlobalConfig(t *testing.T) {
 		err := gcw.applyDefaultGlobalConfig(testCtx, defaultConfig)
 
 		require.NoError(t, err)
+		assert.Equal(t, []string{
+			`CertificateTypeDetected ConfigMap/global-config: Detected certificate type "selfsigned"`,
+			"DefaultsApplied ConfigMap/global-config: Created 3 default keys, skipped 0 existing keys",
+		}, recordedEvents(t, clientSet))
 	})
 
 	t.Run("should set certificate type to self signed, when certificate not found", func(t *testing.T) {
@@ -314,6 +398,26 @@ func Test_cesGlobalConfigWriter_applyDefaultGlobalConfig(t *testing.T) {
 		require.NoError(t, err)
 	})
 
+	t.Run("should fail if the profile rejects self signed certificates and the certificate is not found", func(t *testing.T) {
+		mockRepo := newMockGlobalConfigRepo(t)
+		mockRepo.EXPECT().Get(testCtx).Return(regLibConfig.CreateGlobalConfig(make(regLibConfig.Entries)), nil)
+
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Get(testCtx, ecosystemCertificateName, mock.Anything).Return(nil, errors.NewNotFound(schema.GroupResource{}, "error"))
+
+		gcw := cesGlobalConfigWriter{
+			globalConfigRepo: mockRepo,
+			secretClient:     secretClientMock,
+			rejectSelfSigned: true,
+		}
+
+		err := gcw.applyDefaultGlobalConfig(testCtx, map[string]string{certificateConfigTypeKey: ""})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSelfSignedCertificate)
+		assert.ErrorContains(t, err, "install an external certificate as secret ecosystem-certificate")
+	})
+
 	t.Run("should set certificate type to self signed, when data key is not found", func(t *testing.T) {
 		defaultConfig := map[string]string{
 			certificateConfigTypeKey: "",
@@ -518,9 +622,11 @@ func Test_cesGlobalConfigWriter_applyDefaultGlobalConfig(t *testing.T) {
 		secretClientMock := newMockSecretClient(t)
 		secretClientMock.EXPECT().Get(testCtx, ecosystemCertificateName, mock.Anything).Return(nil, assert.AnError)
 
+		summary := report.NewSummary()
 		gcw := cesGlobalConfigWriter{
 			globalConfigRepo: mockRepo,
 			secretClient:     secretClientMock,
+			summary:          summary,
 			parseCertificate: nil,
 			pemDecode:        nil,
 		}
@@ -529,6 +635,37 @@ func Test_cesGlobalConfigWriter_applyDefaultGlobalConfig(t *testing.T) {
 
 		require.Error(t, err)
 		require.ErrorContains(t, err, "failed to get secret for ecosystem certificate")
+		assert.Equal(t, report.PhaseCertificate, summary.Phase())
+		assert.Equal(t, report.KeyCounts{}, summary.Keys(report.RepoGlobal))
+	})
+
+	t.Run("should fail when certificate cannot be received in time", func(t *testing.T) {
+		defaultConfig := map[string]string{
+			certificateConfigTypeKey: "",
+		}
+
+		emptyConfig := regLibConfig.CreateGlobalConfig(make(regLibConfig.Entries))
+
+		mockRepo := newMockGlobalConfigRepo(t)
+		mockRepo.EXPECT().Get(testCtx).Return(emptyConfig, nil)
+
+		secretClientMock := newMockSecretClient(t)
+		secretClientMock.EXPECT().Get(mock.Anything, ecosystemCertificateName, mock.Anything).RunAndReturn(func(ctx context.Context, _ string, _ metav1.GetOptions) (*corev1.Secret, error) {
+			<-ctx.Done()
+			return nil, assert.AnError
+		})
+
+		gcw := cesGlobalConfigWriter{
+			globalConfigRepo:   mockRepo,
+			secretClient:       secretClientMock,
+			certificateTimeout: time.Millisecond,
+		}
+
+		err := gcw.applyDefaultGlobalConfig(testCtx, defaultConfig)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "failed to get default value for certificate/type")
 	})
 
 	t.Run("should fail when parsing certificate returns error", func(t *testing.T) {
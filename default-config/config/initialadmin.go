@@ -0,0 +1,130 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// DefaultInitialAdminSecretName is the default name of the Secret with the initial credentials of the LDAP admin.
+	DefaultInitialAdminSecretName = "ecosystem-core-initial-admin"
+	// InitialAdminExpiresAtAnnotation contains the time in RFC 3339 after which the job deletes the Secret.
+	InitialAdminExpiresAtAnnotation = "k8s.cloudogu.com/expires-at"
+
+	initialAdminUsernameKey = "username"
+	initialAdminPasswordKey = "password"
+	ldapAdminUsernameKey    = "admin_username"
+)
+
+// InitialAdmin configures the Secret the initial credentials of the LDAP admin are stored in for the first login.
+type InitialAdmin struct {
+	SecretName string
+	// TTL is the time after which the Secret expires. The job deletes expired Secrets on its next run.
+	TTL time.Duration
+}
+
+type initialAdminWriter struct {
+	secretClient secretClient
+	secretName   string
+	ttl          time.Duration
+	recorder     *event.Recorder
+	now          func() time.Time
+}
+
+func newInitialAdminWriter(secretClient secretClient, initialAdmin *InitialAdmin, recorder *event.Recorder) *initialAdminWriter {
+	if initialAdmin == nil {
+		return nil
+	}
+
+	return &initialAdminWriter{
+		secretClient: secretClient,
+		secretName:   initialAdmin.SecretName,
+		ttl:          initialAdmin.TTL,
+		recorder:     recorder,
+		now:          time.Now,
+	}
+}
+
+// write stores the credentials. An existing Secret is replaced, because its password has been replaced by a new one.
+func (w *initialAdminWriter) write(ctx context.Context, username, password string) error {
+	expiresAt := w.now().Add(w.ttl).UTC()
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: w.secretName,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": event.Component,
+			},
+			Annotations: map[string]string{
+				InitialAdminExpiresAtAnnotation: expiresAt.Format(time.RFC3339),
+			},
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		StringData: map[string]string{
+			initialAdminUsernameKey: username,
+			initialAdminPasswordKey: password,
+		},
+	}
+
+	_, err := w.secretClient.Create(ctx, secret, metav1.CreateOptions{})
+	if apierrors.IsAlreadyExists(err) {
+		_, err = w.secretClient.Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to store initial admin credentials in secret %s: %w", w.secretName, err)
+	}
+
+	slog.Info("Stored the initial admin credentials", "secret", w.secretName, "expiresAt", expiresAt)
+	w.recorder.Normal(ctx, event.Secret(w.secretName), event.ReasonInitialAdminStored, "Stored the initial admin credentials until %s", expiresAt.Format(time.RFC3339))
+
+	return nil
+}
+
+// deleteExpired deletes the Secret if it has expired.
+func (w *initialAdminWriter) deleteExpired(ctx context.Context) error {
+	secret, err := w.secretClient.Get(ctx, w.secretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get secret %s: %w", w.secretName, err)
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, secret.Annotations[InitialAdminExpiresAtAnnotation])
+	if err != nil {
+		slog.Warn("Secret with initial admin credentials has no valid expiry", "secret", w.secretName, "err", err)
+		return nil
+	}
+	if w.now().Before(expiresAt) {
+		return nil
+	}
+
+	if _, err = DeleteInitialAdminSecret(ctx, w.secretClient, w.secretName); err != nil {
+		return err
+	}
+	w.recorder.Normal(ctx, event.Secret(w.secretName), event.ReasonInitialAdminExpired, "Deleted the expired initial admin credentials of secret %s", w.secretName)
+
+	return nil
+}
+
+// DeleteInitialAdminSecret deletes the Secret with the initial admin credentials. It returns false if the Secret
+// does not exist.
+func DeleteInitialAdminSecret(ctx context.Context, secretClient corev1client.SecretInterface, name string) (bool, error) {
+	err := secretClient.Delete(ctx, name, metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to delete secret %s: %w", name, err)
+	}
+
+	slog.Info("Deleted the initial admin credentials", "secret", name)
+	return true, nil
+}
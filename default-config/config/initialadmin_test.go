@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newTestInitialAdminWriter(secrets ...*corev1.Secret) (*initialAdminWriter, secretClient) {
+	clientSet := fake.NewClientset()
+	secretClient := clientSet.CoreV1().Secrets("ecosystem")
+	for _, secret := range secrets {
+		_, _ = secretClient.Create(context.Background(), secret, metav1.CreateOptions{})
+	}
+
+	writer := newInitialAdminWriter(secretClient, &InitialAdmin{SecretName: "initial-admin", TTL: 24 * time.Hour}, nil)
+	writer.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return writer, secretClient
+}
+
+func initialAdminSecret(expiresAt string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+		Name:        "initial-admin",
+		Annotations: map[string]string{InitialAdminExpiresAtAnnotation: expiresAt},
+	}}
+}
+
+func Test_newInitialAdminWriter(t *testing.T) {
+	assert.Nil(t, newInitialAdminWriter(newMockSecretClient(t), nil, nil))
+}
+
+func Test_initialAdminWriter_write(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should create secret with expiry", func(t *testing.T) {
+		writer, secretClient := newTestInitialAdminWriter()
+
+		err := writer.write(testCtx, "admin", "topSecret")
+
+		require.NoError(t, err)
+		secret, err := secretClient.Get(testCtx, "initial-admin", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "2026-01-03T03:04:05Z", secret.Annotations[InitialAdminExpiresAtAnnotation])
+		assert.Equal(t, corev1.SecretTypeBasicAuth, secret.Type)
+		assert.Equal(t, map[string]string{"username": "admin", "password": "topSecret"}, secret.StringData)
+	})
+
+	t.Run("should replace existing secret", func(t *testing.T) {
+		writer, secretClient := newTestInitialAdminWriter(initialAdminSecret("2025-01-01T00:00:00Z"))
+
+		err := writer.write(testCtx, "admin", "newSecret")
+
+		require.NoError(t, err)
+		secret, err := secretClient.Get(testCtx, "initial-admin", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "2026-01-03T03:04:05Z", secret.Annotations[InitialAdminExpiresAtAnnotation])
+		assert.Equal(t, "newSecret", secret.StringData["password"])
+	})
+
+	t.Run("should fail on api error", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Create(testCtx, mock.Anything, metav1.CreateOptions{}).Return(nil, assert.AnError)
+		writer := newInitialAdminWriter(mockSecretClient, &InitialAdmin{SecretName: "initial-admin"}, nil)
+
+		err := writer.write(testCtx, "admin", "topSecret")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to store initial admin credentials in secret initial-admin")
+	})
+}
+
+func Test_initialAdminWriter_deleteExpired(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should ignore missing secret", func(t *testing.T) {
+		writer, _ := newTestInitialAdminWriter()
+
+		require.NoError(t, writer.deleteExpired(testCtx))
+	})
+
+	t.Run("should keep secret that has not expired", func(t *testing.T) {
+		writer, secretClient := newTestInitialAdminWriter(initialAdminSecret("2026-01-03T00:00:00Z"))
+
+		require.NoError(t, writer.deleteExpired(testCtx))
+
+		_, err := secretClient.Get(testCtx, "initial-admin", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("should keep secret without valid expiry", func(t *testing.T) {
+		writer, secretClient := newTestInitialAdminWriter(initialAdminSecret("tomorrow"))
+
+		require.NoError(t, writer.deleteExpired(testCtx))
+
+		_, err := secretClient.Get(testCtx, "initial-admin", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("should delete expired secret", func(t *testing.T) {
+		writer, secretClient := newTestInitialAdminWriter(initialAdminSecret("2026-01-02T00:00:00Z"))
+
+		require.NoError(t, writer.deleteExpired(testCtx))
+
+		_, err := secretClient.Get(testCtx, "initial-admin", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
+
+func TestDeleteInitialAdminSecret(t *testing.T) {
+	testCtx := context.Background()
+	secretClient := fake.NewClientset(initialAdminSecret("")).CoreV1().Secrets("")
+
+	deleted, err := DeleteInitialAdminSecret(testCtx, secretClient, "initial-admin")
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	deleted, err = DeleteInitialAdminSecret(testCtx, secretClient, "initial-admin")
+	require.NoError(t, err)
+	assert.False(t, deleted)
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	casDogu                   = "cas"
+	casLdapConnectionDNKey    = "ldap/connection_dn"
+	casLdapPasswordKey        = "ldap/password"
+	defaultLdapPort           = 389
+	defaultLdapsPort          = 636
+	ldapEncryptionNone        = "none"
+	ldapEncryptionSSL         = "ssl"
+	ldapEncryptionSSLAny      = "sslAny"
+	ldapEncryptionStartTLS    = "startTLS"
+	ldapEncryptionStartTLSAny = "startTLSAny"
+)
+
+var ldapEncryptions = []string{ldapEncryptionNone, ldapEncryptionSSL, ldapEncryptionSSLAny, ldapEncryptionStartTLS, ldapEncryptionStartTLSAny}
+
+// ErrExternalLdapConfig is returned if the configuration of the external directory is invalid.
+var ErrExternalLdapConfig = errors.New("invalid external LDAP configuration")
+
+// ExternalLdap connects CAS to an existing LDAP or Active Directory instead of the ldap dogu.
+type ExternalLdap struct {
+	Host       string `json:"host"`
+	Port       int    `json:"port"`
+	Encryption string `json:"encryption"`
+	BaseDN     string `json:"baseDn"`
+	// SearchFilter and the attributes default to the values of an Active Directory.
+	SearchFilter      string    `json:"searchFilter"`
+	AttributeID       string    `json:"attributeId"`
+	AttributeMail     string    `json:"attributeMail"`
+	AttributeFullname string    `json:"attributeFullname"`
+	AttributeGroup    string    `json:"attributeGroup"`
+	BindSecret        SecretRef `json:"bindSecret"`
+}
+
+// ParseExternalLdap parses the configuration as YAML or JSON, sets the defaults and validates it.
+// An empty configuration returns nil, so that the embedded ldap dogu is used.
+func ParseExternalLdap(raw string) (*ExternalLdap, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	ldap := &ExternalLdap{}
+	if err := yaml.UnmarshalStrict([]byte(raw), ldap); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExternalLdapConfig, err)
+	}
+	ldap.setDefaults()
+
+	if err := ldap.validate(); err != nil {
+		return nil, err
+	}
+
+	return ldap, nil
+}
+
+func (l *ExternalLdap) setDefaults() {
+	if l.Encryption == "" {
+		l.Encryption = ldapEncryptionNone
+	}
+	if l.Port == 0 {
+		l.Port = defaultLdapPort
+		if l.Encryption == ldapEncryptionSSL || l.Encryption == ldapEncryptionSSLAny {
+			l.Port = defaultLdapsPort
+		}
+	}
+	if l.SearchFilter == "" {
+		l.SearchFilter = "(objectClass=person)"
+	}
+	if l.AttributeID == "" {
+		l.AttributeID = "sAMAccountName"
+	}
+	if l.AttributeMail == "" {
+		l.AttributeMail = "mail"
+	}
+	if l.AttributeFullname == "" {
+		l.AttributeFullname = "displayName"
+	}
+	if l.AttributeGroup == "" {
+		l.AttributeGroup = "memberOf"
+	}
+	l.BindSecret.setDefaults()
+}
+
+func (l *ExternalLdap) validate() error {
+	var errs []error
+	if l.Host == "" {
+		errs = append(errs, errors.New("host must be set"))
+	}
+	if l.Port < 1 || l.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d must be between 1 and 65535", l.Port))
+	}
+	if !slices.Contains(ldapEncryptions, l.Encryption) {
+		errs = append(errs, fmt.Errorf("encryption %q must be one of %v", l.Encryption, ldapEncryptions))
+	}
+	if l.BaseDN == "" {
+		errs = append(errs, errors.New("baseDn must be set"))
+	}
+	if l.BindSecret.Name == "" {
+		errs = append(errs, errors.New("bindSecret.name must be set"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrExternalLdapConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// casConfig returns the ldap keys of the CAS config.
+func (l *ExternalLdap) casConfig() map[string]string {
+	return map[string]string{
+		"ldap/ds_type":            "external",
+		"ldap/host":               l.Host,
+		"ldap/port":               strconv.Itoa(l.Port),
+		"ldap/encryption":         l.Encryption,
+		"ldap/base_dn":            l.BaseDN,
+		"ldap/search_filter":      l.SearchFilter,
+		"ldap/attribute_id":       l.AttributeID,
+		"ldap/attribute_mail":     l.AttributeMail,
+		"ldap/attribute_fullname": l.AttributeFullname,
+		"ldap/attribute_group":    l.AttributeGroup,
+	}
+}
+
+// bindCredentials reads the bind DN and the password from the Secret.
+func (l *ExternalLdap) bindCredentials(ctx context.Context, secretClient secretClient) (bindDN string, password string, err error) {
+	bindDN, password, err = l.BindSecret.readCredentials(ctx, secretClient, ErrExternalLdapConfig)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read bind credentials: %w", err)
+	}
+
+	return bindDN, password, nil
+}
+
+// externalLdapDoguDefaults returns the dogu defaults without the ldap dogu, whose CAS keys point to the external directory.
+func externalLdapDoguDefaults(ldap *ExternalLdap) map[string]map[string]string {
+	defaults := make(map[string]map[string]string, len(doguDefaults))
+	for dogu, config := range doguDefaults {
+		if dogu == ldapDogu {
+			continue
+		}
+		defaults[dogu] = config
+	}
+	defaults[casDogu] = ldap.casConfig()
+
+	return defaults
+}
@@ -0,0 +1,95 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseExternalLdap(t *testing.T) {
+	t.Run("should return nil without config", func(t *testing.T) {
+		ldap, err := ParseExternalLdap("")
+
+		require.NoError(t, err)
+		assert.Nil(t, ldap)
+	})
+
+	t.Run("should set the defaults of an active directory", func(t *testing.T) {
+		ldap, err := ParseExternalLdap(`{"host": "dc.example.com", "baseDn": "DC=example,DC=com", "bindSecret": {"name": "ldap-bind"}}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, &ExternalLdap{
+			Host:              "dc.example.com",
+			Port:              389,
+			Encryption:        "none",
+			BaseDN:            "DC=example,DC=com",
+			SearchFilter:      "(objectClass=person)",
+			AttributeID:       "sAMAccountName",
+			AttributeMail:     "mail",
+			AttributeFullname: "displayName",
+			AttributeGroup:    "memberOf",
+			BindSecret:        SecretRef{Name: "ldap-bind", UsernameKey: "username", PasswordKey: "password"},
+		}, ldap)
+	})
+
+	t.Run("should use the ldaps port for ssl", func(t *testing.T) {
+		ldap, err := ParseExternalLdap(`{"host": "dc.example.com", "encryption": "sslAny", "baseDn": "DC=example,DC=com", "bindSecret": {"name": "ldap-bind"}}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, 636, ldap.Port)
+	})
+
+	t.Run("should fail for invalid config", func(t *testing.T) {
+		_, err := ParseExternalLdap(`{"port": 70000, "encryption": "tls"}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrExternalLdapConfig)
+		assert.ErrorContains(t, err, "host must be set")
+		assert.ErrorContains(t, err, "port 70000 must be between 1 and 65535")
+		assert.ErrorContains(t, err, `encryption "tls" must be one of`)
+		assert.ErrorContains(t, err, "baseDn must be set")
+		assert.ErrorContains(t, err, "bindSecret.name must be set")
+	})
+
+	t.Run("should fail for unknown fields", func(t *testing.T) {
+		_, err := ParseExternalLdap(`{"hostname": "dc.example.com"}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrExternalLdapConfig)
+	})
+}
+
+func TestExternalLdap_bindCredentials(t *testing.T) {
+	testCtx := context.Background()
+	ldap := &ExternalLdap{BindSecret: SecretRef{Name: "ldap-bind", UsernameKey: "dn", PasswordKey: "pw"}}
+
+	t.Run("should read the bind credentials", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "ldap-bind", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"dn": []byte("CN=cas,DC=example,DC=com"), "pw": []byte("secret")},
+		}, nil)
+
+		bindDN, password, err := ldap.bindCredentials(testCtx, mockSecretClient)
+
+		require.NoError(t, err)
+		assert.Equal(t, "CN=cas,DC=example,DC=com", bindDN)
+		assert.Equal(t, "secret", password)
+	})
+
+	t.Run("should fail for missing key", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "ldap-bind", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"dn": []byte("CN=cas,DC=example,DC=com")},
+		}, nil)
+
+		_, _, err := ldap.bindCredentials(testCtx, mockSecretClient)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrExternalLdapConfig)
+		assert.ErrorContains(t, err, "failed to read bind credentials: invalid external LDAP configuration: secret ldap-bind has no key pw")
+	})
+}
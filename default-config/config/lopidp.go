@@ -0,0 +1,133 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// lopIdpDiscoveryPath is the default path of the OpenID discovery document of the lop-idp component below the FQDN
+	// of the ecosystem. LopIdp.DiscoveryURI overrides it if the component publishes the document elsewhere.
+	lopIdpDiscoveryPath          = "/lop-idp/.well-known/openid-configuration"
+	casOidcDiscoveryURIKey       = "oidc/discovery_uri"
+	casOidcClientIDKey           = "oidc/client_id"
+	casOidcClientSecretKey       = "oidc/client_secret"
+	defaultLopIdpClientID        = "cas"
+	defaultLopIdpClientIDKey     = "clientId"
+	defaultLopIdpClientSecretKey = "clientSecret"
+)
+
+// ErrLopIdpConfig is returned if the configuration that the LOP IdP requires on installation is missing.
+var ErrLopIdpConfig = errors.New("invalid LOP IdP configuration")
+
+// LopIdp configures CAS to delegate the authentication to the LOP IdP.
+type LopIdp struct {
+	// DiscoveryURI is the OpenID discovery document of the IdP. It defaults to the path of the lop-idp component below
+	// the FQDN of the ecosystem.
+	DiscoveryURI string `json:"discoveryUri"`
+	// ClientSecret contains the client ID and the client secret of CAS at the IdP. If its name is empty, the client ID
+	// "cas" is used and the client secret must be set in the sensitive CAS config manually.
+	ClientSecret SecretRef `json:"clientSecret"`
+}
+
+// ParseLopIdp parses the configuration as YAML or JSON, sets the defaults and validates it.
+// An empty configuration returns the defaults, because it is only parsed if the LOP IdP is used.
+func ParseLopIdp(raw string) (*LopIdp, error) {
+	idp := &LopIdp{}
+	if err := yaml.UnmarshalStrict([]byte(raw), idp); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLopIdpConfig, err)
+	}
+	idp.setDefaults()
+
+	if err := idp.validate(); err != nil {
+		return nil, err
+	}
+
+	return idp, nil
+}
+
+func (i *LopIdp) setDefaults() {
+	// the keys of the Secret are named after the client, not after a user
+	if i.ClientSecret.UsernameKey == "" {
+		i.ClientSecret.UsernameKey = defaultLopIdpClientIDKey
+	}
+	if i.ClientSecret.PasswordKey == "" {
+		i.ClientSecret.PasswordKey = defaultLopIdpClientSecretKey
+	}
+}
+
+func (i *LopIdp) validate() error {
+	if i.DiscoveryURI == "" {
+		return nil
+	}
+
+	u, err := url.Parse(i.DiscoveryURI)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("%w: discoveryUri %q must be an https URL", ErrLopIdpConfig, i.DiscoveryURI)
+	}
+
+	return nil
+}
+
+// discoveryURI returns the configured discovery URI or the one of the lop-idp component below the FQDN.
+func (i *LopIdp) discoveryURI(fqdn string) string {
+	if i.DiscoveryURI != "" {
+		return i.DiscoveryURI
+	}
+
+	return "https://" + fqdn + lopIdpDiscoveryPath
+}
+
+// clientCredentials reads the client ID and the client secret of CAS from the Secret.
+func (i *LopIdp) clientCredentials(ctx context.Context, secretClient secretClient) (clientID string, clientSecret string, err error) {
+	clientID, clientSecret, err = i.ClientSecret.readCredentials(ctx, secretClient, ErrLopIdpConfig)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to read OIDC client credentials: %w", err)
+	}
+
+	return clientID, clientSecret, nil
+}
+
+// ValidateLopIdp verifies that the initial domain and FQDN are set. The LOP IdP does not support changing them after the
+// installation, e.g. the domain is part of the LDAP entries of the users.
+func ValidateLopIdp(initialDomain, initialFQDN string) error {
+	var errs []error
+	if initialDomain == "" {
+		errs = append(errs, errors.New("INITIAL_DOMAIN must be set if the LOP IdP is used"))
+	}
+	if initialFQDN == "" {
+		errs = append(errs, errors.New("INITIAL_FQDN must be set if the LOP IdP is used"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrLopIdpConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// lopIdpDoguDefaults are applied instead of doguDefaults if the LOP IdP is used. The users log in at the LOP IdP,
+// so CAS delegates the authentication via OIDC and there is no embedded LDAP whose admin password is generated.
+// The clients of the dogus are registered at the IdP by the auth registration, not in the dogu config. The client
+// credentials of CAS itself are read from the Secret of the configuration, see DefaultConfigApplier.applyLopIdp.
+func lopIdpDoguDefaults(idp *LopIdp, fqdn string) map[string]map[string]string {
+	return map[string]map[string]string{
+		"postfix": {
+			"relayhost": "n/a",
+		},
+		"cas": {
+			"oidc/enabled":             "true",
+			"oidc/optional":            "false",
+			casOidcDiscoveryURIKey:     idp.discoveryURI(fqdn),
+			casOidcClientIDKey:         defaultLopIdpClientID,
+			"oidc/display_name":        "LOP IdP",
+			"oidc/scopes":              "openid email profile groups",
+			"oidc/principal_attribute": "preferred_username",
+			"oidc/attribute_mapping":   "email:mail,family_name:surname,given_name:givenName,preferred_username:username,name:displayName,groups:externalGroups",
+		},
+	}
+}
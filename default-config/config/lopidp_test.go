@@ -0,0 +1,107 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestValidateLopIdp(t *testing.T) {
+	t.Run("should accept initial domain and fqdn", func(t *testing.T) {
+		require.NoError(t, ValidateLopIdp("example.com", "ces.example.com"))
+	})
+
+	t.Run("should reject missing initial domain and fqdn", func(t *testing.T) {
+		err := ValidateLopIdp("", "")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrLopIdpConfig)
+		assert.ErrorContains(t, err, "INITIAL_DOMAIN must be set if the LOP IdP is used")
+		assert.ErrorContains(t, err, "INITIAL_FQDN must be set if the LOP IdP is used")
+	})
+}
+
+func TestParseLopIdp(t *testing.T) {
+	t.Run("should set the defaults without config", func(t *testing.T) {
+		idp, err := ParseLopIdp("")
+
+		require.NoError(t, err)
+		assert.Equal(t, &LopIdp{ClientSecret: SecretRef{UsernameKey: "clientId", PasswordKey: "clientSecret"}}, idp)
+	})
+
+	t.Run("should parse the discovery uri and the client secret", func(t *testing.T) {
+		idp, err := ParseLopIdp(`{"discoveryUri": "https://idp.example.com/.well-known/openid-configuration", "clientSecret": {"name": "lop-idp-cas", "passwordKey": "secret"}}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://idp.example.com/.well-known/openid-configuration", idp.DiscoveryURI)
+		assert.Equal(t, SecretRef{Name: "lop-idp-cas", UsernameKey: "clientId", PasswordKey: "secret"}, idp.ClientSecret)
+	})
+
+	t.Run("should fail for a discovery uri without https", func(t *testing.T) {
+		_, err := ParseLopIdp(`{"discoveryUri": "http://idp.example.com/.well-known/openid-configuration"}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrLopIdpConfig)
+		assert.ErrorContains(t, err, "must be an https URL")
+	})
+
+	t.Run("should fail for unknown fields", func(t *testing.T) {
+		_, err := ParseLopIdp(`{"clientId": "cas"}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrLopIdpConfig)
+	})
+}
+
+func TestLopIdp_clientCredentials(t *testing.T) {
+	testCtx := context.Background()
+	idp := &LopIdp{ClientSecret: SecretRef{Name: "lop-idp-cas", UsernameKey: "clientId", PasswordKey: "clientSecret"}}
+
+	t.Run("should read the client credentials", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "lop-idp-cas", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"clientId": []byte("cas"), "clientSecret": []byte("secret")},
+		}, nil)
+
+		clientID, clientSecret, err := idp.clientCredentials(testCtx, mockSecretClient)
+
+		require.NoError(t, err)
+		assert.Equal(t, "cas", clientID)
+		assert.Equal(t, "secret", clientSecret)
+	})
+
+	t.Run("should fail for missing key", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "lop-idp-cas", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"clientId": []byte("cas")},
+		}, nil)
+
+		_, _, err := idp.clientCredentials(testCtx, mockSecretClient)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrLopIdpConfig)
+		assert.ErrorContains(t, err, "failed to read OIDC client credentials: invalid LOP IdP configuration: secret lop-idp-cas has no key clientSecret")
+	})
+}
+
+func Test_lopIdpDoguDefaults(t *testing.T) {
+	t.Run("should use the discovery document of the lop-idp component below the fqdn", func(t *testing.T) {
+		defaults := lopIdpDoguDefaults(&LopIdp{}, "ces.example.com")
+
+		assert.Equal(t, "https://ces.example.com/lop-idp/.well-known/openid-configuration", defaults["cas"]["oidc/discovery_uri"])
+		assert.Equal(t, "cas", defaults["cas"]["oidc/client_id"])
+		assert.Equal(t, "true", defaults["cas"]["oidc/enabled"])
+		assert.Equal(t, "n/a", defaults["postfix"]["relayhost"])
+		assert.NotContains(t, defaults, ldapDogu)
+	})
+
+	t.Run("should use the configured discovery uri", func(t *testing.T) {
+		defaults := lopIdpDoguDefaults(&LopIdp{DiscoveryURI: "https://idp.example.com/.well-known/openid-configuration"}, "ces.example.com")
+
+		assert.Equal(t, "https://idp.example.com/.well-known/openid-configuration", defaults["cas"]["oidc/discovery_uri"])
+	})
+}
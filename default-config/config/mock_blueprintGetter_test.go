@@ -0,0 +1,114 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package config
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mockBlueprintGetter is an autogenerated mock type for the blueprintGetter type
+type mockBlueprintGetter struct {
+	mock.Mock
+}
+
+type mockBlueprintGetter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockBlueprintGetter) EXPECT() *mockBlueprintGetter_Expecter {
+	return &mockBlueprintGetter_Expecter{mock: &_m.Mock}
+}
+
+// Get provides a mock function with given fields: ctx, name, options, subresources
+func (_m *mockBlueprintGetter) Get(ctx context.Context, name string, options v1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	_va := make([]interface{}, len(subresources))
+	for _i := range subresources {
+		_va[_i] = subresources[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, name, options)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *unstructured.Unstructured
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, v1.GetOptions, ...string) (*unstructured.Unstructured, error)); ok {
+		return rf(ctx, name, options, subresources...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, v1.GetOptions, ...string) *unstructured.Unstructured); ok {
+		r0 = rf(ctx, name, options, subresources...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.Unstructured)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, v1.GetOptions, ...string) error); ok {
+		r1 = rf(ctx, name, options, subresources...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockBlueprintGetter_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockBlueprintGetter_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - options v1.GetOptions
+//   - subresources ...string
+func (_e *mockBlueprintGetter_Expecter) Get(ctx interface{}, name interface{}, options interface{}, subresources ...interface{}) *mockBlueprintGetter_Get_Call {
+	return &mockBlueprintGetter_Get_Call{Call: _e.mock.On("Get",
+		append([]interface{}{ctx, name, options}, subresources...)...)}
+}
+
+func (_c *mockBlueprintGetter_Get_Call) Run(run func(ctx context.Context, name string, options v1.GetOptions, subresources ...string)) *mockBlueprintGetter_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-3)
+		for i, a := range args[3:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(v1.GetOptions), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockBlueprintGetter_Get_Call) Return(_a0 *unstructured.Unstructured, _a1 error) *mockBlueprintGetter_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockBlueprintGetter_Get_Call) RunAndReturn(run func(context.Context, string, v1.GetOptions, ...string) (*unstructured.Unstructured, error)) *mockBlueprintGetter_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockBlueprintGetter creates a new instance of mockBlueprintGetter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockBlueprintGetter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockBlueprintGetter {
+	mock := &mockBlueprintGetter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
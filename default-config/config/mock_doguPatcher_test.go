@@ -0,0 +1,118 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package config
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	types "k8s.io/apimachinery/pkg/types"
+
+	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mockDoguPatcher is an autogenerated mock type for the doguPatcher type
+type mockDoguPatcher struct {
+	mock.Mock
+}
+
+type mockDoguPatcher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockDoguPatcher) EXPECT() *mockDoguPatcher_Expecter {
+	return &mockDoguPatcher_Expecter{mock: &_m.Mock}
+}
+
+// Patch provides a mock function with given fields: ctx, name, pt, data, options, subresources
+func (_m *mockDoguPatcher) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options v1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error) {
+	_va := make([]interface{}, len(subresources))
+	for _i := range subresources {
+		_va[_i] = subresources[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, name, pt, data, options)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
+	}
+
+	var r0 *unstructured.Unstructured
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) (*unstructured.Unstructured, error)); ok {
+		return rf(ctx, name, pt, data, options, subresources...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) *unstructured.Unstructured); ok {
+		r0 = rf(ctx, name, pt, data, options, subresources...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*unstructured.Unstructured)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) error); ok {
+		r1 = rf(ctx, name, pt, data, options, subresources...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDoguPatcher_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type mockDoguPatcher_Patch_Call struct {
+	*mock.Call
+}
+
+// Patch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - pt types.PatchType
+//   - data []byte
+//   - options v1.PatchOptions
+//   - subresources ...string
+func (_e *mockDoguPatcher_Expecter) Patch(ctx interface{}, name interface{}, pt interface{}, data interface{}, options interface{}, subresources ...interface{}) *mockDoguPatcher_Patch_Call {
+	return &mockDoguPatcher_Patch_Call{Call: _e.mock.On("Patch",
+		append([]interface{}{ctx, name, pt, data, options}, subresources...)...)}
+}
+
+func (_c *mockDoguPatcher_Patch_Call) Run(run func(ctx context.Context, name string, pt types.PatchType, data []byte, options v1.PatchOptions, subresources ...string)) *mockDoguPatcher_Patch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-5)
+		for i, a := range args[5:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), args[1].(string), args[2].(types.PatchType), args[3].([]byte), args[4].(v1.PatchOptions), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *mockDoguPatcher_Patch_Call) Return(_a0 *unstructured.Unstructured, _a1 error) *mockDoguPatcher_Patch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDoguPatcher_Patch_Call) RunAndReturn(run func(context.Context, string, types.PatchType, []byte, v1.PatchOptions, ...string) (*unstructured.Unstructured, error)) *mockDoguPatcher_Patch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockDoguPatcher creates a new instance of mockDoguPatcher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockDoguPatcher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockDoguPatcher {
+	mock := &mockDoguPatcher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// FQDN strategies of a profile.
+const (
+	// FQDNFromLoadBalancer enables the fqdn applier, which sets the external ip of the load balancer as fqdn.
+	FQDNFromLoadBalancer = "loadBalancer"
+	// FQDNInitial requires the fqdn to be set with INITIAL_FQDN.
+	FQDNInitial = "initial"
+)
+
+//go:embed profiles.yaml
+var builtinProfiles []byte
+
+// ErrInvalidProfile is returned if the profile is unknown, cannot be parsed or its requirements are not met.
+var ErrInvalidProfile = errors.New("invalid profile")
+
+// Profile bundles the defaults and settings for an environment, e.g. development or production.
+// The zero value applies the built-in defaults only.
+type Profile struct {
+	Name string `json:"-"`
+	// GlobalConfig overrides the built-in global defaults.
+	GlobalConfig map[string]string `json:"globalConfig"`
+	// DoguConfig overrides the built-in dogu defaults per key.
+	DoguConfig map[string]map[string]string `json:"doguConfig"`
+	// FQDN is FQDNFromLoadBalancer or FQDNInitial. ENABLE_FQDN_APPLY is used if empty.
+	FQDN string `json:"fqdn"`
+	// RequireInitialDomain requires the domain to be set with INITIAL_DOMAIN.
+	RequireInitialDomain bool `json:"requireInitialDomain"`
+	// RejectSelfSignedCertificate fails the job if the ecosystem-certificate is missing or self-signed.
+	RejectSelfSignedCertificate bool `json:"rejectSelfSignedCertificate"`
+}
+
+// LoadProfile returns the profile with the name. The profiles of the file replace the compiled-in profiles of the
+// same name; a missing file is ignored. An empty name returns the zero Profile.
+func LoadProfile(name, path string) (Profile, error) {
+	if name == "" {
+		return Profile{}, nil
+	}
+
+	profiles, err := ParseProfiles(builtinProfiles)
+	if err != nil {
+		return Profile{}, err
+	}
+
+	if path != "" {
+		raw, err := os.ReadFile(path)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			slog.Debug("profiles file does not exist, using compiled-in profiles", "path", path)
+		case err != nil:
+			return Profile{}, fmt.Errorf("%w: failed to read %s: %w", ErrInvalidProfile, path, err)
+		default:
+			fileProfiles, err := ParseProfiles(raw)
+			if err != nil {
+				return Profile{}, fmt.Errorf("%s: %w", path, err)
+			}
+			maps.Copy(profiles, fileProfiles)
+		}
+	}
+
+	profile, ok := profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("%w: unknown profile %q, known profiles are %s", ErrInvalidProfile, name, strings.Join(slices.Sorted(maps.Keys(profiles)), ", "))
+	}
+
+	return profile, nil
+}
+
+// ParseProfiles parses a YAML map of the profile names to the profiles.
+func ParseProfiles(raw []byte) (map[string]Profile, error) {
+	var profiles map[string]Profile
+	if err := yaml.UnmarshalStrict(raw, &profiles); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse profiles: %w", ErrInvalidProfile, err)
+	}
+
+	for name, profile := range profiles {
+		if profile.FQDN != "" && profile.FQDN != FQDNFromLoadBalancer && profile.FQDN != FQDNInitial {
+			return nil, fmt.Errorf("%w: profile %s: fqdn must be %q or %q", ErrInvalidProfile, name, FQDNFromLoadBalancer, FQDNInitial)
+		}
+		profile.Name = name
+		profiles[name] = profile
+	}
+
+	return profiles, nil
+}
+
+// Validate verifies the requirements of the profile on the initial domain, fqdn and internal ip. The internal ip is
+// required if the profile lets the dogus use it, otherwise they would use an empty address.
+func (p Profile) Validate(initialDomain, initialFQDN, initialInternalIP string) error {
+	var errs []error
+	if p.RequireInitialDomain && initialDomain == "" {
+		errs = append(errs, fmt.Errorf("INITIAL_DOMAIN must be set for profile %s", p.Name))
+	}
+	if p.FQDN == FQDNInitial && initialFQDN == "" {
+		errs = append(errs, fmt.Errorf("INITIAL_FQDN must be set for profile %s", p.Name))
+	}
+	if p.GlobalConfig[useInternalIPKey] == "true" && initialInternalIP == "" {
+		errs = append(errs, fmt.Errorf("INITIAL_INTERNAL_IP must be set for profile %s, because it sets %s", p.Name, useInternalIPKey))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidProfile, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// FQDNApplyEnabled returns whether the fqdn applier runs. enableFqdnApply is used if the profile has no FQDN strategy.
+func (p Profile) FQDNApplyEnabled(enableFqdnApply bool) bool {
+	switch p.FQDN {
+	case FQDNFromLoadBalancer:
+		return true
+	case FQDNInitial:
+		return false
+	default:
+		return enableFqdnApply
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadProfile(t *testing.T) {
+	t.Run("should return zero profile without name", func(t *testing.T) {
+		profile, err := LoadProfile("", "")
+
+		require.NoError(t, err)
+		assert.Equal(t, Profile{}, profile)
+	})
+
+	t.Run("should load compiled-in profiles", func(t *testing.T) {
+		for _, name := range []string{"development", "production", "airgapped"} {
+			profile, err := LoadProfile(name, filepath.Join(t.TempDir(), "missing.yaml"))
+
+			require.NoError(t, err, name)
+			assert.Equal(t, name, profile.Name)
+		}
+
+		production, err := LoadProfile("production", "")
+		require.NoError(t, err)
+		assert.True(t, production.RejectSelfSignedCertificate)
+		assert.Equal(t, FQDNInitial, production.FQDN)
+
+		development, err := LoadProfile("development", "")
+		require.NoError(t, err)
+		assert.Equal(t, FQDNFromLoadBalancer, development.FQDN)
+		assert.Equal(t, "8", development.GlobalConfig["password-policy/min_length"])
+	})
+
+	t.Run("should replace compiled-in profile and add profiles from file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "profiles.yaml")
+		require.NoError(t, os.WriteFile(path, []byte(`
+production:
+  fqdn: loadBalancer
+staging:
+  doguConfig:
+    postfix:
+      relayhost: mail.example.com
+`), 0o600))
+
+		production, err := LoadProfile("production", path)
+		require.NoError(t, err)
+		assert.Equal(t, Profile{Name: "production", FQDN: FQDNFromLoadBalancer}, production)
+
+		staging, err := LoadProfile("staging", path)
+		require.NoError(t, err)
+		assert.Equal(t, "mail.example.com", staging.DoguConfig["postfix"]["relayhost"])
+	})
+
+	t.Run("should fail for unknown profile", func(t *testing.T) {
+		_, err := LoadProfile("testing", "")
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidProfile)
+		assert.ErrorContains(t, err, `unknown profile "testing", known profiles are airgapped, development, production`)
+	})
+
+	t.Run("should fail for invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "profiles.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("production:\n  fqdn: dns\n"), 0o600))
+
+		_, err := LoadProfile("production", path)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidProfile)
+		assert.ErrorContains(t, err, `profile production: fqdn must be "loadBalancer" or "initial"`)
+	})
+
+	t.Run("should fail for unknown fields", func(t *testing.T) {
+		_, err := ParseProfiles([]byte("production:\n  globalDefaults: {}\n"))
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidProfile)
+	})
+}
+
+func TestProfile_Validate(t *testing.T) {
+	profile := Profile{Name: "production", FQDN: FQDNInitial, RequireInitialDomain: true}
+
+	require.NoError(t, profile.Validate("example.com", "ces.example.com", ""))
+	require.NoError(t, Profile{}.Validate("", "", ""))
+
+	err := profile.Validate("", "", "")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidProfile)
+	assert.ErrorContains(t, err, "INITIAL_DOMAIN must be set for profile production")
+	assert.ErrorContains(t, err, "INITIAL_FQDN must be set for profile production")
+
+	airgapped := Profile{Name: "airgapped", GlobalConfig: map[string]string{"k8s/use_internal_ip": "true"}}
+	require.NoError(t, airgapped.Validate("", "", "10.0.0.10"))
+	err = airgapped.Validate("", "", "")
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrInvalidProfile)
+	assert.ErrorContains(t, err, "INITIAL_INTERNAL_IP must be set for profile airgapped, because it sets k8s/use_internal_ip")
+}
+
+func TestProfile_FQDNApplyEnabled(t *testing.T) {
+	assert.True(t, Profile{FQDN: FQDNFromLoadBalancer}.FQDNApplyEnabled(false))
+	assert.False(t, Profile{FQDN: FQDNInitial}.FQDNApplyEnabled(true))
+	assert.True(t, Profile{}.FQDNApplyEnabled(true))
+	assert.False(t, Profile{}.FQDNApplyEnabled(false))
+}
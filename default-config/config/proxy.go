@@ -0,0 +1,124 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	proxyEnabledKey      = "proxy/enabled"
+	proxyServerKey       = "proxy/server"
+	proxyPortKey         = "proxy/port"
+	proxyNoProxyHostsKey = "proxy/no_proxy_hosts"
+	proxyUsernameKey     = "proxy/username"
+	proxyPasswordKey     = "proxy/password"
+)
+
+// proxyKeys are written together: if one of them is already set, none of them is written, so that a proxy configured
+// in the global config is never mixed with the proxy of the job.
+var proxyKeys = []string{proxyEnabledKey, proxyServerKey, proxyPortKey, proxyNoProxyHostsKey, proxyUsernameKey, proxyPasswordKey}
+
+// ErrProxyConfig is returned if the configuration of the proxy is invalid.
+var ErrProxyConfig = errors.New("invalid proxy configuration")
+
+var hostnamePattern = regexp.MustCompile(`^(?i)[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// Proxy configures the HTTP proxy the dogus use to reach external services, e.g. the dogu and Helm registries.
+// The values are written to the global config, but never to the log or to events.
+type Proxy struct {
+	// Server is the hostname or the IP address of the proxy without scheme and port.
+	Server string `json:"server"`
+	Port   int    `json:"port"`
+	// NoProxyHosts are reached without the proxy, e.g. "localhost", ".cluster.local" or "10.0.0.0/8".
+	NoProxyHosts []string `json:"noProxyHosts"`
+	// CredentialsSecret contains the credentials of the proxy. The proxy is used without authentication if its name is empty.
+	CredentialsSecret SecretRef `json:"credentialsSecret"`
+}
+
+// ParseProxy parses the configuration as YAML or JSON, sets the defaults and validates it.
+// An empty configuration returns nil, so that no proxy is configured.
+func ParseProxy(raw string) (*Proxy, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	proxy := &Proxy{}
+	if err := yaml.UnmarshalStrict([]byte(raw), proxy); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrProxyConfig, err)
+	}
+	proxy.CredentialsSecret.setDefaults()
+
+	if err := proxy.validate(); err != nil {
+		return nil, err
+	}
+
+	return proxy, nil
+}
+
+func (p *Proxy) validate() error {
+	// the values are not part of the errors, because the job logs them
+	var errs []error
+	if !isHost(p.Server) {
+		errs = append(errs, errors.New("server must be a hostname or an IP address without scheme and port"))
+	}
+	if p.Port < 1 || p.Port > 65535 {
+		errs = append(errs, errors.New("port must be between 1 and 65535"))
+	}
+	for i, host := range p.NoProxyHosts {
+		if !isNoProxyHost(host) {
+			errs = append(errs, fmt.Errorf("noProxyHosts[%d] must be a hostname, a domain starting with '.', an IP address or a CIDR", i))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrProxyConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func isHost(host string) bool {
+	return net.ParseIP(host) != nil || (len(host) <= 253 && hostnamePattern.MatchString(host))
+}
+
+func isNoProxyHost(host string) bool {
+	if _, _, err := net.ParseCIDR(host); err == nil {
+		return true
+	}
+	return isHost(strings.TrimPrefix(host, "."))
+}
+
+// globalConfig returns the keys of the global config without the credentials.
+func (p *Proxy) globalConfig() map[string]string {
+	return map[string]string{
+		proxyEnabledKey:      "true",
+		proxyServerKey:       p.Server,
+		proxyPortKey:         strconv.Itoa(p.Port),
+		proxyNoProxyHostsKey: strings.Join(p.NoProxyHosts, ","),
+	}
+}
+
+// credentialsConfig reads the credentials from the Secret and returns them as keys of the global config.
+// It returns nil if no Secret is referenced.
+func (p *Proxy) credentialsConfig(ctx context.Context, secretClient secretClient) (map[string]string, error) {
+	if p.CredentialsSecret.Name == "" {
+		return nil, nil
+	}
+
+	username, password, err := p.CredentialsSecret.readCredentials(ctx, secretClient, ErrProxyConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read proxy credentials: %w", err)
+	}
+
+	return map[string]string{
+		proxyUsernameKey: username,
+		proxyPasswordKey: password,
+	}, nil
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseProxy(t *testing.T) {
+	t.Run("should return nil without config", func(t *testing.T) {
+		proxy, err := ParseProxy("")
+
+		require.NoError(t, err)
+		assert.Nil(t, proxy)
+	})
+
+	t.Run("should set the defaults", func(t *testing.T) {
+		proxy, err := ParseProxy(`{"server": "proxy.example.com", "port": 3128, "noProxyHosts": ["localhost", ".cluster.local", "10.0.0.0/8", "192.168.1.10"]}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, &Proxy{
+			Server:            "proxy.example.com",
+			Port:              3128,
+			NoProxyHosts:      []string{"localhost", ".cluster.local", "10.0.0.0/8", "192.168.1.10"},
+			CredentialsSecret: SecretRef{UsernameKey: "username", PasswordKey: "password"},
+		}, proxy)
+	})
+
+	t.Run("should accept an ip address as server", func(t *testing.T) {
+		proxy, err := ParseProxy(`{"server": "fd00::1", "port": 8080}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, "fd00::1", proxy.Server)
+	})
+
+	t.Run("should fail for invalid config without the values", func(t *testing.T) {
+		_, err := ParseProxy(`{"server": "http://proxy.example.com:3128", "port": 70000, "noProxyHosts": ["localhost", "*.example.com", "10.0.0.0/33"]}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrProxyConfig)
+		assert.ErrorContains(t, err, "server must be a hostname or an IP address without scheme and port")
+		assert.ErrorContains(t, err, "port must be between 1 and 65535")
+		assert.ErrorContains(t, err, "noProxyHosts[1] must be")
+		assert.ErrorContains(t, err, "noProxyHosts[2] must be")
+		assert.NotContains(t, err.Error(), "noProxyHosts[0]")
+		assert.NotContains(t, err.Error(), "proxy.example.com")
+		assert.NotContains(t, err.Error(), "70000")
+	})
+
+	t.Run("should fail without server and port", func(t *testing.T) {
+		_, err := ParseProxy(`{"noProxyHosts": []}`)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "server must be")
+		assert.ErrorContains(t, err, "port must be")
+	})
+
+	t.Run("should fail for unknown fields", func(t *testing.T) {
+		_, err := ParseProxy(`{"host": "proxy.example.com", "port": 3128}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrProxyConfig)
+	})
+}
+
+func TestProxy_globalConfig(t *testing.T) {
+	proxy := &Proxy{Server: "proxy.example.com", Port: 3128, NoProxyHosts: []string{"localhost", ".cluster.local"}}
+
+	assert.Equal(t, map[string]string{
+		"proxy/enabled":        "true",
+		"proxy/server":         "proxy.example.com",
+		"proxy/port":           "3128",
+		"proxy/no_proxy_hosts": "localhost,.cluster.local",
+	}, proxy.globalConfig())
+}
+
+func TestProxy_credentialsConfig(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should return nil without secret", func(t *testing.T) {
+		config, err := (&Proxy{}).credentialsConfig(testCtx, newMockSecretClient(t))
+
+		require.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
+	t.Run("should read the credentials", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "proxy-credentials", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"user": []byte("ces"), "password": []byte("secret")},
+		}, nil)
+		proxy := &Proxy{CredentialsSecret: SecretRef{Name: "proxy-credentials", UsernameKey: "user", PasswordKey: "password"}}
+
+		config, err := proxy.credentialsConfig(testCtx, mockSecretClient)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"proxy/username": "ces", "proxy/password": "secret"}, config)
+	})
+
+	t.Run("should fail for missing key", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "proxy-credentials", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"username": []byte("ces")},
+		}, nil)
+		proxy := &Proxy{CredentialsSecret: SecretRef{Name: "proxy-credentials", UsernameKey: "username", PasswordKey: "password"}}
+
+		_, err := proxy.credentialsConfig(testCtx, mockSecretClient)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrProxyConfig)
+		assert.ErrorContains(t, err, "failed to read proxy credentials: invalid proxy configuration: secret proxy-credentials has no key password")
+	})
+}
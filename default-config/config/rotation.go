@@ -0,0 +1,190 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	passwordPolicyMinLengthKey = "password-policy/min_length"
+	// AdminPasswordRotatedAtKey is the key of the ldap dogu config with the time of the last rotation in RFC 3339.
+	AdminPasswordRotatedAtKey = "admin_password_rotated_at"
+	// AdminPasswordRotatedAtAnnotation notifies the ldap dogu about a rotation. It contains the time in RFC 3339.
+	AdminPasswordRotatedAtAnnotation = "k8s.cloudogu.com/admin-password-rotated-at"
+
+	storedPasswordCheckTimeout = 10 * time.Second
+)
+
+// DoguResource is the resource of the Dogu CRs.
+var DoguResource = schema.GroupVersionResource{Group: "k8s.cloudogu.com", Version: "v1", Resource: "dogus"}
+
+type doguConfigUpdater interface {
+	Get(ctx context.Context, name cesLibDogu.SimpleName) (regLibConfig.DoguConfig, error)
+	Update(ctx context.Context, doguConfig regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error)
+}
+
+type doguPatcher interface {
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, options metav1.PatchOptions, subresources ...string) (*unstructured.Unstructured, error)
+}
+
+// AdminPasswordRotator replaces the password of the LDAP admin in the sensitive config of the ldap dogu.
+type AdminPasswordRotator struct {
+	globalConfigRepo        globalConfigRepo
+	doguConfigRepo          doguConfigRepo
+	sensitiveDoguConfigRepo doguConfigUpdater
+	// doguClient annotates the Dogu CR of the ldap dogu after the rotation. Nil disables the annotation.
+	doguClient        doguPatcher
+	passwordGenerator passwordGenerator
+	recorder          *event.Recorder
+	now               func() time.Time
+}
+
+func NewAdminPasswordRotator(
+	globalConfigRepo globalConfigRepo,
+	doguConfigRepo doguConfigRepo,
+	sensitiveDoguConfigRepo doguConfigUpdater,
+	doguClient doguPatcher,
+	recorder *event.Recorder,
+) *AdminPasswordRotator {
+	return &AdminPasswordRotator{
+		globalConfigRepo:        globalConfigRepo,
+		doguConfigRepo:          doguConfigRepo,
+		sensitiveDoguConfigRepo: sensitiveDoguConfigRepo,
+		doguClient:              doguClient,
+		passwordGenerator:       &adminPasswordGenerator{},
+		recorder:                recorder,
+		now:                     time.Now,
+	}
+}
+
+// Rotate generates a new password that satisfies the password policy of the global config and replaces the password
+// of the LDAP admin with it. The password is returned as soon as it has been stored, even if recording the rotation
+// fails afterwards.
+func (r *AdminPasswordRotator) Rotate(ctx context.Context) (string, error) {
+	length, err := r.passwordLength(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	sensitiveConfig, err := r.sensitiveDoguConfigRepo.Get(ctx, ldapDogu)
+	if err != nil {
+		return "", fmt.Errorf("failed to read sensitive config of dogu %q: %w", ldapDogu, err)
+	}
+
+	password := r.passwordGenerator.generatePassword(length)
+	changed, err := sensitiveConfig.Set(ldapAdminPasswordKey, regLibConfig.Value(password))
+	if err != nil {
+		return "", fmt.Errorf("failed to set key %q: %w", ldapAdminPasswordKey, err)
+	}
+
+	// the update fails on a conflict, so that a concurrent change of the sensitive config is not overwritten
+	_, err = r.sensitiveDoguConfigRepo.Update(ctx, regLibConfig.DoguConfig{DoguName: ldapDogu, Config: changed})
+	if err != nil {
+		if !r.passwordStored(ctx, password) {
+			return "", fmt.Errorf("failed to update sensitive config of dogu %q: %w", ldapDogu, err)
+		}
+		slog.Warn("The update of the sensitive config failed, but the new password has been stored", "dogu", ldapDogu, "err", err)
+	}
+
+	rotatedAt := r.now().UTC().Format(time.RFC3339)
+	slog.Info("Rotated the password of the LDAP admin", "rotatedAt", rotatedAt)
+	r.recorder.Normal(ctx, event.SensitiveDoguConfig(ldapDogu), event.ReasonPasswordRotated, "Rotated password for key %q", ldapAdminPasswordKey)
+
+	if err = r.recordRotation(ctx, rotatedAt); err != nil {
+		return password, err
+	}
+	if err = r.notifyDogu(ctx, rotatedAt); err != nil {
+		return password, err
+	}
+
+	return password, nil
+}
+
+// passwordStored reports whether the password has been stored although the update failed. This happens if the
+// response of the update times out after it has been applied, e.g. its retry then fails with a conflict.
+// The password is random, so it is only stored if the update has been applied.
+func (r *AdminPasswordRotator) passwordStored(ctx context.Context, password string) bool {
+	// the update may have failed because the context is done
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storedPasswordCheckTimeout)
+	defer cancel()
+
+	sensitiveConfig, err := r.sensitiveDoguConfigRepo.Get(ctx, ldapDogu)
+	if err != nil {
+		slog.Warn("Could not check whether the new password has been stored", "dogu", ldapDogu, "err", err)
+		return false
+	}
+
+	value, ok := sensitiveConfig.Get(ldapAdminPasswordKey)
+	return ok && value.String() == password
+}
+
+// passwordLength returns the length of the generated password, which is at least the minimum length of the password
+// policy.
+func (r *AdminPasswordRotator) passwordLength(ctx context.Context) (int, error) {
+	globalConfig, err := r.globalConfigRepo.Get(ctx)
+	if cesLibErr.IsNotFoundError(err) {
+		return passwordLength, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read global config: %w", err)
+	}
+
+	value, ok := globalConfig.Get(passwordPolicyMinLengthKey)
+	if !ok {
+		return passwordLength, nil
+	}
+	minLength, err := strconv.Atoi(value.String())
+	if err != nil {
+		slog.Warn("Ignoring invalid password policy", "key", passwordPolicyMinLengthKey, "err", err)
+		return passwordLength, nil
+	}
+
+	return max(passwordLength, minLength), nil
+}
+
+// recordRotation writes the time of the rotation to the config of the ldap dogu. An existing time is replaced.
+func (r *AdminPasswordRotator) recordRotation(ctx context.Context, rotatedAt string) error {
+	doguConfig, err := r.doguConfigRepo.Get(ctx, ldapDogu)
+	if cesLibErr.IsNotFoundError(err) {
+		doguConfig, err = r.doguConfigRepo.Create(ctx, regLibConfig.CreateDoguConfig(ldapDogu, make(regLibConfig.Entries)))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read config of dogu %q: %w", ldapDogu, err)
+	}
+
+	changed, err := doguConfig.Set(AdminPasswordRotatedAtKey, regLibConfig.Value(rotatedAt))
+	if err != nil {
+		return fmt.Errorf("failed to set key %q: %w", AdminPasswordRotatedAtKey, err)
+	}
+	if _, err = r.doguConfigRepo.SaveOrMerge(ctx, regLibConfig.DoguConfig{DoguName: ldapDogu, Config: changed}); err != nil {
+		return fmt.Errorf("failed to record the rotation in the config of dogu %q: %w", ldapDogu, err)
+	}
+
+	return nil
+}
+
+func (r *AdminPasswordRotator) notifyDogu(ctx context.Context, rotatedAt string) error {
+	if r.doguClient == nil {
+		return nil
+	}
+
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, AdminPasswordRotatedAtAnnotation, rotatedAt)
+	if _, err := r.doguClient.Patch(ctx, ldapDogu, types.MergePatchType, []byte(patch), metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to annotate dogu %q: %w", ldapDogu, err)
+	}
+
+	slog.Info("Annotated the Dogu CR with the rotation", "dogu", ldapDogu, "annotation", AdminPasswordRotatedAtAnnotation)
+	return nil
+}
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+type rotatorMocks struct {
+	globalConfigRepo        *mockGlobalConfigRepo
+	doguConfigRepo          *mockDoguConfigRepo
+	sensitiveDoguConfigRepo *mockDoguConfigUpdater
+	doguClient              *mockDoguPatcher
+	passwordGenerator       *mockPasswordGenerator
+}
+
+func newTestRotator(t *testing.T) (*AdminPasswordRotator, rotatorMocks) {
+	m := rotatorMocks{
+		globalConfigRepo:        newMockGlobalConfigRepo(t),
+		doguConfigRepo:          newMockDoguConfigRepo(t),
+		sensitiveDoguConfigRepo: newMockDoguConfigUpdater(t),
+		doguClient:              newMockDoguPatcher(t),
+		passwordGenerator:       newMockPasswordGenerator(t),
+	}
+	recorder, _ := newTestRecorder()
+	rotator := NewAdminPasswordRotator(m.globalConfigRepo, m.doguConfigRepo, m.sensitiveDoguConfigRepo, m.doguClient, recorder)
+	rotator.passwordGenerator = m.passwordGenerator
+	rotator.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+	return rotator, m
+}
+
+func TestNewAdminPasswordRotator(t *testing.T) {
+	rotator := NewAdminPasswordRotator(newMockGlobalConfigRepo(t), newMockDoguConfigRepo(t), newMockDoguConfigUpdater(t), nil, nil)
+
+	assert.Nil(t, rotator.doguClient)
+	assert.IsType(t, &adminPasswordGenerator{}, rotator.passwordGenerator)
+	assert.NotNil(t, rotator.now)
+}
+
+func TestAdminPasswordRotator_Rotate(t *testing.T) {
+	testCtx := context.Background()
+	globalConfig := regLibConfig.CreateGlobalConfig(regLibConfig.Entries{passwordPolicyMinLengthKey: "32"})
+	sensitiveConfig := regLibConfig.CreateDoguConfig(ldapDogu, regLibConfig.Entries{"admin_password": "old", "other": "value"})
+	ldapConfig := regLibConfig.CreateDoguConfig(ldapDogu, regLibConfig.Entries{"admin_username": "admin"})
+
+	t.Run("should replace password, record rotation and annotate dogu", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(sensitiveConfig, nil)
+		m.passwordGenerator.EXPECT().generatePassword(32).Return("newPassword")
+		m.sensitiveDoguConfigRepo.EXPECT().Update(testCtx, mock.Anything).RunAndReturn(func(_ context.Context, cfg regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error) {
+			assert.Equal(t, regLibConfig.Entries{"admin_password": "newPassword", "other": "value"}, cfg.GetAll())
+			return cfg, nil
+		})
+		m.doguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(ldapConfig, nil)
+		m.doguConfigRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).RunAndReturn(func(_ context.Context, cfg regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error) {
+			assert.Equal(t, regLibConfig.Entries{"admin_username": "admin", AdminPasswordRotatedAtKey: "2026-01-02T03:04:05Z"}, cfg.GetAll())
+			return cfg, nil
+		})
+		m.doguClient.EXPECT().Patch(testCtx, ldapDogu, types.MergePatchType,
+			[]byte(`{"metadata":{"annotations":{"k8s.cloudogu.com/admin-password-rotated-at":"2026-01-02T03:04:05Z"}}}`), metav1.PatchOptions{}).
+			Return(nil, nil)
+
+		password, err := rotator.Rotate(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "newPassword", password)
+	})
+
+	t.Run("should use default length without global config and create ldap config", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		rotator.doguClient = nil
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(regLibConfig.GlobalConfig{}, cesLibErr.NewNotFoundError(assert.AnError))
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(sensitiveConfig, nil)
+		m.passwordGenerator.EXPECT().generatePassword(passwordLength).Return("newPassword")
+		m.sensitiveDoguConfigRepo.EXPECT().Update(testCtx, mock.Anything).Return(sensitiveConfig, nil)
+		emptyLdapConfig := regLibConfig.CreateDoguConfig(ldapDogu, make(regLibConfig.Entries))
+		m.doguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(regLibConfig.DoguConfig{}, cesLibErr.NewNotFoundError(assert.AnError))
+		m.doguConfigRepo.EXPECT().Create(testCtx, emptyLdapConfig).Return(emptyLdapConfig, nil)
+		m.doguConfigRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(emptyLdapConfig, nil)
+
+		password, err := rotator.Rotate(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "newPassword", password)
+	})
+
+	t.Run("should fail on error reading sensitive config", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(regLibConfig.DoguConfig{}, assert.AnError)
+
+		_, err := rotator.Rotate(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, `failed to read sensitive config of dogu "ldap"`)
+	})
+
+	t.Run("should fail on conflicting update", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(regLibConfig.CreateGlobalConfig(regLibConfig.Entries{passwordPolicyMinLengthKey: "invalid"}), nil)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(sensitiveConfig, nil)
+		m.passwordGenerator.EXPECT().generatePassword(passwordLength).Return("newPassword")
+		m.sensitiveDoguConfigRepo.EXPECT().Update(testCtx, mock.Anything).Return(regLibConfig.DoguConfig{}, cesLibErr.NewConflictError(assert.AnError))
+		concurrentConfig := regLibConfig.CreateDoguConfig(ldapDogu, regLibConfig.Entries{"admin_password": "concurrent"})
+		m.sensitiveDoguConfigRepo.EXPECT().Get(mock.Anything, cesLibDogu.SimpleName(ldapDogu)).Return(concurrentConfig, nil)
+
+		password, err := rotator.Rotate(testCtx)
+
+		require.Error(t, err)
+		assert.Empty(t, password)
+		assert.True(t, cesLibErr.IsConflictError(err))
+	})
+
+	t.Run("should return password if the update timed out after it has been stored", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		rotator.doguClient = nil
+		storedConfig := regLibConfig.CreateDoguConfig(ldapDogu, regLibConfig.Entries{"admin_password": "newPassword", "other": "value"})
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(sensitiveConfig, nil).Once()
+		m.passwordGenerator.EXPECT().generatePassword(32).Return("newPassword")
+		m.sensitiveDoguConfigRepo.EXPECT().Update(testCtx, mock.Anything).Return(regLibConfig.DoguConfig{}, context.DeadlineExceeded)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(mock.Anything, cesLibDogu.SimpleName(ldapDogu)).Return(storedConfig, nil).Once()
+		m.doguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(ldapConfig, nil)
+		m.doguConfigRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(ldapConfig, nil)
+
+		password, err := rotator.Rotate(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "newPassword", password)
+	})
+
+	t.Run("should fail if the update timed out and the password cannot be read", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(sensitiveConfig, nil).Once()
+		m.passwordGenerator.EXPECT().generatePassword(32).Return("newPassword")
+		m.sensitiveDoguConfigRepo.EXPECT().Update(testCtx, mock.Anything).Return(regLibConfig.DoguConfig{}, context.DeadlineExceeded)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(mock.Anything, cesLibDogu.SimpleName(ldapDogu)).Return(regLibConfig.DoguConfig{}, assert.AnError).Once()
+
+		password, err := rotator.Rotate(testCtx)
+
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Empty(t, password)
+	})
+
+	t.Run("should return password if recording the rotation fails", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(sensitiveConfig, nil)
+		m.passwordGenerator.EXPECT().generatePassword(32).Return("newPassword")
+		m.sensitiveDoguConfigRepo.EXPECT().Update(testCtx, mock.Anything).Return(sensitiveConfig, nil)
+		m.doguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(ldapConfig, nil)
+		m.doguConfigRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(regLibConfig.DoguConfig{}, assert.AnError)
+
+		password, err := rotator.Rotate(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, "newPassword", password)
+	})
+
+	t.Run("should return password if annotating the dogu fails", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(globalConfig, nil)
+		m.sensitiveDoguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(sensitiveConfig, nil)
+		m.passwordGenerator.EXPECT().generatePassword(32).Return("newPassword")
+		m.sensitiveDoguConfigRepo.EXPECT().Update(testCtx, mock.Anything).Return(sensitiveConfig, nil)
+		m.doguConfigRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName(ldapDogu)).Return(ldapConfig, nil)
+		m.doguConfigRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(ldapConfig, nil)
+		m.doguClient.EXPECT().Patch(testCtx, ldapDogu, types.MergePatchType, mock.Anything, metav1.PatchOptions{}).Return(nil, assert.AnError)
+
+		password, err := rotator.Rotate(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, `failed to annotate dogu "ldap"`)
+		assert.Equal(t, "newPassword", password)
+	})
+}
+
+func TestAdminPasswordRotator_passwordLength(t *testing.T) {
+	testCtx := context.Background()
+
+	tests := []struct {
+		name     string
+		entries  regLibConfig.Entries
+		expected int
+	}{
+		{name: "longer policy", entries: regLibConfig.Entries{passwordPolicyMinLengthKey: "32"}, expected: 32},
+		{name: "shorter policy", entries: regLibConfig.Entries{passwordPolicyMinLengthKey: "8"}, expected: passwordLength},
+		{name: "invalid policy", entries: regLibConfig.Entries{passwordPolicyMinLengthKey: "long"}, expected: passwordLength},
+		{name: "no policy", entries: regLibConfig.Entries{}, expected: passwordLength},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rotator, m := newTestRotator(t)
+			m.globalConfigRepo.EXPECT().Get(testCtx).Return(regLibConfig.CreateGlobalConfig(tt.entries), nil)
+
+			length, err := rotator.passwordLength(testCtx)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, length)
+		})
+	}
+
+	t.Run("should fail on error reading global config", func(t *testing.T) {
+		rotator, m := newTestRotator(t)
+		m.globalConfigRepo.EXPECT().Get(testCtx).Return(regLibConfig.GlobalConfig{}, assert.AnError)
+
+		_, err := rotator.passwordLength(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
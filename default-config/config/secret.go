@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultSecretUsernameKey = "username"
+	defaultSecretPasswordKey = "password"
+)
+
+// SecretRef references a Secret in the namespace of the job that contains a username and a password.
+type SecretRef struct {
+	Name        string `json:"name"`
+	UsernameKey string `json:"usernameKey"`
+	PasswordKey string `json:"passwordKey"`
+}
+
+func (r *SecretRef) setDefaults() {
+	if r.UsernameKey == "" {
+		r.UsernameKey = defaultSecretUsernameKey
+	}
+	if r.PasswordKey == "" {
+		r.PasswordKey = defaultSecretPasswordKey
+	}
+}
+
+// readCredentials returns the username and the password of the Secret. errInvalid is wrapped if a key is missing.
+func (r SecretRef) readCredentials(ctx context.Context, secretClient secretClient, errInvalid error) (username, password string, err error) {
+	secret, err := secretClient.Get(ctx, r.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get secret %s: %w", r.Name, err)
+	}
+
+	values := make([]string, 0, 2)
+	for _, key := range []string{r.UsernameKey, r.PasswordKey} {
+		value, ok := secret.Data[key]
+		if !ok || len(value) == 0 {
+			return "", "", fmt.Errorf("%w: secret %s has no key %s", errInvalid, r.Name, key)
+		}
+		values = append(values, string(value))
+	}
+
+	return values[0], values[1], nil
+}
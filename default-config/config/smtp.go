@@ -0,0 +1,207 @@
+package config
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"slices"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	postfixDogu               = "postfix"
+	postfixRelayHostKey       = "relayhost"
+	postfixTLSLevelKey        = "smtp_tls_security_level"
+	postfixSenderCanonicalKey = "sender_canonical"
+	postfixSaslUsernameKey    = "sasl_username"
+	postfixSaslPasswordKey    = "sasl_password"
+	mailAddressKey            = "mail_address"
+	defaultSMTPPort           = 587
+	smtpCheckTimeout          = 10 * time.Second
+	smtpCheckHelloName        = "ecosystem-core-default-config"
+)
+
+// TLS modes of the relay, which are the security levels of postfix.
+const (
+	smtpTLSNone    = "none"
+	smtpTLSMay     = "may"
+	smtpTLSEncrypt = "encrypt"
+	smtpTLSVerify  = "verify"
+)
+
+var smtpTLSModes = []string{smtpTLSNone, smtpTLSMay, smtpTLSEncrypt, smtpTLSVerify}
+
+// ErrSMTPRelayConfig is returned if the configuration of the SMTP relay is invalid.
+var ErrSMTPRelayConfig = errors.New("invalid SMTP relay configuration")
+
+// ErrSMTPRelayUnreachable is returned if the connectivity check of the SMTP relay fails.
+var ErrSMTPRelayUnreachable = errors.New("SMTP relay is unreachable")
+
+// SMTPRelay configures postfix to send the mails of the ecosystem via a relay.
+type SMTPRelay struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// TLS is the security level of postfix: "none", "may", "encrypt" or "verify".
+	TLS string `json:"tls"`
+	// SenderAddress replaces the sender of all mails if set, e.g. if the relay only accepts its own addresses.
+	SenderAddress string `json:"senderAddress"`
+	// MailAddress is the mail_address of the global config. It defaults to SenderAddress.
+	MailAddress string `json:"mailAddress"`
+	// CredentialsSecret contains the SASL credentials. The relay is used without authentication if its name is empty.
+	CredentialsSecret SecretRef `json:"credentialsSecret"`
+	// Check connects to the relay before the config is written.
+	Check bool `json:"check"`
+}
+
+// ParseSMTPRelay parses the configuration as YAML or JSON, sets the defaults and validates it.
+// An empty configuration returns nil, so that postfix is not configured.
+func ParseSMTPRelay(raw string) (*SMTPRelay, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	relay := &SMTPRelay{}
+	if err := yaml.UnmarshalStrict([]byte(raw), relay); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSMTPRelayConfig, err)
+	}
+	relay.setDefaults()
+
+	if err := relay.validate(); err != nil {
+		return nil, err
+	}
+
+	return relay, nil
+}
+
+func (r *SMTPRelay) setDefaults() {
+	if r.Port == 0 {
+		r.Port = defaultSMTPPort
+	}
+	if r.TLS == "" {
+		r.TLS = smtpTLSMay
+	}
+	if r.MailAddress == "" {
+		r.MailAddress = r.SenderAddress
+	}
+	r.CredentialsSecret.setDefaults()
+}
+
+func (r *SMTPRelay) validate() error {
+	var errs []error
+	if r.Host == "" {
+		errs = append(errs, errors.New("host must be set"))
+	}
+	if r.Port < 1 || r.Port > 65535 {
+		errs = append(errs, fmt.Errorf("port %d must be between 1 and 65535", r.Port))
+	}
+	if !slices.Contains(smtpTLSModes, r.TLS) {
+		errs = append(errs, fmt.Errorf("tls %q must be one of %v", r.TLS, smtpTLSModes))
+	}
+	if r.SenderAddress != "" && !isMailAddress(r.SenderAddress) {
+		errs = append(errs, fmt.Errorf("senderAddress %q is no valid mail address", r.SenderAddress))
+	}
+	if r.MailAddress != "" && !isMailAddress(r.MailAddress) {
+		errs = append(errs, fmt.Errorf("mailAddress %q is no valid mail address", r.MailAddress))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrSMTPRelayConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// isMailAddress accepts plain addresses like "ces@example.com", but no names like "CES <ces@example.com>".
+func isMailAddress(address string) bool {
+	parsed, err := mail.ParseAddress(address)
+	return err == nil && parsed.Address == address
+}
+
+func (r *SMTPRelay) address() string {
+	return net.JoinHostPort(r.Host, strconv.Itoa(r.Port))
+}
+
+// postfixConfig returns the keys of the postfix config. The host is set in brackets, so that postfix does not look up
+// its MX records.
+func (r *SMTPRelay) postfixConfig() map[string]string {
+	config := map[string]string{
+		postfixRelayHostKey: "[" + r.Host + "]:" + strconv.Itoa(r.Port),
+		postfixTLSLevelKey:  r.TLS,
+	}
+	if r.SenderAddress != "" {
+		config[postfixSenderCanonicalKey] = "/.+/ " + r.SenderAddress
+	}
+
+	return config
+}
+
+// postfixSensitiveConfig reads the SASL credentials from the Secret and returns them as keys of the sensitive postfix
+// config. It returns nil if no Secret is referenced.
+func (r *SMTPRelay) postfixSensitiveConfig(ctx context.Context, secretClient secretClient) (map[string]string, error) {
+	if r.CredentialsSecret.Name == "" {
+		return nil, nil
+	}
+
+	username, password, err := r.CredentialsSecret.readCredentials(ctx, secretClient, ErrSMTPRelayConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SASL credentials: %w", err)
+	}
+
+	return map[string]string{
+		postfixSaslUsernameKey: username,
+		postfixSaslPasswordKey: password,
+	}, nil
+}
+
+// checkConnectivity connects to the relay and greets it. If the TLS mode requires encryption, the relay must support
+// STARTTLS; its certificate is only verified with the mode "verify". The credentials are not verified.
+func (r *SMTPRelay) checkConnectivity(ctx context.Context) error {
+	slog.Info("Checking the connectivity of the SMTP relay...", "address", r.address())
+
+	ctx, cancel := context.WithTimeout(ctx, smtpCheckTimeout)
+	defer cancel()
+
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", r.address())
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrSMTPRelayUnreachable, err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, r.Host)
+	if err != nil {
+		return fmt.Errorf("%w: no greeting from %s: %w", ErrSMTPRelayUnreachable, r.address(), err)
+	}
+	defer client.Close()
+
+	if err = client.Hello(smtpCheckHelloName); err != nil {
+		return fmt.Errorf("%w: %s rejected the greeting: %w", ErrSMTPRelayUnreachable, r.address(), err)
+	}
+
+	if r.TLS == smtpTLSEncrypt || r.TLS == smtpTLSVerify {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return fmt.Errorf("%w: %s does not support STARTTLS, which the tls mode %s requires", ErrSMTPRelayUnreachable, r.address(), r.TLS)
+		}
+		// the certificate is only verified with "verify", like postfix does
+		if err = client.StartTLS(&tls.Config{ServerName: r.Host, InsecureSkipVerify: r.TLS != smtpTLSVerify}); err != nil {
+			return fmt.Errorf("%w: STARTTLS with %s failed: %w", ErrSMTPRelayUnreachable, r.address(), err)
+		}
+	}
+
+	if err = client.Quit(); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrSMTPRelayUnreachable, r.address(), err)
+	}
+
+	slog.Info("...SMTP relay is reachable", "address", r.address())
+	return nil
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// startSMTPStandIn serves a minimal SMTP dialog: the greeting, EHLO with the extensions and QUIT.
+func startSMTPStandIn(t *testing.T, greeting string, extensions ...string) (string, int) {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, greeting, extensions)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+func serveSMTP(conn net.Conn, greeting string, extensions []string) {
+	defer conn.Close()
+
+	reply := func(lines ...string) {
+		_, _ = conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n"))
+	}
+
+	reply(greeting)
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		command := strings.ToUpper(strings.Fields(scanner.Text() + " ")[0])
+		switch command {
+		case "EHLO":
+			lines := []string{"250-stand-in"}
+			for _, extension := range extensions {
+				lines = append(lines, "250-"+extension)
+			}
+			reply(append(lines, "250 OK")...)
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 not implemented")
+		}
+	}
+}
+
+func TestParseSMTPRelay(t *testing.T) {
+	t.Run("should return nil without config", func(t *testing.T) {
+		relay, err := ParseSMTPRelay("")
+
+		require.NoError(t, err)
+		assert.Nil(t, relay)
+	})
+
+	t.Run("should set the defaults", func(t *testing.T) {
+		relay, err := ParseSMTPRelay(`{"host": "mail.example.com", "senderAddress": "ces@example.com"}`)
+
+		require.NoError(t, err)
+		assert.Equal(t, &SMTPRelay{
+			Host:              "mail.example.com",
+			Port:              587,
+			TLS:               "may",
+			SenderAddress:     "ces@example.com",
+			MailAddress:       "ces@example.com",
+			CredentialsSecret: SecretRef{UsernameKey: "username", PasswordKey: "password"},
+		}, relay)
+	})
+
+	t.Run("should fail for invalid config", func(t *testing.T) {
+		_, err := ParseSMTPRelay(`{"port": 70000, "tls": "ssl", "senderAddress": "CES <ces@example.com>", "mailAddress": "ces"}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSMTPRelayConfig)
+		assert.ErrorContains(t, err, "host must be set")
+		assert.ErrorContains(t, err, "port 70000 must be between 1 and 65535")
+		assert.ErrorContains(t, err, `tls "ssl" must be one of`)
+		assert.ErrorContains(t, err, `senderAddress "CES <ces@example.com>" is no valid mail address`)
+		assert.ErrorContains(t, err, `mailAddress "ces" is no valid mail address`)
+	})
+
+	t.Run("should fail for unknown fields", func(t *testing.T) {
+		_, err := ParseSMTPRelay(`{"relayhost": "mail.example.com"}`)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSMTPRelayConfig)
+	})
+}
+
+func TestSMTPRelay_postfixConfig(t *testing.T) {
+	relay := &SMTPRelay{Host: "mail.example.com", Port: 25, TLS: "encrypt"}
+	assert.Equal(t, map[string]string{"relayhost": "[mail.example.com]:25", "smtp_tls_security_level": "encrypt"}, relay.postfixConfig())
+
+	relay.SenderAddress = "ces@example.com"
+	assert.Equal(t, "/.+/ ces@example.com", relay.postfixConfig()["sender_canonical"])
+}
+
+func TestSMTPRelay_postfixSensitiveConfig(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should return nil without secret", func(t *testing.T) {
+		config, err := (&SMTPRelay{}).postfixSensitiveConfig(testCtx, newMockSecretClient(t))
+
+		require.NoError(t, err)
+		assert.Nil(t, config)
+	})
+
+	t.Run("should read the sasl credentials", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "smtp-relay", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"username": []byte("ces"), "password": []byte("secret")},
+		}, nil)
+		relay := &SMTPRelay{CredentialsSecret: SecretRef{Name: "smtp-relay", UsernameKey: "username", PasswordKey: "password"}}
+
+		config, err := relay.postfixSensitiveConfig(testCtx, mockSecretClient)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"sasl_username": "ces", "sasl_password": "secret"}, config)
+	})
+
+	t.Run("should fail for missing key", func(t *testing.T) {
+		mockSecretClient := newMockSecretClient(t)
+		mockSecretClient.EXPECT().Get(testCtx, "smtp-relay", metav1.GetOptions{}).Return(&corev1.Secret{
+			Data: map[string][]byte{"username": []byte("ces")},
+		}, nil)
+		relay := &SMTPRelay{CredentialsSecret: SecretRef{Name: "smtp-relay", UsernameKey: "username", PasswordKey: "password"}}
+
+		_, err := relay.postfixSensitiveConfig(testCtx, mockSecretClient)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSMTPRelayConfig)
+		assert.ErrorContains(t, err, "failed to read SASL credentials: invalid SMTP relay configuration: secret smtp-relay has no key password")
+	})
+}
+
+func TestSMTPRelay_checkConnectivity(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should succeed if the relay greets", func(t *testing.T) {
+		host, port := startSMTPStandIn(t, "220 stand-in ESMTP")
+
+		err := (&SMTPRelay{Host: host, Port: port, TLS: "may"}).checkConnectivity(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should fail if the relay is not reachable", func(t *testing.T) {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		port := listener.Addr().(*net.TCPAddr).Port
+		require.NoError(t, listener.Close())
+
+		err = (&SMTPRelay{Host: "127.0.0.1", Port: port, TLS: "none"}).checkConnectivity(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSMTPRelayUnreachable)
+	})
+
+	t.Run("should fail if the relay rejects the connection", func(t *testing.T) {
+		host, port := startSMTPStandIn(t, "554 no service")
+
+		err := (&SMTPRelay{Host: host, Port: port, TLS: "none"}).checkConnectivity(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSMTPRelayUnreachable)
+		assert.ErrorContains(t, err, "no greeting from "+net.JoinHostPort(host, strconv.Itoa(port)))
+	})
+
+	t.Run("should fail if encryption is required, but not supported", func(t *testing.T) {
+		host, port := startSMTPStandIn(t, "220 stand-in ESMTP", "8BITMIME")
+
+		err := (&SMTPRelay{Host: host, Port: port, TLS: "encrypt"}).checkConnectivity(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrSMTPRelayUnreachable)
+		assert.ErrorContains(t, err, "does not support STARTTLS, which the tls mode encrypt requires")
+	})
+}
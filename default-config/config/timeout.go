@@ -0,0 +1,33 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Timeouts limits the duration of the single phases of applying the default config.
+// A zero duration disables the limit of the respective phase.
+type Timeouts struct {
+	GlobalConfig time.Duration
+	DoguConfig   time.Duration
+	Certificate  time.Duration
+}
+
+// withTimeout calls fn with a context limited by timeout. If fn fails because the context is done,
+// the context error is added to the returned error, as the registry errors do not preserve it.
+func withTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	err := fn(ctx)
+	if err != nil && ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+
+	return err
+}
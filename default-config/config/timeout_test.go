@@ -0,0 +1,52 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_withTimeout(t *testing.T) {
+	t.Run("should not limit context without timeout", func(t *testing.T) {
+		err := withTimeout(context.Background(), 0, func(ctx context.Context) error {
+			_, ok := ctx.Deadline()
+			assert.False(t, ok)
+			return nil
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should return error unchanged if context is not done", func(t *testing.T) {
+		err := withTimeout(context.Background(), time.Minute, func(ctx context.Context) error {
+			return assert.AnError
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("should add context error", func(t *testing.T) {
+		err := withTimeout(context.Background(), time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return assert.AnError
+		})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("should not add context error twice", func(t *testing.T) {
+		err := withTimeout(context.Background(), time.Millisecond, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+
+		require.Error(t, err)
+		assert.Equal(t, context.DeadlineExceeded, err)
+	})
+}
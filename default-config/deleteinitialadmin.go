@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cloudogu/ecosystem-core/default-config/config"
+	"k8s.io/client-go/kubernetes"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// deleteInitialAdminOptions configures the delete-initial-admin command, which deletes the Secret with the initial
+// admin credentials once the admin has confirmed the first login.
+type deleteInitialAdminOptions struct {
+	envFile    string
+	secretName string
+}
+
+func parseDeleteInitialAdminOptions(args []string) (deleteInitialAdminOptions, error) {
+	var opts deleteInitialAdminOptions
+	flags := flag.NewFlagSet("delete-initial-admin", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.envFile, "env-file", "", "file with the variables NAMESPACE and KUBE_CONTEXT_NAME")
+	flags.StringVar(&opts.secretName, "secret-name", config.DefaultInitialAdminSecretName, "name of the Secret with the initial admin credentials")
+
+	if err := flags.Parse(args); err != nil {
+		return deleteInitialAdminOptions{}, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+	if opts.secretName == "" {
+		return deleteInitialAdminOptions{}, fmt.Errorf("%w: --secret-name must not be empty", errInvalidJobConfig)
+	}
+
+	return opts, nil
+}
+
+func deleteInitialAdminCommand(signalCtx context.Context, stopSignals context.CancelFunc) int {
+	err := runDeleteInitialAdmin(signalCtx, os.Args[2:], os.Stdout)
+	stopSignals()
+
+	if err != nil {
+		class := classifyError(signalCtx, err)
+		code := exitCodes[class]
+		slog.Error("failed to delete initial admin credentials", "err", err, "errorClass", class, "exitCode", code)
+		return code
+	}
+
+	return 0
+}
+
+func runDeleteInitialAdmin(ctx context.Context, args []string, stdout io.Writer) error {
+	opts, err := parseDeleteInitialAdminOptions(args)
+	if err != nil {
+		return err
+	}
+
+	lookup, err := readRegistryConfigsLookup(opts.envFile)
+	if err != nil {
+		return err
+	}
+
+	logLevel, ok := lookup("LOG_LEVEL")
+	if !ok {
+		logLevel = "info"
+	}
+	logFormat, _ := lookup("LOG_FORMAT")
+	configureLogger(logLevel, logFormat, false)
+
+	namespace, _ := lookup("NAMESPACE")
+	if namespace == "" {
+		return fmt.Errorf("%w: NAMESPACE must be set", errInvalidJobConfig)
+	}
+
+	kubeContext, _ := lookup("KUBE_CONTEXT_NAME")
+	clusterConfig, err := ctrlconfig.GetConfigWithContext(kubeContext)
+	if err != nil {
+		return fmt.Errorf("failed to read kube config: %w", err)
+	}
+
+	clientSet, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return deleteInitialAdmin(ctx, clientSet.CoreV1().Secrets(namespace), opts.secretName, stdout)
+}
+
+func deleteInitialAdmin(ctx context.Context, secretClient corev1client.SecretInterface, secretName string, stdout io.Writer) error {
+	deleted, err := config.DeleteInitialAdminSecret(ctx, secretClient, secretName)
+	if err != nil {
+		return err
+	}
+
+	if !deleted {
+		_, err = fmt.Fprintf(stdout, "Secret %s does not exist. Nothing to delete.\n", secretName)
+		return err
+	}
+	_, err = fmt.Fprintf(stdout, "Deleted secret %s with the initial admin credentials.\n", secretName)
+	return err
+}
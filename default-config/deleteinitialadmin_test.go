@@ -0,0 +1,69 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cloudogu/ecosystem-core/default-config/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_parseDeleteInitialAdminOptions(t *testing.T) {
+	t.Run("should use default secret name", func(t *testing.T) {
+		opts, err := parseDeleteInitialAdminOptions(nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, deleteInitialAdminOptions{secretName: config.DefaultInitialAdminSecretName}, opts)
+	})
+
+	t.Run("should parse flags", func(t *testing.T) {
+		opts, err := parseDeleteInitialAdminOptions([]string{"--env-file", "../.env", "--secret-name", "admin"})
+
+		require.NoError(t, err)
+		assert.Equal(t, deleteInitialAdminOptions{envFile: "../.env", secretName: "admin"}, opts)
+	})
+
+	t.Run("should fail on empty secret name", func(t *testing.T) {
+		_, err := parseDeleteInitialAdminOptions([]string{"--secret-name", ""})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+	})
+
+	t.Run("should fail on unknown flag", func(t *testing.T) {
+		_, err := parseDeleteInitialAdminOptions([]string{"--unknown"})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+	})
+}
+
+func Test_runDeleteInitialAdmin(t *testing.T) {
+	t.Run("should fail without namespace", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "")
+
+		err := runDeleteInitialAdmin(context.Background(), nil, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.Equal(t, errorClassValidation, classifyError(context.Background(), err))
+		assert.ErrorContains(t, err, "NAMESPACE must be set")
+	})
+}
+
+func Test_deleteInitialAdmin(t *testing.T) {
+	testCtx := context.Background()
+	secretClient := fake.NewClientset(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "admin"}}).CoreV1().Secrets("")
+
+	var out bytes.Buffer
+	require.NoError(t, deleteInitialAdmin(testCtx, secretClient, "admin", &out))
+	assert.Equal(t, "Deleted secret admin with the initial admin credentials.\n", out.String())
+
+	out.Reset()
+	require.NoError(t, deleteInitialAdmin(testCtx, secretClient, "admin", &out))
+	assert.Equal(t, "Secret admin does not exist. Nothing to delete.\n", out.String())
+}
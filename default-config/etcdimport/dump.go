@@ -0,0 +1,62 @@
+// Package etcdimport imports the global and dogu config of a classic, etcd-based Cloudogu EcoSystem into the config
+// of the Kubernetes registry.
+package etcdimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrInvalidDump is returned if the export cannot be read.
+var ErrInvalidDump = errors.New("invalid etcd export")
+
+// node is a key or directory of a response of the etcd v2 keys API.
+type node struct {
+	Key   string  `json:"key"`
+	Value *string `json:"value"`
+	Dir   bool    `json:"dir"`
+	Nodes []node  `json:"nodes"`
+}
+
+// ReadDump reads an export of the etcd and returns the values by their etcd key, e.g. "/config/_global/fqdn".
+//
+// Two formats are supported: the response of the etcd v2 keys API for a recursive get of "/config", e.g. of
+// "curl http://localhost:4001/v2/keys/config?recursive=true", and a flat JSON object of keys and values.
+func ReadDump(r io.Reader) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidDump, err)
+	}
+
+	entries := map[string]string{}
+	if rawNode, ok := raw["node"]; ok {
+		var root node
+		if err := json.Unmarshal(rawNode, &root); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidDump, err)
+		}
+		flatten(root, entries)
+		return entries, nil
+	}
+
+	for key, rawValue := range raw {
+		var value string
+		if err := json.Unmarshal(rawValue, &value); err != nil {
+			return nil, fmt.Errorf("%w: value of key %q must be a string: %w", ErrInvalidDump, key, err)
+		}
+		entries["/"+strings.Trim(key, "/")] = value
+	}
+
+	return entries, nil
+}
+
+func flatten(n node, entries map[string]string) {
+	if !n.Dir && n.Value != nil {
+		entries[n.Key] = *n.Value
+	}
+	for _, child := range n.Nodes {
+		flatten(child, entries)
+	}
+}
@@ -0,0 +1,72 @@
+package etcdimport
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadDump(t *testing.T) {
+	t.Run("should flatten response of etcd keys api", func(t *testing.T) {
+		dump := `{
+  "action": "get",
+  "node": {"key": "/config", "dir": true, "nodes": [
+    {"key": "/config/_global", "dir": true, "nodes": [
+      {"key": "/config/_global/fqdn", "value": "ces.example.com"},
+      {"key": "/config/_global/password-policy", "dir": true, "nodes": [
+        {"key": "/config/_global/password-policy/min_length", "value": "14"}
+      ]}
+    ]},
+    {"key": "/config/ldap", "dir": true, "nodes": [
+      {"key": "/config/ldap/admin_username", "value": "admin"},
+      {"key": "/config/ldap/empty", "value": ""}
+    ]},
+    {"key": "/config/cas", "dir": true}
+  ]}
+}`
+
+		entries, err := ReadDump(strings.NewReader(dump))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"/config/_global/fqdn":                       "ces.example.com",
+			"/config/_global/password-policy/min_length": "14",
+			"/config/ldap/admin_username":                "admin",
+			"/config/ldap/empty":                         "",
+		}, entries)
+	})
+
+	t.Run("should read flat object", func(t *testing.T) {
+		entries, err := ReadDump(strings.NewReader(`{"/config/_global/fqdn": "ces.example.com", "config/ldap/admin_username/": "admin"}`))
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"/config/_global/fqdn":        "ces.example.com",
+			"/config/ldap/admin_username": "admin",
+		}, entries)
+	})
+
+	t.Run("should fail on invalid json", func(t *testing.T) {
+		_, err := ReadDump(strings.NewReader(`[`))
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidDump)
+	})
+
+	t.Run("should fail on non-string value", func(t *testing.T) {
+		_, err := ReadDump(strings.NewReader(`{"/config/_global/fqdn": 1}`))
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidDump)
+		assert.ErrorContains(t, err, `value of key "/config/_global/fqdn" must be a string`)
+	})
+
+	t.Run("should fail on invalid node", func(t *testing.T) {
+		_, err := ReadDump(strings.NewReader(`{"node": "config"}`))
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrInvalidDump)
+	})
+}
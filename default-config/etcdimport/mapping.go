@@ -0,0 +1,175 @@
+package etcdimport
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/cesapp-lib/keys"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+)
+
+const (
+	configDir      = "/config/"
+	globalDir      = "_global"
+	keyProviderKey = "key_provider"
+	publicKeyKey   = "public.pem"
+)
+
+// Results of the entries of the mapping report.
+const (
+	ResultImported = "imported"
+	ResultPlanned  = "planned"
+	ResultExists   = "exists"
+	ResultSkipped  = "skipped"
+)
+
+// ErrInvalidPrivateKey is returned if a private key cannot be read.
+var ErrInvalidPrivateKey = errors.New("invalid private key")
+
+// rsaCiphertextLengths are the lengths of values encrypted with the 2048 or 4096 bit keys of the dogus.
+var rsaCiphertextLengths = []int{256, 512}
+
+// Value is a value of the export mapped onto a key of a config repository.
+type Value struct {
+	// Source is the key of the value in the etcd.
+	Source string
+	// Repo is one of report.RepoGlobal, report.RepoDogu and report.RepoSensitiveDogu.
+	Repo  string
+	Dogu  string
+	Key   string
+	Value string
+}
+
+// Target describes the config key of the value for the mapping report.
+func (v Value) Target() string {
+	if v.Repo == report.RepoGlobal {
+		return fmt.Sprintf("%s %s", v.Repo, v.Key)
+	}
+	return fmt.Sprintf("%s %s %s", v.Repo, v.Dogu, v.Key)
+}
+
+// Entry is a line of the mapping report. It never contains a value.
+type Entry struct {
+	Source string
+	Target string
+	Result string
+	Reason string
+}
+
+// Mapping contains the values to import and the keys of the export that are skipped.
+type Mapping struct {
+	Values  []Value
+	Skipped []Entry
+}
+
+type decrypter interface {
+	Decrypt(input string) (string, error)
+}
+
+// Map maps the values of the export onto the config repositories. The values of "/config/_global" are mapped onto the
+// global config and the values of "/config/<dogu>" onto the config of the dogu. Encrypted values are decrypted with
+// the PEM encoded private key of their dogu and mapped onto the sensitive config of the dogu.
+func Map(entries map[string]string, privateKeys map[string][]byte) (Mapping, error) {
+	decrypters, err := readPrivateKeys(entries[configDir+globalDir+"/"+keyProviderKey], privateKeys)
+	if err != nil {
+		return Mapping{}, err
+	}
+
+	var mapping Mapping
+	for _, source := range slices.Sorted(maps.Keys(entries)) {
+		value, skipped := mapValue(source, entries[source], decrypters)
+		if skipped != nil {
+			mapping.Skipped = append(mapping.Skipped, *skipped)
+			continue
+		}
+		mapping.Values = append(mapping.Values, value)
+	}
+
+	return mapping, nil
+}
+
+func mapValue(source string, value string, decrypters map[string]decrypter) (Value, *Entry) {
+	skip := func(reason string) (Value, *Entry) {
+		return Value{}, &Entry{Source: source, Result: ResultSkipped, Reason: reason}
+	}
+
+	path, ok := strings.CutPrefix(source, configDir)
+	if !ok {
+		return skip("not part of /config")
+	}
+	dir, key, ok := strings.Cut(path, "/")
+	if !ok || key == "" {
+		return skip("no config key")
+	}
+
+	if dir == globalDir {
+		if key == keyProviderKey {
+			return skip("key provider of the classic ecosystem")
+		}
+		return Value{Source: source, Repo: report.RepoGlobal, Key: key, Value: value}, nil
+	}
+
+	if key == publicKeyKey {
+		return skip("key pair of the classic ecosystem")
+	}
+
+	decrypter, hasKey := decrypters[dir]
+	if hasKey {
+		if plain, err := decrypter.Decrypt(value); err == nil {
+			return Value{Source: source, Repo: report.RepoSensitiveDogu, Dogu: dir, Key: key, Value: plain}, nil
+		}
+	}
+	if isEncrypted(value) {
+		if hasKey {
+			return skip("decryption failed")
+		}
+		return skip("encrypted, no private key of the dogu")
+	}
+
+	return Value{Source: source, Repo: report.RepoDogu, Dogu: dir, Key: key, Value: value}, nil
+}
+
+// isEncrypted reports whether the value looks like a value encrypted with the public key of a dogu, either directly
+// with RSA or hybrid with AES-GCM.
+func isEncrypted(value string) bool {
+	var hybrid keys.HybridEncryptionValue
+	if json.Unmarshal([]byte(value), &hybrid) == nil && hybrid.Encryption.Type != "" {
+		return true
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	return err == nil && slices.Contains(rsaCiphertextLengths, len(decoded))
+}
+
+func readPrivateKeys(keyProvider string, privateKeys map[string][]byte) (map[string]decrypter, error) {
+	decrypters := map[string]decrypter{}
+	if len(privateKeys) == 0 {
+		return decrypters, nil
+	}
+
+	provider, err := keys.NewKeyProvider(keyProvider)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPrivateKey, err)
+	}
+
+	for dogu, privateKey := range privateKeys {
+		// the key provider panics on input that is not PEM encoded
+		if block, _ := pem.Decode(privateKey); block == nil {
+			return nil, fmt.Errorf("%w: key of dogu %q is not PEM encoded", ErrInvalidPrivateKey, dogu)
+		}
+
+		keyPair, err := provider.FromPrivateKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("%w: key of dogu %q: %w", ErrInvalidPrivateKey, dogu, err)
+		}
+		decrypters[dogu] = keyPair.Private()
+	}
+
+	return decrypters, nil
+}
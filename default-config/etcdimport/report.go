@@ -0,0 +1,41 @@
+package etcdimport
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/tabwriter"
+)
+
+// WriteReport writes the mapping report as table sorted by the etcd key, followed by the number of entries per
+// result.
+func WriteReport(w io.Writer, entries []Entry) error {
+	sorted := slices.SortedFunc(slices.Values(entries), func(a, b Entry) int {
+		return strings.Compare(a.Source, b.Source)
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "SOURCE\tTARGET\tRESULT\tREASON")
+	counts := map[string]int{}
+	for _, entry := range sorted {
+		target := entry.Target
+		if target == "" {
+			target = "-"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", entry.Source, target, entry.Result, entry.Reason)
+		counts[entry.Result]++
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	var summary []string
+	for _, result := range []string{ResultImported, ResultPlanned, ResultExists, ResultSkipped} {
+		if counts[result] > 0 {
+			summary = append(summary, fmt.Sprintf("%s=%d", result, counts[result]))
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%s\n", strings.Join(summary, " "))
+	return err
+}
@@ -0,0 +1,28 @@
+package etcdimport
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteReport(t *testing.T) {
+	var out bytes.Buffer
+
+	err := WriteReport(&out, []Entry{
+		{Source: "/config/ldap/admin_username", Target: "dogu ldap admin_username", Result: ResultImported},
+		{Source: "/config/_global/fqdn", Target: "global fqdn", Result: ResultExists, Reason: "already set, not overwritten"},
+		{Source: "/config/ldap/public.pem", Result: ResultSkipped, Reason: "key pair of the classic ecosystem"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, `SOURCE                       TARGET                    RESULT    REASON
+/config/_global/fqdn         global fqdn               exists    already set, not overwritten
+/config/ldap/admin_username  dogu ldap admin_username  imported  
+/config/ldap/public.pem      -                         skipped   key pair of the classic ecosystem
+
+imported=1 exists=1 skipped=1
+`, out.String())
+}
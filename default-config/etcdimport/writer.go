@@ -0,0 +1,185 @@
+package etcdimport
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+)
+
+type globalConfigRepo interface {
+	Get(ctx context.Context) (regLibConfig.GlobalConfig, error)
+	Create(ctx context.Context, globalConfig regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error)
+	SaveOrMerge(ctx context.Context, globalConfig regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error)
+}
+
+type doguConfigRepo interface {
+	Get(ctx context.Context, name cesLibDogu.SimpleName) (regLibConfig.DoguConfig, error)
+	Create(ctx context.Context, doguConfig regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error)
+	SaveOrMerge(ctx context.Context, doguConfig regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error)
+}
+
+// Writer writes the mapped values into the config repositories. Keys that are already set are not changed, so that
+// the import can be repeated and does not overwrite config that has been changed in the Kubernetes registry.
+type Writer struct {
+	globalConfigRepo        globalConfigRepo
+	doguConfigRepo          doguConfigRepo
+	sensitiveDoguConfigRepo doguConfigRepo
+}
+
+func NewWriter(globalConfigRepo globalConfigRepo, doguConfigRepo doguConfigRepo, sensitiveDoguConfigRepo doguConfigRepo) *Writer {
+	return &Writer{
+		globalConfigRepo:        globalConfigRepo,
+		doguConfigRepo:          doguConfigRepo,
+		sensitiveDoguConfigRepo: sensitiveDoguConfigRepo,
+	}
+}
+
+// Write imports the values and returns an entry of the mapping report for each of them. The entries of the values
+// written before an error are returned as well.
+func (w *Writer) Write(ctx context.Context, values []Value) ([]Entry, error) {
+	var entries []Entry
+
+	var globalValues []Value
+	doguValues := map[string][]Value{}
+	sensitiveValues := map[string][]Value{}
+	var dogus, sensitiveDogus []string
+	for _, value := range values {
+		switch value.Repo {
+		case report.RepoGlobal:
+			globalValues = append(globalValues, value)
+		case report.RepoDogu:
+			if _, ok := doguValues[value.Dogu]; !ok {
+				dogus = append(dogus, value.Dogu)
+			}
+			doguValues[value.Dogu] = append(doguValues[value.Dogu], value)
+		case report.RepoSensitiveDogu:
+			if _, ok := sensitiveValues[value.Dogu]; !ok {
+				sensitiveDogus = append(sensitiveDogus, value.Dogu)
+			}
+			sensitiveValues[value.Dogu] = append(sensitiveValues[value.Dogu], value)
+		}
+	}
+
+	if len(globalValues) > 0 {
+		written, err := w.writeGlobal(ctx, globalValues)
+		entries = append(entries, written...)
+		if err != nil {
+			return entries, err
+		}
+	}
+	for _, dogu := range dogus {
+		written, err := writeDogu(ctx, w.doguConfigRepo, dogu, doguValues[dogu])
+		entries = append(entries, written...)
+		if err != nil {
+			return entries, err
+		}
+	}
+	for _, dogu := range sensitiveDogus {
+		written, err := writeDogu(ctx, w.sensitiveDoguConfigRepo, dogu, sensitiveValues[dogu])
+		entries = append(entries, written...)
+		if err != nil {
+			return entries, err
+		}
+	}
+
+	return entries, nil
+}
+
+func (w *Writer) writeGlobal(ctx context.Context, values []Value) ([]Entry, error) {
+	globalConfig, err := w.globalConfigRepo.Get(ctx)
+	if cesLibErr.IsNotFoundError(err) {
+		globalConfig, err = w.globalConfigRepo.Create(ctx, regLibConfig.CreateGlobalConfig(make(regLibConfig.Entries)))
+		if cesLibErr.IsAlreadyExistsError(err) {
+			// another writer, e.g. the default-config job, created the global config in the meantime
+			globalConfig, err = w.globalConfigRepo.Get(ctx)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read global config: %w", err)
+	}
+
+	var entries []Entry
+	imported := 0
+	for _, value := range values {
+		if _, exists := globalConfig.Get(regLibConfig.Key(value.Key)); exists {
+			entries = append(entries, existingEntry(value))
+			continue
+		}
+
+		changed, err := globalConfig.Set(regLibConfig.Key(value.Key), regLibConfig.Value(value.Value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set global config key %q: %w", value.Key, err)
+		}
+		globalConfig = regLibConfig.GlobalConfig{Config: changed}
+		entries = append(entries, importedEntry(value, ResultImported))
+		imported++
+	}
+
+	if _, err = w.globalConfigRepo.SaveOrMerge(ctx, globalConfig); err != nil {
+		return nil, fmt.Errorf("failed to save global config: %w", err)
+	}
+	slog.Info("Imported global config", "keys", imported, "existingKeys", len(values)-imported)
+
+	return entries, nil
+}
+
+func writeDogu(ctx context.Context, repo doguConfigRepo, dogu string, values []Value) ([]Entry, error) {
+	doguName := cesLibDogu.SimpleName(dogu)
+	doguConfig, err := repo.Get(ctx, doguName)
+	if cesLibErr.IsNotFoundError(err) {
+		doguConfig, err = repo.Create(ctx, regLibConfig.CreateDoguConfig(doguName, make(regLibConfig.Entries)))
+		if cesLibErr.IsAlreadyExistsError(err) {
+			// another writer, e.g. the default-config job, created the dogu config in the meantime
+			doguConfig, err = repo.Get(ctx, doguName)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config of dogu %q: %w", dogu, err)
+	}
+
+	var entries []Entry
+	imported := 0
+	for _, value := range values {
+		if _, exists := doguConfig.Get(regLibConfig.Key(value.Key)); exists {
+			entries = append(entries, existingEntry(value))
+			continue
+		}
+
+		changed, err := doguConfig.Set(regLibConfig.Key(value.Key), regLibConfig.Value(value.Value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to set config key %q of dogu %q: %w", value.Key, dogu, err)
+		}
+		doguConfig = regLibConfig.DoguConfig{DoguName: doguName, Config: changed}
+		entries = append(entries, importedEntry(value, ResultImported))
+		imported++
+	}
+
+	if _, err = repo.SaveOrMerge(ctx, doguConfig); err != nil {
+		return nil, fmt.Errorf("failed to save config of dogu %q: %w", dogu, err)
+	}
+	slog.Info("Imported dogu config", "dogu", dogu, "keys", imported, "existingKeys", len(values)-imported)
+
+	return entries, nil
+}
+
+// Plan returns the entries of the mapping report for a dry run without reading or writing the config.
+func Plan(values []Value) []Entry {
+	entries := make([]Entry, 0, len(values))
+	for _, value := range values {
+		entries = append(entries, importedEntry(value, ResultPlanned))
+	}
+	return entries
+}
+
+func importedEntry(value Value, result string) Entry {
+	return Entry{Source: value.Source, Target: value.Target(), Result: result}
+}
+
+func existingEntry(value Value) Entry {
+	return Entry{Source: value.Source, Target: value.Target(), Result: ResultExists, Reason: "already set, not overwritten"}
+}
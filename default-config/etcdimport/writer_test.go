@@ -0,0 +1,120 @@
+package etcdimport
+
+import (
+	"context"
+	"testing"
+
+	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newTestWriter uses a fake client per repository, because the fake client ignores the field selectors the
+// repositories list the ConfigMaps with. The sensitive config is stored in a ConfigMap as well, because the fake
+// client does not convert the string data of Secrets.
+func newTestWriter() (*Writer, *repository.GlobalConfigRepository, *repository.DoguConfigRepository, *repository.DoguConfigRepository) {
+	globalRepo := repository.NewGlobalConfigRepository(fake.NewClientset().CoreV1().ConfigMaps("ecosystem"))
+	doguRepo := repository.NewDoguConfigRepository(fake.NewClientset().CoreV1().ConfigMaps("ecosystem"))
+	sensitiveRepo := repository.NewDoguConfigRepository(fake.NewClientset().CoreV1().ConfigMaps("ecosystem"))
+	return NewWriter(globalRepo, doguRepo, sensitiveRepo), globalRepo, doguRepo, sensitiveRepo
+}
+
+func TestWriter_Write(t *testing.T) {
+	testCtx := context.Background()
+	values := []Value{
+		{Source: "/config/_global/fqdn", Repo: "global", Key: "fqdn", Value: "ces.example.com"},
+		{Source: "/config/_global/domain", Repo: "global", Key: "domain", Value: "example.com"},
+		{Source: "/config/ldap/admin_username", Repo: "dogu", Dogu: "ldap", Key: "admin_username", Value: "admin"},
+		{Source: "/config/ldap/admin_password", Repo: "sensitive-dogu", Dogu: "ldap", Key: "admin_password", Value: "topSecret"},
+	}
+
+	t.Run("should import values and keep existing keys", func(t *testing.T) {
+		writer, globalRepo, doguRepo, sensitiveRepo := newTestWriter()
+		_, err := globalRepo.Create(testCtx, regLibConfig.CreateGlobalConfig(regLibConfig.Entries{"domain": "existing.com"}))
+		require.NoError(t, err)
+
+		entries, err := writer.Write(testCtx, values)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Entry{
+			{Source: "/config/_global/fqdn", Target: "global fqdn", Result: ResultImported},
+			{Source: "/config/_global/domain", Target: "global domain", Result: ResultExists, Reason: "already set, not overwritten"},
+			{Source: "/config/ldap/admin_username", Target: "dogu ldap admin_username", Result: ResultImported},
+			{Source: "/config/ldap/admin_password", Target: "sensitive-dogu ldap admin_password", Result: ResultImported},
+		}, entries)
+
+		globalConfig, err := globalRepo.Get(testCtx)
+		require.NoError(t, err)
+		assert.Equal(t, regLibConfig.Entries{"fqdn": "ces.example.com", "domain": "existing.com"}, globalConfig.GetAll())
+		doguConfig, err := doguRepo.Get(testCtx, cesLibDogu.SimpleName("ldap"))
+		require.NoError(t, err)
+		assert.Equal(t, regLibConfig.Entries{"admin_username": "admin"}, doguConfig.GetAll())
+		sensitiveConfig, err := sensitiveRepo.Get(testCtx, cesLibDogu.SimpleName("ldap"))
+		require.NoError(t, err)
+		assert.Equal(t, regLibConfig.Entries{"admin_password": "topSecret"}, sensitiveConfig.GetAll())
+	})
+
+	t.Run("should read the config again if it has been created concurrently", func(t *testing.T) {
+		existing := regLibConfig.CreateGlobalConfig(regLibConfig.Entries{"domain": "existing.com"})
+		globalRepo := newMockGlobalConfigRepo(t)
+		globalRepo.EXPECT().Get(testCtx).Return(regLibConfig.GlobalConfig{}, cesLibErr.NewNotFoundError(assert.AnError)).Once()
+		globalRepo.EXPECT().Create(testCtx, mock.Anything).Return(regLibConfig.GlobalConfig{}, cesLibErr.NewAlreadyExistsError(assert.AnError))
+		globalRepo.EXPECT().Get(testCtx).Return(existing, nil).Once()
+		globalRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).RunAndReturn(func(_ context.Context, globalConfig regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error) {
+			assert.Equal(t, regLibConfig.Entries{"fqdn": "ces.example.com", "domain": "existing.com"}, globalConfig.GetAll())
+			return globalConfig, nil
+		})
+
+		doguRepo := newMockDoguConfigRepo(t)
+		doguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(regLibConfig.DoguConfig{}, cesLibErr.NewNotFoundError(assert.AnError)).Once()
+		doguRepo.EXPECT().Create(testCtx, mock.Anything).Return(regLibConfig.DoguConfig{}, cesLibErr.NewAlreadyExistsError(assert.AnError))
+		doguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(regLibConfig.CreateDoguConfig("ldap", regLibConfig.Entries{}), nil).Once()
+		doguRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(regLibConfig.DoguConfig{}, nil)
+		_, _, _, sensitiveRepo := newTestWriter()
+		writer := NewWriter(globalRepo, doguRepo, sensitiveRepo)
+
+		entries, err := writer.Write(testCtx, values)
+
+		require.NoError(t, err)
+		assert.Len(t, entries, 4)
+		assert.Equal(t, ResultExists, entries[1].Result)
+	})
+
+	t.Run("should return entries written before an error", func(t *testing.T) {
+		_, globalRepo, _, _ := newTestWriter()
+		doguRepo := newMockDoguConfigRepo(t)
+		doguRepo.EXPECT().Get(testCtx, cesLibDogu.SimpleName("ldap")).Return(regLibConfig.DoguConfig{}, assert.AnError)
+		writer := NewWriter(globalRepo, doguRepo, newMockDoguConfigRepo(t))
+
+		entries, err := writer.Write(testCtx, values)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, `failed to read config of dogu "ldap"`)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("should fail on error saving global config", func(t *testing.T) {
+		globalRepo := newMockGlobalConfigRepo(t)
+		globalRepo.EXPECT().Get(testCtx).Return(regLibConfig.CreateGlobalConfig(regLibConfig.Entries{}), nil)
+		globalRepo.EXPECT().SaveOrMerge(testCtx, mock.Anything).Return(regLibConfig.GlobalConfig{}, assert.AnError)
+		writer := NewWriter(globalRepo, newMockDoguConfigRepo(t), newMockDoguConfigRepo(t))
+
+		entries, err := writer.Write(testCtx, values)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, entries)
+	})
+}
+
+func TestPlan(t *testing.T) {
+	entries := Plan([]Value{{Source: "/config/_global/fqdn", Repo: "global", Key: "fqdn", Value: "ces.example.com"}})
+
+	assert.Equal(t, []Entry{{Source: "/config/_global/fqdn", Target: "global fqdn", Result: ResultPlanned}}, entries)
+}
@@ -0,0 +1,99 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package event
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// mockEventClient is an autogenerated mock type for the eventClient type
+type mockEventClient struct {
+	mock.Mock
+}
+
+type mockEventClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockEventClient) EXPECT() *mockEventClient_Expecter {
+	return &mockEventClient_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, _a1, opts
+func (_m *mockEventClient) Create(ctx context.Context, _a1 *v1.Event, opts metav1.CreateOptions) (*v1.Event, error) {
+	ret := _m.Called(ctx, _a1, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *v1.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.Event, metav1.CreateOptions) (*v1.Event, error)); ok {
+		return rf(ctx, _a1, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.Event, metav1.CreateOptions) *v1.Event); ok {
+		r0 = rf(ctx, _a1, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.Event, metav1.CreateOptions) error); ok {
+		r1 = rf(ctx, _a1, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockEventClient_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockEventClient_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 *v1.Event
+//   - opts metav1.CreateOptions
+func (_e *mockEventClient_Expecter) Create(ctx interface{}, _a1 interface{}, opts interface{}) *mockEventClient_Create_Call {
+	return &mockEventClient_Create_Call{Call: _e.mock.On("Create", ctx, _a1, opts)}
+}
+
+func (_c *mockEventClient_Create_Call) Run(run func(ctx context.Context, _a1 *v1.Event, opts metav1.CreateOptions)) *mockEventClient_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1.Event), args[2].(metav1.CreateOptions))
+	})
+	return _c
+}
+
+func (_c *mockEventClient_Create_Call) Return(_a0 *v1.Event, _a1 error) *mockEventClient_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockEventClient_Create_Call) RunAndReturn(run func(context.Context, *v1.Event, metav1.CreateOptions) (*v1.Event, error)) *mockEventClient_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockEventClient creates a new instance of mockEventClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockEventClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockEventClient {
+	mock := &mockEventClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
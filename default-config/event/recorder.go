@@ -0,0 +1,125 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Component is the event source of the default-config job.
+const Component = "ecosystem-core-default-config"
+
+// Reasons of the events emitted by the default-config job.
+const (
+	ReasonDefaultsApplied         = "DefaultsApplied"
+	ReasonCertificateTypeDetected = "CertificateTypeDetected"
+	ReasonPasswordGenerated       = "PasswordGenerated"
+	ReasonFQDNResolved            = "FQDNResolved"
+	ReasonFQDNTimeout             = "FQDNTimeout"
+	ReasonInitialAdminStored      = "InitialAdminStored"
+	ReasonInitialAdminExpired     = "InitialAdminExpired"
+	ReasonPasswordRotated         = "PasswordRotated"
+)
+
+type eventClient interface {
+	Create(ctx context.Context, event *corev1.Event, opts metav1.CreateOptions) (*corev1.Event, error)
+}
+
+// Object references the object an event is attached to.
+type Object struct {
+	Kind string
+	Name string
+}
+
+// GlobalConfig references the ConfigMap of the global config.
+func GlobalConfig() Object {
+	return Object{Kind: "ConfigMap", Name: "global-config"}
+}
+
+// DoguConfig references the ConfigMap of the config of the given dogu.
+func DoguConfig(dogu string) Object {
+	return Object{Kind: "ConfigMap", Name: dogu + "-config"}
+}
+
+// SensitiveDoguConfig references the Secret of the sensitive config of the given dogu.
+func SensitiveDoguConfig(dogu string) Object {
+	return Object{Kind: "Secret", Name: dogu + "-config"}
+}
+
+// Secret references the Secret with the given name.
+func Secret(name string) Object {
+	return Object{Kind: "Secret", Name: name}
+}
+
+// Service references the Service with the given name.
+func Service(name string) Object {
+	return Object{Kind: "Service", Name: name}
+}
+
+// Recorder emits Kubernetes events for the actions of the default-config job.
+// Events are best effort: a failure to emit one is logged and does not fail the job.
+// Messages must never contain config values, because events are readable without access to the sensitive config.
+// All methods can be called on a nil Recorder.
+type Recorder struct {
+	client    eventClient
+	namespace string
+	instance  string
+	now       func() time.Time
+}
+
+// NewRecorder creates a recorder for events in the given namespace. instance identifies the reporting pod.
+func NewRecorder(client eventClient, namespace string, instance string) *Recorder {
+	return &Recorder{
+		client:    client,
+		namespace: namespace,
+		instance:  instance,
+		now:       time.Now,
+	}
+}
+
+// Normal emits an event of type Normal.
+func (r *Recorder) Normal(ctx context.Context, obj Object, reason string, messageFmt string, args ...any) {
+	r.emit(ctx, obj, corev1.EventTypeNormal, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+// Warning emits an event of type Warning.
+func (r *Recorder) Warning(ctx context.Context, obj Object, reason string, messageFmt string, args ...any) {
+	r.emit(ctx, obj, corev1.EventTypeWarning, reason, fmt.Sprintf(messageFmt, args...))
+}
+
+func (r *Recorder) emit(ctx context.Context, obj Object, eventType string, reason string, message string) {
+	if r == nil {
+		return
+	}
+
+	now := r.now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s.%x", obj.Name, now.UnixNano()),
+			Namespace: r.namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       obj.Kind,
+			Name:       obj.Name,
+			Namespace:  r.namespace,
+		},
+		Reason:              reason,
+		Message:             message,
+		Type:                eventType,
+		Source:              corev1.EventSource{Component: Component},
+		FirstTimestamp:      metav1.NewTime(now),
+		LastTimestamp:       metav1.NewTime(now),
+		Count:               1,
+		ReportingController: Component,
+		ReportingInstance:   r.instance,
+	}
+
+	if _, err := r.client.Create(ctx, event, metav1.CreateOptions{}); err != nil {
+		slog.Warn("failed to emit event", "reason", reason, "kind", obj.Kind, "name", obj.Name, "err", err)
+	}
+}
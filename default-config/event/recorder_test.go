@@ -0,0 +1,95 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "ecosystem"
+
+func listEvents(t *testing.T, clientSet *fake.Clientset) []corev1.Event {
+	t.Helper()
+	list, err := clientSet.CoreV1().Events(testNamespace).List(context.Background(), metav1.ListOptions{})
+	require.NoError(t, err)
+	return list.Items
+}
+
+func TestRecorder_Normal(t *testing.T) {
+	t.Run("should create event for object", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		recorder := NewRecorder(clientSet.CoreV1().Events(testNamespace), testNamespace, "default-config-abcde")
+		now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		recorder.now = func() time.Time { return now }
+
+		recorder.Normal(context.Background(), GlobalConfig(), ReasonDefaultsApplied, "Created %d default keys", 3)
+
+		events := listEvents(t, clientSet)
+		require.Len(t, events, 1)
+		event := events[0]
+		assert.Equal(t, corev1.EventTypeNormal, event.Type)
+		assert.Equal(t, ReasonDefaultsApplied, event.Reason)
+		assert.Equal(t, "Created 3 default keys", event.Message)
+		assert.Equal(t, corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "global-config", Namespace: testNamespace}, event.InvolvedObject)
+		assert.Equal(t, Component, event.Source.Component)
+		assert.Equal(t, Component, event.ReportingController)
+		assert.Equal(t, "default-config-abcde", event.ReportingInstance)
+		assert.Equal(t, int32(1), event.Count)
+		assert.True(t, event.FirstTimestamp.Time.Equal(now))
+		assert.True(t, event.LastTimestamp.Time.Equal(now))
+	})
+
+	t.Run("should not fail if event cannot be created", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		clientSet.PrependReactor("create", "events", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(corev1.Resource("events"), "", assert.AnError)
+		})
+		recorder := NewRecorder(clientSet.CoreV1().Events(testNamespace), testNamespace, "default-config-abcde")
+
+		assert.NotPanics(t, func() {
+			recorder.Normal(context.Background(), Service("ces-loadbalancer"), ReasonFQDNResolved, "Resolved fqdn")
+		})
+	})
+
+	t.Run("should ignore nil recorder", func(t *testing.T) {
+		var recorder *Recorder
+
+		assert.NotPanics(t, func() {
+			recorder.Normal(context.Background(), GlobalConfig(), ReasonDefaultsApplied, "Created default keys")
+		})
+	})
+}
+
+func TestRecorder_Warning(t *testing.T) {
+	t.Run("should create warning event", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		recorder := NewRecorder(clientSet.CoreV1().Events(testNamespace), testNamespace, "default-config-abcde")
+
+		recorder.Warning(context.Background(), Service("ces-loadbalancer"), ReasonFQDNTimeout, "Timed out after %s", time.Minute)
+
+		events := listEvents(t, clientSet)
+		require.Len(t, events, 1)
+		assert.Equal(t, corev1.EventTypeWarning, events[0].Type)
+		assert.Equal(t, ReasonFQDNTimeout, events[0].Reason)
+		assert.Equal(t, "Timed out after 1m0s", events[0].Message)
+		assert.Equal(t, "Service", events[0].InvolvedObject.Kind)
+		assert.Equal(t, "ces-loadbalancer", events[0].InvolvedObject.Name)
+	})
+}
+
+func TestObjects(t *testing.T) {
+	assert.Equal(t, Object{Kind: "ConfigMap", Name: "global-config"}, GlobalConfig())
+	assert.Equal(t, Object{Kind: "ConfigMap", Name: "ldap-config"}, DoguConfig("ldap"))
+	assert.Equal(t, Object{Kind: "Secret", Name: "ldap-config"}, SensitiveDoguConfig("ldap"))
+	assert.Equal(t, Object{Kind: "Secret", Name: "ecosystem-core-initial-admin"}, Secret("ecosystem-core-initial-admin"))
+	assert.Equal(t, Object{Kind: "Service", Name: "ces-loadbalancer"}, Service("ces-loadbalancer"))
+}
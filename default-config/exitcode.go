@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/config"
+	"github.com/cloudogu/ecosystem-core/default-config/lease"
+	"github.com/cloudogu/ecosystem-core/default-config/preflight"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// errorClass is the cause of a failed job as shown in the termination message.
+type errorClass string
+
+const (
+	errorClassUnknown     errorClass = "unknown"
+	errorClassTimeout     errorClass = "timeout"
+	errorClassValidation  errorClass = "validation"
+	errorClassAPI         errorClass = "api"
+	errorClassInterrupted errorClass = "interrupted"
+	errorClassLeaseLost   errorClass = "lease-lost"
+)
+
+// Exit codes of the default-config job. They allow to distinguish the cause of a failed job without reading its logs.
+const (
+	exitCodeFailure     = 1
+	exitCodeTimeout     = 2
+	exitCodeValidation  = 3
+	exitCodeAPIFailure  = 4
+	exitCodeInterrupted = 5
+	exitCodeLeaseLost   = 6
+)
+
+var exitCodes = map[errorClass]int{
+	errorClassUnknown:     exitCodeFailure,
+	errorClassTimeout:     exitCodeTimeout,
+	errorClassValidation:  exitCodeValidation,
+	errorClassAPI:         exitCodeAPIFailure,
+	errorClassInterrupted: exitCodeInterrupted,
+	errorClassLeaseLost:   exitCodeLeaseLost,
+}
+
+var errInvalidJobConfig = errors.New("invalid job configuration")
+
+// classifyError returns the cause of err. signalCtx is done if the job was interrupted by a signal.
+func classifyError(signalCtx context.Context, err error) errorClass {
+	switch {
+	case signalCtx.Err() != nil:
+		return errorClassInterrupted
+	case errors.Is(err, lease.ErrLost):
+		return errorClassLeaseLost
+	case errors.Is(err, context.DeadlineExceeded):
+		return errorClassTimeout
+	case errors.Is(err, errInvalidJobConfig), errors.Is(err, preflight.ErrFailed), errors.Is(err, config.ErrSelfSignedCertificate):
+		return errorClassValidation
+	case isAPIError(err):
+		return errorClassAPI
+	default:
+		return errorClassUnknown
+	}
+}
+
+func isAPIError(err error) bool {
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) {
+		return true
+	}
+
+	var registryErr cesLibErr.Error
+	return errors.As(err, &registryErr)
+}
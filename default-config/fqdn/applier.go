@@ -2,11 +2,16 @@ package fqdn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/cloudogu/ecosystem-core/default-config/tracing"
 	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+	"go.opentelemetry.io/otel/attribute"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -29,16 +34,24 @@ type serviceGetter interface {
 type Applier struct {
 	globalConfigRepo globalConfigRepo
 	serviceGetter    serviceGetter
+	summary          *report.Summary
+	recorder         *event.Recorder
 }
 
-func NewApplier(globalConfigRepo globalConfigRepo, serviceGetter serviceGetter) *Applier {
+func NewApplier(globalConfigRepo globalConfigRepo, serviceGetter serviceGetter, summary *report.Summary, recorder *event.Recorder) *Applier {
 	return &Applier{
 		globalConfigRepo: globalConfigRepo,
 		serviceGetter:    serviceGetter,
+		summary:          summary,
+		recorder:         recorder,
 	}
 }
 
-func (a *Applier) ApplyInitialFQDN(ctx context.Context, timeout time.Duration) error {
+func (a *Applier) ApplyInitialFQDN(ctx context.Context, timeout time.Duration) (err error) {
+	a.summary.EnterPhase(report.PhaseFQDN)
+	ctx, span := tracing.Start(ctx, string(report.PhaseFQDN))
+	defer func() { tracing.End(span, err) }()
+
 	globalConfig, err := a.globalConfigRepo.Get(ctx)
 	if err != nil {
 		return fmt.Errorf("error reading global config while checking for fqdn: %w", err)
@@ -47,17 +60,24 @@ func (a *Applier) ApplyInitialFQDN(ctx context.Context, timeout time.Duration) e
 	fqdn, exists := globalConfig.Get(fqdnKey)
 	if exists && fqdn != "" {
 		slog.Info("fqdn already set. Skipping...")
+		a.summary.AddKeys(report.RepoGlobal, report.KeyCounts{Skipped: 1})
 		return nil
 	}
 
 	slog.Info("fqdn not set. Retrieving fqdn from load balancer service...")
 
+	waitStarted := time.Now()
 	loadBalancerFqdn, err := a.getFQDNFromLoadBalancerService(ctx, timeout)
+	a.summary.SetFQDNWait(time.Since(waitStarted))
+	if errors.Is(err, context.DeadlineExceeded) {
+		a.recorder.Warning(ctx, event.Service(cesLoadBalancerServiceName), event.ReasonFQDNTimeout, "Timed out after %s waiting for an external address", timeout)
+	}
 	if err != nil {
 		return fmt.Errorf("error getting fqdn from load balancer service: %w", err)
 	}
 
 	slog.Info("fqdn retrieved from load balancer service", "fqdn", loadBalancerFqdn)
+	a.recorder.Normal(ctx, event.Service(cesLoadBalancerServiceName), event.ReasonFQDNResolved, "Resolved fqdn %q from the external address", loadBalancerFqdn)
 
 	newGlobalConfig, err := globalConfig.Set(fqdnKey, regLibConfig.Value(loadBalancerFqdn))
 	if err != nil {
@@ -70,13 +90,16 @@ func (a *Applier) ApplyInitialFQDN(ctx context.Context, timeout time.Duration) e
 	if err != nil {
 		return fmt.Errorf("failed to save global config while setting fqdn: %w", err)
 	}
-
+	a.summary.AddKeys(report.RepoGlobal, report.KeyCounts{Created: 1})
 	slog.Info("...Successfully applied fqdn from load balancer service to global config.")
 
 	return nil
 }
 
-func (a *Applier) getFQDNFromLoadBalancerService(ctx context.Context, timeout time.Duration) (string, error) {
+func (a *Applier) getFQDNFromLoadBalancerService(ctx context.Context, timeout time.Duration) (fqdn string, err error) {
+	ctx, span := tracing.Start(ctx, "wait-for-load-balancer", attribute.String("service", cesLoadBalancerServiceName))
+	defer func() { tracing.End(span, err) }()
+
 	ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -107,7 +130,7 @@ func (a *Applier) getFQDNFromLoadBalancerService(ctx context.Context, timeout ti
 
 		select {
 		case <-ctxWithTimeout.Done():
-			return "", fmt.Errorf("timed out after %s waiting for external address on service %q", timeout, cesLoadBalancerServiceName)
+			return "", fmt.Errorf("timed out after %s waiting for external address on service %q: %w", timeout, cesLoadBalancerServiceName, ctxWithTimeout.Err())
 		case <-ticker.C:
 			// retry
 		}
@@ -2,17 +2,41 @@ package fqdn
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
 	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
+func newTestRecorder() (*event.Recorder, *fake.Clientset) {
+	clientSet := fake.NewClientset()
+	return event.NewRecorder(clientSet.CoreV1().Events("ecosystem"), "ecosystem", "default-config-abcde"), clientSet
+}
+
+// recordedEvents returns the created events in the order of their creation.
+func recordedEvents(t *testing.T, clientSet *fake.Clientset) []string {
+	t.Helper()
+	var events []string
+	for _, action := range clientSet.Actions() {
+		if create, ok := action.(k8stesting.CreateAction); ok {
+			e, ok := create.GetObject().(*corev1.Event)
+			require.True(t, ok)
+			events = append(events, fmt.Sprintf("%s %s/%s: %s", e.Reason, e.InvolvedObject.Kind, e.InvolvedObject.Name, e.Message))
+		}
+	}
+	return events
+}
+
 func TestApplier_getFQDNFromLoadBalancerService(t *testing.T) {
 	t.Run("should get fqdn as IP from load balancer service", func(t *testing.T) {
 		sg := newMockServiceGetter(t)
@@ -174,6 +198,7 @@ func TestApplier_getFQDNFromLoadBalancerService(t *testing.T) {
 		require.Error(t, err)
 		assert.Equal(t, 3, tries, "should be called 2 times")
 		assert.ErrorContains(t, err, "timed out after 10ms waiting for external address on service \"ces-loadbalancer\"")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
 	})
 }
 
@@ -214,14 +239,23 @@ func TestApplier_ApplyInitialFQDN(t *testing.T) {
 		sg := newMockServiceGetter(t)
 		sg.EXPECT().Get(mock.Anything, cesLoadBalancerServiceName, metav1.GetOptions{}).Return(svc, nil)
 
+		summary := report.NewSummary()
+		recorder, clientSet := newTestRecorder()
 		a := &Applier{
 			globalConfigRepo: cr,
 			serviceGetter:    sg,
+			summary:          summary,
+			recorder:         recorder,
 		}
 
 		err := a.ApplyInitialFQDN(testCtx, time.Second)
 
 		require.NoError(t, err)
+		assert.Equal(t, report.PhaseFQDN, summary.Phase())
+		assert.Equal(t, report.KeyCounts{Created: 1}, summary.Keys(report.RepoGlobal))
+		assert.Equal(t, []string{
+			`FQDNResolved Service/ces-loadbalancer: Resolved fqdn "203.0.113.10" from the external address`,
+		}, recordedEvents(t, clientSet))
 	})
 
 	t.Run("should fail to apply initial fqdn on error getting global-config", func(t *testing.T) {
@@ -283,15 +317,23 @@ func TestApplier_ApplyInitialFQDN(t *testing.T) {
 		sg := newMockServiceGetter(t)
 		sg.EXPECT().Get(mock.Anything, cesLoadBalancerServiceName, metav1.GetOptions{}).Return(nil, assert.AnError)
 
+		recorder, clientSet := newTestRecorder()
+		summary := report.NewSummary()
 		a := &Applier{
 			globalConfigRepo: cr,
 			serviceGetter:    sg,
+			summary:          summary,
+			recorder:         recorder,
 		}
 
 		err := a.ApplyInitialFQDN(testCtx, time.Millisecond*10)
 
 		require.Error(t, err)
 		assert.ErrorContains(t, err, "error getting fqdn from load balancer service: timed out after 10ms waiting for external address on service \"ces-loadbalancer\"")
+		assert.Equal(t, []string{
+			"FQDNTimeout Service/ces-loadbalancer: Timed out after 10ms waiting for an external address",
+		}, recordedEvents(t, clientSet))
+		assert.GreaterOrEqual(t, summary.FQDNWait(), 10*time.Millisecond)
 	})
 
 	t.Run("should not apply initial fqdn if already set", func(t *testing.T) {
@@ -305,14 +347,17 @@ func TestApplier_ApplyInitialFQDN(t *testing.T) {
 
 		sg := newMockServiceGetter(t)
 
+		summary := report.NewSummary()
 		a := &Applier{
 			globalConfigRepo: cr,
 			serviceGetter:    sg,
+			summary:          summary,
 		}
 
 		err = a.ApplyInitialFQDN(testCtx, time.Second)
 
 		require.NoError(t, err)
+		assert.Equal(t, report.KeyCounts{Skipped: 1}, summary.Keys(report.RepoGlobal))
 	})
 }
 
@@ -321,10 +366,15 @@ func TestNewApplier(t *testing.T) {
 		cr := newMockGlobalConfigRepo(t)
 		sg := newMockServiceGetter(t)
 
-		applier := NewApplier(cr, sg)
+		summary := report.NewSummary()
+		recorder, _ := newTestRecorder()
+
+		applier := NewApplier(cr, sg, summary, recorder)
 
 		require.NotNil(t, applier)
 		assert.Equal(t, cr, applier.globalConfigRepo)
 		assert.Equal(t, sg, applier.serviceGetter)
+		assert.Same(t, summary, applier.summary)
+		assert.Same(t, recorder, applier.recorder)
 	})
 }
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/ecosystem-core/default-config/etcdimport"
+	"github.com/cloudogu/ecosystem-core/default-config/retry"
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"k8s.io/client-go/kubernetes"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// importEtcdOptions configures the import-etcd command, which imports the config of a classic, etcd-based
+// ecosystem into the config of the Kubernetes registry.
+type importEtcdOptions struct {
+	envFile string
+	dump    string
+	// privateKeys are the files of the private keys by dogu.
+	privateKeys privateKeyFiles
+	dryRun      bool
+}
+
+// privateKeyFiles is a flag that can be repeated, e.g. "--private-key ldap=ldap.pem --private-key cas=cas.pem".
+type privateKeyFiles map[string]string
+
+func (p privateKeyFiles) String() string {
+	var pairs []string
+	for _, dogu := range slices.Sorted(maps.Keys(p)) {
+		pairs = append(pairs, dogu+"="+p[dogu])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (p privateKeyFiles) Set(value string) error {
+	dogu, path, ok := strings.Cut(value, "=")
+	if !ok || dogu == "" || path == "" {
+		return fmt.Errorf("private key %q must have the format <dogu>=<file>", value)
+	}
+	p[dogu] = path
+	return nil
+}
+
+func parseImportEtcdOptions(args []string) (importEtcdOptions, error) {
+	opts := importEtcdOptions{privateKeys: privateKeyFiles{}}
+	flags := flag.NewFlagSet("import-etcd", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.envFile, "env-file", "", "file with the variables NAMESPACE and KUBE_CONTEXT_NAME")
+	flags.StringVar(&opts.dump, "dump", "", "JSON export of the etcd keys below /config")
+	flags.Var(opts.privateKeys, "private-key", "PEM encoded private key of a dogu as <dogu>=<file>, can be repeated")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print the mapping report without writing the config")
+
+	if err := flags.Parse(args); err != nil {
+		return importEtcdOptions{}, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+	if opts.dump == "" {
+		return importEtcdOptions{}, fmt.Errorf("%w: --dump must be set", errInvalidJobConfig)
+	}
+
+	return opts, nil
+}
+
+func importEtcdCommand(signalCtx context.Context, stopSignals context.CancelFunc) int {
+	err := runImportEtcd(signalCtx, os.Args[2:], os.Stdout)
+	stopSignals()
+
+	if err != nil {
+		class := classifyError(signalCtx, err)
+		code := exitCodes[class]
+		slog.Error("failed to import etcd config", "err", err, "errorClass", class, "exitCode", code)
+		return code
+	}
+
+	return 0
+}
+
+func runImportEtcd(ctx context.Context, args []string, stdout io.Writer) error {
+	opts, err := parseImportEtcdOptions(args)
+	if err != nil {
+		return err
+	}
+
+	lookup, err := readRegistryConfigsLookup(opts.envFile)
+	if err != nil {
+		return err
+	}
+
+	logLevel, ok := lookup("LOG_LEVEL")
+	if !ok {
+		logLevel = "info"
+	}
+	logFormat, _ := lookup("LOG_FORMAT")
+	// the log is written to stderr, so that the mapping report can be redirected from stdout
+	configureLogger(logLevel, logFormat, false)
+
+	mapping, err := readEtcdMapping(opts)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		return etcdimport.WriteReport(stdout, append(etcdimport.Plan(mapping.Values), mapping.Skipped...))
+	}
+
+	namespace, _ := lookup("NAMESPACE")
+	if namespace == "" {
+		return fmt.Errorf("%w: NAMESPACE must be set", errInvalidJobConfig)
+	}
+
+	kubeContext, _ := lookup("KUBE_CONTEXT_NAME")
+	clusterConfig, err := ctrlconfig.GetConfigWithContext(kubeContext)
+	if err != nil {
+		return fmt.Errorf("failed to read kube config: %w", err)
+	}
+
+	clientSet, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	slog.Info("importing etcd config...", "namespace", namespace)
+	policy := retry.DefaultPolicy()
+	// the clients retry transient errors, the repositories only repeat a conflicting merge
+	configMapClient := retry.NewConfigMapClient(clientSet.CoreV1().ConfigMaps(namespace), policy)
+	secretClient := retry.NewSecretClient(clientSet.CoreV1().Secrets(namespace), policy)
+	writer := etcdimport.NewWriter(
+		retry.NewGlobalConfigRepository(repository.NewGlobalConfigRepository(configMapClient), policy),
+		retry.NewDoguConfigRepository(repository.NewDoguConfigRepository(configMapClient), policy),
+		retry.NewDoguConfigRepository(repository.NewSensitiveDoguConfigRepository(secretClient), policy),
+	)
+
+	entries, err := writer.Write(ctx, mapping.Values)
+	// the report of a failed import lists the keys that have been written before the error
+	if rErr := etcdimport.WriteReport(stdout, append(entries, mapping.Skipped...)); rErr != nil {
+		return errors.Join(err, rErr)
+	}
+
+	return err
+}
+
+// readEtcdMapping reads the export and the private keys and maps the values onto the config repositories.
+func readEtcdMapping(opts importEtcdOptions) (etcdimport.Mapping, error) {
+	dump, err := os.Open(opts.dump)
+	if err != nil {
+		return etcdimport.Mapping{}, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+	defer func() { _ = dump.Close() }()
+
+	entries, err := etcdimport.ReadDump(dump)
+	if err != nil {
+		return etcdimport.Mapping{}, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+
+	privateKeys := map[string][]byte{}
+	for dogu, path := range opts.privateKeys {
+		if privateKeys[dogu], err = os.ReadFile(path); err != nil {
+			return etcdimport.Mapping{}, fmt.Errorf("%w: failed to read private key of dogu %q: %w", errInvalidJobConfig, dogu, err)
+		}
+	}
+
+	mapping, err := etcdimport.Map(entries, privateKeys)
+	if err != nil {
+		return etcdimport.Mapping{}, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+
+	return mapping, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cloudogu/cesapp-lib/keys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFile(t *testing.T, name string, content []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, content, 0o600))
+	return path
+}
+
+func Test_parseImportEtcdOptions(t *testing.T) {
+	t.Run("should parse flags", func(t *testing.T) {
+		opts, err := parseImportEtcdOptions([]string{"--dump", "etcd.json", "--private-key", "ldap=ldap.pem", "--private-key", "cas=cas.pem", "--dry-run"})
+
+		require.NoError(t, err)
+		assert.Equal(t, importEtcdOptions{
+			dump:        "etcd.json",
+			privateKeys: privateKeyFiles{"ldap": "ldap.pem", "cas": "cas.pem"},
+			dryRun:      true,
+		}, opts)
+		assert.Equal(t, "cas=cas.pem,ldap=ldap.pem", opts.privateKeys.String())
+	})
+
+	t.Run("should fail without dump", func(t *testing.T) {
+		_, err := parseImportEtcdOptions(nil)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "--dump must be set")
+	})
+
+	t.Run("should fail on invalid private key flag", func(t *testing.T) {
+		_, err := parseImportEtcdOptions([]string{"--dump", "etcd.json", "--private-key", "ldap.pem"})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, `private key "ldap.pem" must have the format <dogu>=<file>`)
+	})
+}
+
+func Test_runImportEtcd(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should print mapping report on dry run", func(t *testing.T) {
+		provider, err := keys.NewKeyProvider("pkcs1v15")
+		require.NoError(t, err)
+		keyPair, err := provider.Generate()
+		require.NoError(t, err)
+		privateKey, err := keyPair.Private().AsBytes()
+		require.NoError(t, err)
+		encrypted, err := keyPair.Public().Encrypt("topSecret")
+		require.NoError(t, err)
+
+		dump := writeTestFile(t, "etcd.json", []byte(`{
+  "/config/_global/key_provider": "pkcs1v15",
+  "/config/_global/fqdn": "ces.example.com",
+  "/config/ldap/admin_password": "`+encrypted+`"
+}`))
+		keyFile := writeTestFile(t, "ldap.pem", privateKey)
+		var out bytes.Buffer
+
+		err = runImportEtcd(testCtx, []string{"--dump", dump, "--private-key", "ldap=" + keyFile, "--dry-run"}, &out)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "/config/_global/fqdn")
+		assert.Contains(t, out.String(), "sensitive-dogu ldap admin_password  planned")
+		assert.Contains(t, out.String(), "planned=2 skipped=1")
+		assert.NotContains(t, out.String(), "topSecret")
+	})
+
+	t.Run("should fail on invalid dump", func(t *testing.T) {
+		err := runImportEtcd(testCtx, []string{"--dump", writeTestFile(t, "etcd.json", []byte("[")), "--dry-run"}, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.Equal(t, errorClassValidation, classifyError(testCtx, err))
+	})
+
+	t.Run("should fail on missing private key", func(t *testing.T) {
+		dump := writeTestFile(t, "etcd.json", []byte("{}"))
+
+		err := runImportEtcd(testCtx, []string{"--dump", dump, "--private-key", "ldap=missing.pem", "--dry-run"}, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.Equal(t, errorClassValidation, classifyError(testCtx, err))
+		assert.ErrorContains(t, err, `failed to read private key of dogu "ldap"`)
+	})
+
+	t.Run("should fail without namespace", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "")
+		dump := writeTestFile(t, "etcd.json", []byte("{}"))
+
+		err := runImportEtcd(testCtx, []string{"--dump", dump}, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "NAMESPACE must be set")
+	})
+}
@@ -0,0 +1,259 @@
+package lease
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const defaultRetryPeriod = 2 * time.Second
+
+var retryPeriod = defaultRetryPeriod
+
+// ErrLost is the cause of the context returned by Acquire if the lease has been taken over by another run or could
+// not be renewed within the lease duration.
+var ErrLost = errors.New("lease lost")
+
+type leaseClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*coordinationv1.Lease, error)
+	Create(ctx context.Context, lease *coordinationv1.Lease, opts metav1.CreateOptions) (*coordinationv1.Lease, error)
+	Update(ctx context.Context, lease *coordinationv1.Lease, opts metav1.UpdateOptions) (*coordinationv1.Lease, error)
+}
+
+// Lock provides mutual exclusion between concurrent runs of the default-config job based on a coordination.k8s.io Lease.
+// A lease that has not been renewed within the lease duration is considered abandoned and is taken over.
+type Lock struct {
+	client        leaseClient
+	name          string
+	identity      string
+	leaseDuration time.Duration
+	now           func() time.Time
+
+	mu          sync.Mutex
+	stopRenewal context.CancelFunc
+	renewalDone chan struct{}
+	lose        context.CancelCauseFunc
+}
+
+func NewLock(client leaseClient, name string, identity string, leaseDuration time.Duration) *Lock {
+	return &Lock{
+		client:        client,
+		name:          name,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		now:           time.Now,
+	}
+}
+
+// Acquire blocks until the lease is held by this lock or waitTimeout is exceeded.
+// The lease is renewed in the background until Release is called. The returned context is cancelled with ErrLost as
+// cause if the lease is lost, so that the work it protects stops.
+func (l *Lock) Acquire(ctx context.Context, waitTimeout time.Duration) (context.Context, error) {
+	waitCtx, cancel := context.WithTimeout(ctx, waitTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(retryPeriod)
+	defer ticker.Stop()
+
+	for {
+		holder, err := l.tryAcquireOrRenew(waitCtx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire lease %q: %w", l.name, err)
+		}
+
+		if holder == l.identity {
+			slog.Info("acquired lease", "lease", l.name, "identity", l.identity)
+			return l.startRenewal(ctx), nil
+		}
+
+		slog.Info("lease is held by another run. Waiting...", "lease", l.name, "holder", holder)
+
+		select {
+		case <-waitCtx.Done():
+			return nil, fmt.Errorf("timed out after %s waiting for lease %q held by %q: %w", waitTimeout, l.name, holder, waitCtx.Err())
+		case <-ticker.C:
+			// retry
+		}
+	}
+}
+
+// Release stops renewing the lease and hands it back, so that a waiting run does not have to wait for the takeover timeout.
+func (l *Lock) Release(ctx context.Context) error {
+	l.mu.Lock()
+	if l.stopRenewal != nil {
+		l.stopRenewal()
+		<-l.renewalDone
+		l.stopRenewal = nil
+		l.lose(nil)
+	}
+	l.mu.Unlock()
+
+	lease, err := l.client.Get(ctx, l.name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get lease %q: %w", l.name, err)
+	}
+
+	if holderOf(lease) != l.identity {
+		slog.Warn("lease is not held anymore. Skipping release...", "lease", l.name, "holder", holderOf(lease))
+		return nil
+	}
+
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+	if _, err = l.client.Update(ctx, lease, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to release lease %q: %w", l.name, err)
+	}
+
+	slog.Info("released lease", "lease", l.name, "identity", l.identity)
+
+	return nil
+}
+
+// tryAcquireOrRenew returns the holder of the lease after trying to acquire or renew it.
+func (l *Lock) tryAcquireOrRenew(ctx context.Context) (string, error) {
+	now := metav1.NewMicroTime(l.now())
+	leaseDurationSeconds := int32(l.leaseDuration.Seconds())
+
+	lease, err := l.client.Get(ctx, l.name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		newLease := &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: l.name},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       &l.identity,
+				LeaseDurationSeconds: &leaseDurationSeconds,
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+
+		_, err = l.client.Create(ctx, newLease, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			// another run created the lease in the meantime
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to create lease: %w", err)
+		}
+
+		return l.identity, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get lease: %w", err)
+	}
+
+	holder := holderOf(lease)
+	if holder != "" && holder != l.identity && !l.isExpired(lease) {
+		return holder, nil
+	}
+
+	if holder != l.identity {
+		if holder != "" {
+			slog.Warn("taking over expired lease", "lease", l.name, "previousHolder", holder)
+		}
+
+		transitions := int32(0)
+		if lease.Spec.LeaseTransitions != nil {
+			transitions = *lease.Spec.LeaseTransitions
+		}
+		transitions++
+
+		lease.Spec.HolderIdentity = &l.identity
+		lease.Spec.AcquireTime = &now
+		lease.Spec.LeaseTransitions = &transitions
+	}
+
+	lease.Spec.RenewTime = &now
+	lease.Spec.LeaseDurationSeconds = &leaseDurationSeconds
+
+	_, err = l.client.Update(ctx, lease, metav1.UpdateOptions{})
+	if apierrors.IsConflict(err) {
+		// another run updated the lease in the meantime, the current holder is unknown
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to update lease: %w", err)
+	}
+
+	return l.identity, nil
+}
+
+func (l *Lock) isExpired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil {
+		return true
+	}
+
+	leaseDuration := l.leaseDuration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		leaseDuration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+
+	return lease.Spec.RenewTime.Add(leaseDuration).Before(l.now())
+}
+
+// startRenewal renews the lease until Release is called and returns a context that is cancelled with ErrLost as cause
+// if the lease is taken over or has not been renewed for the lease duration.
+func (l *Lock) startRenewal(ctx context.Context) context.Context {
+	heldCtx, lose := context.WithCancelCause(ctx)
+	renewCtx, cancel := context.WithCancel(heldCtx)
+	done := make(chan struct{})
+
+	l.mu.Lock()
+	l.stopRenewal = cancel
+	l.renewalDone = done
+	l.lose = lose
+	l.mu.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(max(l.leaseDuration/3, time.Millisecond))
+		defer ticker.Stop()
+
+		renewedAt := l.now()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				holder, err := l.tryAcquireOrRenew(renewCtx)
+				switch {
+				case err == nil && holder == l.identity:
+					renewedAt = l.now()
+				case err == nil && holder != "":
+					slog.Error("lease was taken over by another run", "lease", l.name, "holder", holder)
+					lose(fmt.Errorf("%w: lease %q was taken over by %q", ErrLost, l.name, holder))
+					return
+				case err != nil:
+					slog.Warn("failed to renew lease", "lease", l.name, "err", err)
+				}
+
+				if l.now().Sub(renewedAt) >= l.leaseDuration {
+					slog.Error("lease could not be renewed within the lease duration", "lease", l.name, "leaseDuration", l.leaseDuration)
+					lose(fmt.Errorf("%w: lease %q has not been renewed for %s", ErrLost, l.name, l.leaseDuration))
+					return
+				}
+			}
+		}
+	}()
+
+	return heldCtx
+}
+
+func holderOf(lease *coordinationv1.Lease) string {
+	if lease.Spec.HolderIdentity == nil {
+		return ""
+	}
+
+	return *lease.Spec.HolderIdentity
+}
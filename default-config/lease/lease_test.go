@@ -0,0 +1,260 @@
+package lease
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testNamespace = "ecosystem"
+	testLeaseName = "ecosystem-core-default-config"
+)
+
+func existingLease(holder string, renewTime time.Time) *coordinationv1.Lease {
+	duration := int32(60)
+	renew := metav1.NewMicroTime(renewTime)
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: testLeaseName, Namespace: testNamespace},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &holder,
+			LeaseDurationSeconds: &duration,
+			AcquireTime:          &renew,
+			RenewTime:            &renew,
+		},
+	}
+}
+
+func getHolder(t *testing.T, clientSet *fake.Clientset) string {
+	t.Helper()
+	lease, err := clientSet.CoordinationV1().Leases(testNamespace).Get(context.Background(), testLeaseName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return holderOf(lease)
+}
+
+func TestLock_Acquire(t *testing.T) {
+	testCtx := context.Background()
+
+	originalRetryPeriod := retryPeriod
+	defer func() { retryPeriod = originalRetryPeriod }()
+	retryPeriod = 5 * time.Millisecond
+
+	t.Run("should create lease if it does not exist", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		_, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release(testCtx) }()
+
+		assert.Equal(t, "pod-a", getHolder(t, clientSet))
+	})
+
+	t.Run("should acquire released lease", func(t *testing.T) {
+		lease := existingLease("", time.Now())
+		lease.Spec.HolderIdentity = nil
+		clientSet := fake.NewClientset(lease)
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		_, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release(testCtx) }()
+
+		assert.Equal(t, "pod-a", getHolder(t, clientSet))
+	})
+
+	t.Run("should take over expired lease", func(t *testing.T) {
+		clientSet := fake.NewClientset(existingLease("pod-b", time.Now().Add(-2*time.Minute)))
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		_, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release(testCtx) }()
+
+		lease, err := clientSet.CoordinationV1().Leases(testNamespace).Get(testCtx, testLeaseName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "pod-a", holderOf(lease))
+		assert.Equal(t, int32(1), *lease.Spec.LeaseTransitions)
+	})
+
+	t.Run("should wait until lease is released by other run", func(t *testing.T) {
+		clientSet := fake.NewClientset(existingLease("pod-b", time.Now()))
+		leases := clientSet.CoordinationV1().Leases(testNamespace)
+		other := NewLock(leases, testLeaseName, "pod-b", time.Minute)
+		lock := NewLock(leases, testLeaseName, "pod-a", time.Minute)
+
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			_ = other.Release(testCtx)
+		}()
+
+		_, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release(testCtx) }()
+
+		assert.Equal(t, "pod-a", getHolder(t, clientSet))
+	})
+
+	t.Run("should time out if lease is held by other run", func(t *testing.T) {
+		clientSet := fake.NewClientset(existingLease("pod-b", time.Now()))
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		_, err := lock.Acquire(testCtx, 20*time.Millisecond)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.ErrorContains(t, err, "waiting for lease \"ecosystem-core-default-config\" held by \"pod-b\"")
+		assert.Equal(t, "pod-b", getHolder(t, clientSet))
+	})
+
+	t.Run("should keep waiting if other run wins the race", func(t *testing.T) {
+		clientSet := fake.NewClientset(existingLease("pod-b", time.Now().Add(-2*time.Minute)))
+		clientSet.PrependReactor("update", "leases", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewConflict(coordinationv1.Resource("leases"), testLeaseName, assert.AnError)
+		})
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		_, err := lock.Acquire(testCtx, 20*time.Millisecond)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should fail on api error", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		clientSet.PrependReactor("get", "leases", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, apierrors.NewForbidden(coordinationv1.Resource("leases"), testLeaseName, assert.AnError)
+		})
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		_, err := lock.Acquire(testCtx, time.Second)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to acquire lease")
+		assert.True(t, apierrors.IsForbidden(err))
+	})
+}
+
+func TestLock_renewal(t *testing.T) {
+	t.Run("should renew lease while held", func(t *testing.T) {
+		testCtx := context.Background()
+		clientSet := fake.NewClientset()
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", 30*time.Millisecond)
+
+		_, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+
+		lease, err := clientSet.CoordinationV1().Leases(testNamespace).Get(testCtx, testLeaseName, metav1.GetOptions{})
+		require.NoError(t, err)
+		firstRenewal := lease.Spec.RenewTime.Time
+
+		assert.Eventually(t, func() bool {
+			lease, err := clientSet.CoordinationV1().Leases(testNamespace).Get(testCtx, testLeaseName, metav1.GetOptions{})
+			return err == nil && lease.Spec.RenewTime.After(firstRenewal)
+		}, time.Second, 5*time.Millisecond)
+
+		require.NoError(t, lock.Release(testCtx))
+	})
+
+	t.Run("should cancel the context if the lease is taken over", func(t *testing.T) {
+		testCtx := context.Background()
+		clientSet := fake.NewClientset()
+		leases := clientSet.CoordinationV1().Leases(testNamespace)
+		lock := NewLock(leases, testLeaseName, "pod-a", 30*time.Millisecond)
+
+		heldCtx, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+		defer func() { _ = lock.Release(testCtx) }()
+
+		lease, err := leases.Get(testCtx, testLeaseName, metav1.GetOptions{})
+		require.NoError(t, err)
+		lease.Spec = existingLease("pod-b", time.Now()).Spec
+		_, err = leases.Update(testCtx, lease, metav1.UpdateOptions{})
+		require.NoError(t, err)
+
+		assert.Eventually(t, func() bool { return heldCtx.Err() != nil }, time.Second, 5*time.Millisecond)
+		assert.ErrorIs(t, context.Cause(heldCtx), ErrLost)
+		assert.ErrorContains(t, context.Cause(heldCtx), `taken over by "pod-b"`)
+	})
+
+	t.Run("should cancel the context if the lease cannot be renewed within the lease duration", func(t *testing.T) {
+		testCtx := context.Background()
+		clientSet := fake.NewClientset()
+		var failRenewal atomic.Bool
+		clientSet.PrependReactor("get", "leases", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			if failRenewal.Load() {
+				return true, nil, apierrors.NewServiceUnavailable("unavailable")
+			}
+			return false, nil, nil
+		})
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", 30*time.Millisecond)
+
+		heldCtx, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+		failRenewal.Store(true)
+
+		assert.Eventually(t, func() bool { return heldCtx.Err() != nil }, time.Second, 5*time.Millisecond)
+		assert.ErrorIs(t, context.Cause(heldCtx), ErrLost)
+		assert.ErrorContains(t, context.Cause(heldCtx), "has not been renewed")
+	})
+
+	t.Run("should not cancel the context while the lease is held", func(t *testing.T) {
+		testCtx := context.Background()
+		clientSet := fake.NewClientset()
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", 30*time.Millisecond)
+
+		heldCtx, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		assert.NoError(t, heldCtx.Err())
+
+		require.NoError(t, lock.Release(testCtx))
+		assert.NotErrorIs(t, context.Cause(heldCtx), ErrLost)
+	})
+}
+
+func TestLock_Release(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should not release lease held by other run", func(t *testing.T) {
+		clientSet := fake.NewClientset(existingLease("pod-b", time.Now()))
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		err := lock.Release(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "pod-b", getHolder(t, clientSet))
+	})
+
+	t.Run("should ignore missing lease", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+
+		err := lock.Release(testCtx)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should clear holder of own lease", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		lock := NewLock(clientSet.CoordinationV1().Leases(testNamespace), testLeaseName, "pod-a", time.Minute)
+		_, err := lock.Acquire(testCtx, time.Second)
+		require.NoError(t, err)
+
+		err = lock.Release(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "", getHolder(t, clientSet))
+	})
+}
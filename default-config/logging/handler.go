@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Format is the output format of the log.
+type Format string
+
+const (
+	FormatText Format = "text"
+	// FormatJSON is parsed by the log pipeline of the cluster, e.g. Loki.
+	FormatJSON Format = "json"
+)
+
+// ParseFormat returns the format with the given name. An empty name is the text format.
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return FormatText, fmt.Errorf("unknown log format %q, must be %q or %q", name, FormatText, FormatJSON)
+	}
+}
+
+// NewHandler creates a handler that writes the log in the given format to w and redacts sensitive values.
+func NewHandler(w io.Writer, format Format, level slog.Level, addSource bool) slog.Handler {
+	opts := &slog.HandlerOptions{
+		AddSource: addSource,
+		Level:     level,
+	}
+
+	if format == FormatJSON {
+		return NewRedactHandler(slog.NewJSONHandler(w, opts))
+	}
+
+	return NewRedactHandler(slog.NewTextHandler(w, opts))
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{name: "", want: FormatText},
+		{name: "text", want: FormatText},
+		{name: "json", want: FormatJSON},
+		{name: "logfmt", want: FormatText, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.name)
+
+			assert.Equal(t, tt.want, got)
+			if tt.wantErr {
+				assert.ErrorContains(t, err, `unknown log format "logfmt"`)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	t.Run("should write redacted json", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(NewHandler(&buf, FormatJSON, slog.LevelDebug, true))
+
+		logger.Debug("Setting dogu config key", "dogu", "ldap", "key", "admin_password", "value", "s3cr3t")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "DEBUG", entry["level"])
+		assert.Equal(t, "Setting dogu config key", entry["msg"])
+		assert.Equal(t, "ldap", entry["dogu"])
+		assert.Equal(t, Redacted, entry["value"])
+		assert.Contains(t, entry, "source")
+		assert.NotContains(t, buf.String(), "s3cr3t")
+	})
+
+	t.Run("should write redacted text", func(t *testing.T) {
+		var buf bytes.Buffer
+		logger := slog.New(NewHandler(&buf, FormatText, slog.LevelInfo, false))
+
+		logger.Info("created secret", "token", "abc")
+		logger.Debug("not enabled")
+
+		assert.Contains(t, buf.String(), "level=INFO msg=\"created secret\" token="+Redacted+"\n")
+		assert.NotContains(t, buf.String(), "not enabled")
+		assert.NotContains(t, buf.String(), "source=")
+	})
+}
@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"slices"
+)
+
+// Redacted replaces the values of sensitive attributes.
+const Redacted = "[REDACTED]"
+
+const (
+	// configKeyAttr and configValueAttr are the attribute names used to log a config entry.
+	// The value is redacted if the name of the config key is sensitive.
+	configKeyAttr   = "key"
+	configValueAttr = "value"
+)
+
+// sensitivePattern matches the names of attributes and config keys whose values must never be logged.
+var sensitivePattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private[_-]?key)`)
+
+// sensitiveKeys are config keys that are sensitive although their names do not match sensitivePattern.
+var sensitiveKeys = []string{
+	"certificate/server.key",
+	"server.key",
+}
+
+// IsSensitive reports whether the value of an attribute or config key with the given name must never be logged.
+func IsSensitive(name string) bool {
+	return sensitivePattern.MatchString(name) || slices.Contains(sensitiveKeys, name)
+}
+
+// redactHandler masks the values of sensitive attributes before passing the record to the next handler.
+// An attribute is sensitive if its name is sensitive, or if it is the value of a config entry with a sensitive key,
+// e.g. slog.Debug("Setting dogu config key", "key", "admin_password", "value", password).
+type redactHandler struct {
+	next slog.Handler
+	// sensitiveEntry is set if a sensitive config key has been added with WithAttrs.
+	sensitiveEntry bool
+}
+
+// NewRedactHandler wraps next so that it never receives the values of sensitive attributes.
+func NewRedactHandler(next slog.Handler) slog.Handler {
+	return &redactHandler{next: next}
+}
+
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *redactHandler) Handle(ctx context.Context, record slog.Record) error {
+	var attrs []slog.Attr
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+
+	redacted := slog.NewRecord(record.Time, record.Level, record.Message, record.PC)
+	redacted.AddAttrs(redactAttrs(attrs, h.sensitiveEntry)...)
+
+	return h.next.Handle(ctx, redacted)
+}
+
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &redactHandler{
+		next:           h.next.WithAttrs(redactAttrs(attrs, h.sensitiveEntry)),
+		sensitiveEntry: h.sensitiveEntry || hasSensitiveConfigKey(attrs),
+	}
+}
+
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	// the config key of the enclosing group does not belong to the attributes of the new group
+	return &redactHandler{next: h.next.WithGroup(name)}
+}
+
+func redactAttrs(attrs []slog.Attr, sensitiveEntry bool) []slog.Attr {
+	redactValue := sensitiveEntry || hasSensitiveConfigKey(attrs)
+
+	result := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		result = append(result, redactAttr(attr, redactValue))
+	}
+
+	return result
+}
+
+func redactAttr(attr slog.Attr, redactValue bool) slog.Attr {
+	if IsSensitive(attr.Key) || (redactValue && attr.Key == configValueAttr) {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	// values are resolved before the check, so that a slog.LogValuer cannot bypass it
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(redactAttrs(value.Group(), false)...)}
+	}
+
+	return slog.Attr{Key: attr.Key, Value: value}
+}
+
+func hasSensitiveConfigKey(attrs []slog.Attr) bool {
+	return slices.ContainsFunc(attrs, func(attr slog.Attr) bool {
+		return attr.Key == configKeyAttr && IsSensitive(attr.Value.Resolve().String())
+	})
+}
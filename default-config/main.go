@@ -2,23 +2,63 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"net"
+	"net/url"
 	"os"
+	"os/signal"
 	"strconv"
+	"strings"
+	"syscall"
 	"time"
 
+	"github.com/cloudogu/ecosystem-core/default-config/component"
 	"github.com/cloudogu/ecosystem-core/default-config/config"
+	"github.com/cloudogu/ecosystem-core/default-config/event"
 	"github.com/cloudogu/ecosystem-core/default-config/fqdn"
+	"github.com/cloudogu/ecosystem-core/default-config/lease"
+	"github.com/cloudogu/ecosystem-core/default-config/logging"
+	"github.com/cloudogu/ecosystem-core/default-config/metrics"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/cloudogu/ecosystem-core/default-config/retry"
+	"github.com/cloudogu/ecosystem-core/default-config/tracing"
 	"github.com/cloudogu/k8s-registry-lib/repository"
+	"go.opentelemetry.io/otel/attribute"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
 const (
-	defaultWaitTimeoutMinutes = 5
-	defaultEnableFqdnApply    = false
-	defaultUseLopIdp          = false
+	defaultWaitTimeoutMinutes   = 5
+	defaultEnableFqdnApply      = false
+	defaultUseLopIdp            = false
+	defaultProfilesFile         = "/etc/default-config/profiles.yaml"
+	defaultInitialAdminTTLHours = 24
+
+	defaultRetryMaxAttempts                = retry.DefaultMaxAttempts
+	defaultRetryInitialBackoffMilliseconds = 200
+	defaultRetryMaxBackoffSeconds          = 10
+
+	defaultRunTimeoutMinutes          = 30
+	defaultGlobalConfigTimeoutSeconds = 120
+	defaultDoguConfigTimeoutSeconds   = 120
+	defaultCertificateTimeoutSeconds  = 30
+
+	defaultComponentsTimeoutMinutes = 10
+
+	defaultLeaseName               = "ecosystem-core-default-config"
+	defaultLeaseWaitTimeoutMinutes = 10
+	defaultLeaseDurationSeconds    = 60
+	leaseReleaseTimeout            = 10 * time.Second
+
+	defaultTerminationMessagePath = "/dev/termination-log"
+	metricsPushTimeout            = 10 * time.Second
+	tracingShutdownTimeout        = 10 * time.Second
 )
 
 type configApplier interface {
@@ -29,47 +69,193 @@ type fqdnApplier interface {
 	ApplyInitialFQDN(ctx context.Context, timeout time.Duration) error
 }
 
+// command runs a subcommand of the binary and returns its exit code.
+// stopSignals restores the default handling of signals, so that a second signal terminates the job immediately.
+type command func(signalCtx context.Context, stopSignals context.CancelFunc) int
+
+const defaultCommand = "apply"
+
+// commands are selected by the first argument of the binary. Without an argument, the default config is applied.
+var commands = map[string]command{
+	defaultCommand: applyCommand,
+	"cleanup":      cleanupCommand,
+	"preflight":    preflightCommand,
+	// delete-initial-admin is run locally once the admin has confirmed the first login
+	"delete-initial-admin": deleteInitialAdminCommand,
+	// import-etcd is run locally, e.g. with "go run . import-etcd --dump etcd.json --dry-run"
+	"import-etcd": importEtcdCommand,
+	// rotate-admin-password is run locally, e.g. with "go run . rotate-admin-password --annotate-dogu"
+	"rotate-admin-password": rotateAdminPasswordCommand,
+	// registry-configs is run locally, e.g. with "go run . registry-configs --env-file ../.env"
+	"registry-configs": registryConfigsCommand,
+}
+
 func main() {
-	ctx := context.Background()
+	// Kubernetes sends SIGTERM on node drains or aborted Argo CD syncs
+	signalCtx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+
+	name := defaultCommand
+	if len(os.Args) > 1 {
+		name = os.Args[1]
+	}
+
+	cmd, ok := commands[name]
+	if !ok {
+		slog.Error("unknown command", "command", name)
+		os.Exit(exitCodeValidation)
+	}
+
+	os.Exit(cmd(signalCtx, stop))
+}
+
+func applyCommand(signalCtx context.Context, stopSignals context.CancelFunc) int {
 	cfg := readConfig()
+	summary := report.NewSummary()
+	runMetrics := metrics.New(summary)
+	stopServingMetrics := serveMetrics(cfg, runMetrics)
+
+	err := run(signalCtx, cfg, summary)
+	stopSignals()
+
+	var class errorClass
+	if err != nil {
+		class = classifyError(signalCtx, err)
+	} else {
+		summary.EnterPhase(report.PhaseDone)
+	}
+
+	if tErr := report.WriteTerminationMessage(cfg.terminationMessagePath, summary.Message(string(class), err)); tErr != nil {
+		slog.Warn("could not write termination message", "err", tErr)
+	}
+
+	runMetrics.Finish(err)
+	pushMetrics(cfg, runMetrics)
+	stopServingMetrics()
 
-	err := run(ctx, cfg)
 	if err != nil {
-		panic(fmt.Errorf("failed to run default-config: %w", err))
+		code := exitCodes[class]
+		slog.Error("failed to run default-config", "err", err, "phase", summary.Phase(), "errorClass", class, "exitCode", code)
+		return code
 	}
 
 	slog.Info("exiting")
+	return 0
 }
 
-func run(ctx context.Context, cfg jobConfig) error {
-	configureLogger(cfg.logLevel)
+func run(ctx context.Context, cfg jobConfig, summary *report.Summary) error {
+	configureLogger(cfg.logLevel, cfg.logFormat, cfg.logAddSource)
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	if err := cfg.applyProfile(); err != nil {
+		return err
+	}
+	if cfg.dryRun {
+		return writePlan(cfg, os.Stdout)
+	}
 
-	namespace := os.Getenv("NAMESPACE")
+	if cfg.tracingEndpoint != "" {
+		shutdownTracing, err := tracing.Setup(ctx, cfg.tracingEndpoint, cfg.namespace, cfg.leaseIdentity)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			// the run context may already be cancelled, but the spans of a failed run are of interest too
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tracingShutdownTimeout)
+			defer cancel()
+			if sErr := shutdownTracing(flushCtx); sErr != nil {
+				slog.Warn("could not export traces", "err", sErr)
+			}
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.runTimeout)
+	defer cancel()
+
+	ctx, span := tracing.Start(ctx, tracing.ServiceName)
+	err := applyWithClients(ctx, cfg, summary)
+	if err != nil && ctx.Err() != nil && !errors.Is(err, ctx.Err()) {
+		// the registry errors do not preserve the context error
+		err = fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+	tracing.End(span, err)
+
+	return err
+}
+
+func applyWithClients(ctx context.Context, cfg jobConfig, summary *report.Summary) error {
+	namespace := cfg.namespace
+	cfg.retryPolicy.OnRetry = func(string, int, error) { summary.AddRetry() }
 	slog.Info("starting applying default-configs...", "namespace", namespace)
 
 	clusterConfig, err := ctrl.GetConfig()
 	if err != nil {
 		return fmt.Errorf("failed to read kube config: %w", err)
 	}
+	if cfg.tracingEndpoint != "" {
+		clusterConfig.Wrap(tracing.WrapTransport)
+	}
 
 	k8sClientSet, err := kubernetes.NewForConfig(clusterConfig)
 	if err != nil {
 		return fmt.Errorf("failed to create k8s client set: %w", err)
 	}
 
-	k8sConfigMapClient := k8sClientSet.CoreV1().ConfigMaps(namespace)
-	k8sSecretClient := k8sClientSet.CoreV1().Secrets(namespace)
+	summary.EnterPhase(report.PhaseLease)
+	lock := lease.NewLock(k8sClientSet.CoordinationV1().Leases(namespace), cfg.leaseName, cfg.leaseIdentity, cfg.leaseDuration)
+	// the span only measures the wait, the renewal of the lease is not part of it
+	_, leaseSpan := tracing.Start(ctx, string(report.PhaseLease), attribute.String("lease", cfg.leaseName))
+	heldCtx, err := lock.Acquire(ctx, cfg.leaseWaitTimeout)
+	tracing.End(leaseSpan, err)
+	if err != nil {
+		return fmt.Errorf("failed to wait for other default-config runs: %w", err)
+	}
+	defer func() {
+		// the run context may already be cancelled, but the lease should be handed back anyway
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), leaseReleaseTimeout)
+		defer cancel()
+		if rErr := lock.Release(releaseCtx); rErr != nil {
+			slog.Warn("failed to release lease. Other runs have to wait until it expires.", "err", rErr)
+		}
+	}()
+
+	// the run stops as soon as the lease is lost, because another run may write the config then
+	err = applyHoldingLease(heldCtx, cfg, clusterConfig, k8sClientSet, summary)
+	if cause := context.Cause(heldCtx); err != nil && errors.Is(cause, lease.ErrLost) {
+		return fmt.Errorf("%w: %w", cause, err)
+	}
+
+	return err
+}
+
+// applyHoldingLease applies the defaults. ctx is cancelled if the lease of the run is lost.
+func applyHoldingLease(ctx context.Context, cfg jobConfig, clusterConfig *rest.Config, k8sClientSet *kubernetes.Clientset, summary *report.Summary) error {
+	namespace := cfg.namespace
+	if err := waitForComponents(ctx, cfg, clusterConfig, summary); err != nil {
+		return err
+	}
+
+	// the clients retry transient errors, the repositories only repeat a conflicting merge
+	k8sConfigMapClient := retry.NewConfigMapClient(k8sClientSet.CoreV1().ConfigMaps(namespace), cfg.retryPolicy)
+	k8sSecretClient := retry.NewSecretClient(k8sClientSet.CoreV1().Secrets(namespace), cfg.retryPolicy)
 	k8sServicesClient := k8sClientSet.CoreV1().Services(namespace)
+	recorder := event.NewRecorder(k8sClientSet.CoreV1().Events(namespace), namespace, cfg.leaseIdentity)
 
-	globalConfigRepo := repository.NewGlobalConfigRepository(k8sConfigMapClient)
-	doguConfigRepo := repository.NewDoguConfigRepository(k8sConfigMapClient)
-	sensitiveDoguConfigRepo := repository.NewSensitiveDoguConfigRepository(k8sSecretClient)
+	globalConfigRepo := retry.NewGlobalConfigRepository(repository.NewGlobalConfigRepository(k8sConfigMapClient), cfg.retryPolicy)
+	doguConfigRepo := retry.NewDoguConfigRepository(repository.NewDoguConfigRepository(k8sConfigMapClient), cfg.retryPolicy)
+	sensitiveDoguConfigRepo := retry.NewDoguConfigRepository(repository.NewSensitiveDoguConfigRepository(k8sSecretClient), cfg.retryPolicy)
 
-	initialDomain := os.Getenv("INITIAL_DOMAIN")
-	initialFQDN := os.Getenv("INITIAL_FQDN")
+	blueprint, err := loadBlueprint(ctx, cfg, clusterConfig)
+	if err != nil {
+		return err
+	}
 
-	ca := config.NewDefaultConfigApplier(globalConfigRepo, doguConfigRepo, sensitiveDoguConfigRepo, k8sSecretClient, initialDomain, initialFQDN, cfg.useLopIdp)
-	fa := fqdn.NewApplier(globalConfigRepo, k8sServicesClient)
+	opts := cfg.applierOptions()
+	opts.Blueprint = blueprint
+	opts.InitialAdmin = cfg.initialAdmin()
+	ca := config.NewDefaultConfigApplier(globalConfigRepo, doguConfigRepo, sensitiveDoguConfigRepo, k8sSecretClient, opts, summary, recorder)
+	fa := fqdn.NewApplier(globalConfigRepo, k8sServicesClient, summary, recorder)
 
 	if err = applyDefaults(ctx, cfg, ca, fa); err != nil {
 		return fmt.Errorf("failed to apply default config: %w", err)
@@ -78,6 +264,58 @@ func run(ctx context.Context, cfg jobConfig) error {
 	return nil
 }
 
+// writePlan writes the resolved global and dogu defaults and their layers. It does not access the cluster.
+func writePlan(cfg jobConfig, w io.Writer) error {
+	// the plan only resolves the layers of the defaults, so the applier needs no clients
+	ca := config.NewDefaultConfigApplier(nil, nil, nil, nil, cfg.applierOptions(), report.NewSummary(), nil)
+	if cfg.blueprintName != "" {
+		slog.Info("The config of the blueprint is not part of the plan", "blueprint", cfg.blueprintName)
+	}
+
+	return config.WritePlan(w, ca.Plan())
+}
+
+// loadBlueprint reads the config of the configured Blueprint CR. It returns nil if no blueprint is configured or the
+// Blueprint CR does not exist.
+func loadBlueprint(ctx context.Context, cfg jobConfig, clusterConfig *rest.Config) (*config.Blueprint, error) {
+	if cfg.blueprintName == "" {
+		return nil, nil
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(clusterConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	return config.LoadBlueprint(ctx, dynamicClient.Resource(config.BlueprintResource).Namespace(cfg.namespace), cfg.blueprintName)
+}
+
+// waitForComponents waits until the configured components are ready, e.g. the k8s-dogu-operator.
+func waitForComponents(ctx context.Context, cfg jobConfig, clusterConfig *rest.Config, summary *report.Summary) (err error) {
+	if len(cfg.components) == 0 {
+		return nil
+	}
+
+	summary.EnterPhase(report.PhaseComponents)
+	ctx, span := tracing.Start(ctx, string(report.PhaseComponents))
+	defer func() { tracing.End(span, err) }()
+
+	dynamicClient, err := dynamic.NewForConfig(clusterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create dynamic client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.componentsTimeout)
+	defer cancel()
+
+	gate := component.NewGate(dynamicClient.Resource(component.Resource).Namespace(cfg.namespace), cfg.components)
+	if err = gate.WaitUntilReady(ctx); err != nil {
+		return fmt.Errorf("failed to wait for components: %w", err)
+	}
+
+	return nil
+}
+
 func applyDefaults(ctx context.Context, cfg jobConfig, configApplier configApplier, fqdnApplier fqdnApplier) error {
 	if err := configApplier.ApplyDefaultConfig(ctx); err != nil {
 		return fmt.Errorf("failed to apply default config: %w", err)
@@ -92,7 +330,38 @@ func applyDefaults(ctx context.Context, cfg jobConfig, configApplier configAppli
 	return nil
 }
 
-func configureLogger(logLevel string) {
+// serveMetrics exposes the metrics of the run if a listen address is configured. The returned func stops serving.
+func serveMetrics(cfg jobConfig, m *metrics.Metrics) context.CancelFunc {
+	ctx, cancel := context.WithCancel(context.Background())
+	if cfg.metricsListenAddress == "" {
+		return cancel
+	}
+
+	go func() {
+		if err := m.Serve(ctx, cfg.metricsListenAddress); err != nil {
+			slog.Warn("could not serve metrics", "err", err)
+		}
+	}()
+
+	return cancel
+}
+
+// pushMetrics pushes the metrics of the run to the Pushgateway if one is configured.
+// A failed push is logged but does not fail the job.
+func pushMetrics(cfg jobConfig, m *metrics.Metrics) {
+	if cfg.metricsPushgatewayURL == "" {
+		return
+	}
+
+	// the run context may already be cancelled, but the metrics of an interrupted run are of interest too
+	ctx, cancel := context.WithTimeout(context.Background(), metricsPushTimeout)
+	defer cancel()
+	if err := m.Push(ctx, cfg.metricsPushgatewayURL, cfg.namespace); err != nil {
+		slog.Warn("could not push metrics", "err", err)
+	}
+}
+
+func configureLogger(logLevel string, logFormat string, addSource bool) {
 	var level slog.Level
 	var err = level.UnmarshalText([]byte(logLevel))
 	if err != nil {
@@ -100,47 +369,312 @@ func configureLogger(logLevel string) {
 		level = slog.LevelInfo
 	}
 
-	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
-		AddSource: false,
-		Level:     level,
-	}))
+	format, err := logging.ParseFormat(logFormat)
+	if err != nil {
+		slog.Error("error parsing log format. Setting log format to text.", "err", err)
+	}
+
+	logger := slog.New(logging.NewHandler(os.Stderr, format, level, addSource))
 	slog.SetDefault(logger)
 
-	slog.Info("configured logger", "level", level.String())
+	slog.Info("configured logger", "level", level.String(), "format", format)
 }
 
 type jobConfig struct {
 	namespace       string
 	logLevel        string
+	logFormat       string
+	logAddSource    bool
 	waitTimeout     time.Duration
 	enableFqdnApply bool
 	useLopIdp       bool
+	initialDomain   string
+	initialFQDN     string
+	// initialInternalIP is the k8s/internal_ip of the global config, which the dogus use if k8s/use_internal_ip is set.
+	initialInternalIP string
+	profileName       string
+	profilesFile      string
+	profile           config.Profile
+	retryPolicy       retry.Policy
+	leaseName         string
+	leaseIdentity     string
+	leaseWaitTimeout  time.Duration
+	leaseDuration     time.Duration
+	runTimeout        time.Duration
+	phaseTimeouts     config.Timeouts
+
+	// lopIdp configures CAS for the LOP IdP as JSON. It is only used if useLopIdp is set and the defaults apply if empty.
+	lopIdp string
+	// externalLdap configures CAS for an external LDAP or Active Directory as JSON. The ldap dogu is used if empty.
+	externalLdap string
+	// smtpRelay configures postfix for an SMTP relay as JSON. Postfix is not configured if empty.
+	smtpRelay string
+	// proxy configures the HTTP proxy in the global config as JSON. No proxy is configured if empty.
+	proxy string
+	// extraDefaults are the global and dogu defaults of the chart values as JSON. No defaults are added if empty.
+	extraDefaults string
+	// blueprintName is the Blueprint CR whose config takes precedence over the defaults. Empty disables it.
+	blueprintName string
+
+	// initialAdminSecret is the Secret the generated admin credentials are stored in. Empty disables the Secret.
+	initialAdminSecret string
+	initialAdminTTL    time.Duration
+
+	// components are waited for until they are ready before the defaults are applied. Empty disables the wait.
+	components        []string
+	componentsTimeout time.Duration
+
+	// dryRun writes the plan of the resolved defaults to stdout instead of applying them.
+	dryRun bool
+
+	terminationMessagePath string
+	metricsPushgatewayURL  string
+	metricsListenAddress   string
+	tracingEndpoint        string
+
+	// envErr contains the environment variables with malformed values.
+	envErr error
 }
 
-func readConfig() jobConfig {
-	waitTimeoutMinutes, err := strconv.Atoi(os.Getenv("WAIT_TIMEOUT_MINUTES"))
-	if err != nil {
-		slog.Warn("failed to parse WAIT_TIMEOUT_MINUTES. Using default value.", "err", err, "defaultWaitTimeoutMinutes", defaultWaitTimeoutMinutes)
-		waitTimeoutMinutes = defaultWaitTimeoutMinutes
+func (c jobConfig) validate() error {
+	var errs []error
+	if c.envErr != nil {
+		errs = append(errs, c.envErr)
+	}
+	if c.namespace == "" {
+		errs = append(errs, errors.New("NAMESPACE must be set"))
+	}
+	if c.runTimeout <= 0 {
+		errs = append(errs, errors.New("RUN_TIMEOUT_MINUTES must be positive"))
+	}
+	if c.waitTimeout <= 0 {
+		errs = append(errs, errors.New("WAIT_TIMEOUT_MINUTES must be positive"))
+	}
+	if c.phaseTimeouts.GlobalConfig < 0 || c.phaseTimeouts.DoguConfig < 0 || c.phaseTimeouts.Certificate < 0 {
+		errs = append(errs, errors.New("phase timeouts must not be negative"))
+	}
+	if c.leaseWaitTimeout <= 0 || c.leaseDuration <= 0 {
+		errs = append(errs, errors.New("LEASE_WAIT_TIMEOUT_MINUTES and LEASE_DURATION_SECONDS must be positive"))
+	}
+	if len(c.components) > 0 && c.componentsTimeout <= 0 {
+		errs = append(errs, errors.New("COMPONENTS_TIMEOUT_MINUTES must be positive"))
+	}
+	if c.retryPolicy.MaxAttempts < 1 {
+		errs = append(errs, errors.New("RETRY_MAX_ATTEMPTS must be at least 1"))
+	}
+	if c.tracingEndpoint != "" && !isHTTPURL(c.tracingEndpoint) {
+		errs = append(errs, errors.New("OTEL_EXPORTER_OTLP_ENDPOINT must be an http or https URL"))
+	}
+	if c.metricsPushgatewayURL != "" {
+		if !isHTTPURL(c.metricsPushgatewayURL) {
+			errs = append(errs, errors.New("METRICS_PUSHGATEWAY_URL must be an http or https URL"))
+		}
+	}
+	if c.initialInternalIP != "" && net.ParseIP(c.initialInternalIP) == nil {
+		errs = append(errs, fmt.Errorf("INITIAL_INTERNAL_IP %q must be an IP address", c.initialInternalIP))
+	}
+	if c.useLopIdp {
+		if err := config.ValidateLopIdp(c.initialDomain, c.initialFQDN); err != nil {
+			errs = append(errs, err)
+		}
+		if _, err := config.ParseLopIdp(c.lopIdp); err != nil {
+			errs = append(errs, fmt.Errorf("LOP_IDP: %w", err))
+		}
+		if c.externalLdap != "" {
+			errs = append(errs, errors.New("EXTERNAL_LDAP must not be set if the LOP IdP is used"))
+		}
+	}
+	if _, err := config.ParseExternalLdap(c.externalLdap); err != nil {
+		errs = append(errs, fmt.Errorf("EXTERNAL_LDAP: %w", err))
+	}
+	if _, err := config.ParseSMTPRelay(c.smtpRelay); err != nil {
+		errs = append(errs, fmt.Errorf("SMTP_RELAY: %w", err))
+	}
+	if _, err := config.ParseProxy(c.proxy); err != nil {
+		errs = append(errs, fmt.Errorf("PROXY: %w", err))
+	}
+	if _, err := config.ParseExtraDefaults(c.extraDefaults); err != nil {
+		errs = append(errs, fmt.Errorf("EXTRA_DEFAULTS: %w", err))
+	}
+	if c.initialAdminSecret != "" && c.initialAdminTTL <= 0 {
+		errs = append(errs, errors.New("INITIAL_ADMIN_TTL_HOURS must be positive"))
 	}
 
-	enableFqdnApply, err := strconv.ParseBool(os.Getenv("ENABLE_FQDN_APPLY"))
-	if err != nil {
-		slog.Warn("failed to parse ENABLE_FQDN_APPLY. Using default value.", "err", err, "defaultEnableFqdnApply", defaultEnableFqdnApply)
-		enableFqdnApply = defaultEnableFqdnApply
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", errInvalidJobConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// applierOptions returns the options of the defaults without the blueprint and the initial admin, which are only
+// needed to apply the defaults. The config must have been validated.
+func (c jobConfig) applierOptions() config.ApplierOptions {
+	opts := config.ApplierOptions{
+		InitialDomain:     c.initialDomain,
+		InitialFQDN:       c.initialFQDN,
+		InitialInternalIP: c.initialInternalIP,
+		Profile:           c.profile,
+		Timeouts:          c.phaseTimeouts,
+	}
+	if c.useLopIdp {
+		opts.LopIdp, _ = config.ParseLopIdp(c.lopIdp)
+	}
+	opts.ExternalLdap, _ = config.ParseExternalLdap(c.externalLdap)
+	opts.SMTPRelay, _ = config.ParseSMTPRelay(c.smtpRelay)
+	opts.Proxy, _ = config.ParseProxy(c.proxy)
+	opts.ExtraDefaults, _ = config.ParseExtraDefaults(c.extraDefaults)
+
+	return opts
+}
+
+// initialAdmin returns the config of the Secret for the initial admin credentials or nil if it is disabled.
+func (c jobConfig) initialAdmin() *config.InitialAdmin {
+	if c.initialAdminSecret == "" {
+		return nil
 	}
 
-	useLopIdp, err := strconv.ParseBool(os.Getenv("USE_LOP_IDP"))
+	return &config.InitialAdmin{SecretName: c.initialAdminSecret, TTL: c.initialAdminTTL}
+}
+
+// applyProfile loads the selected profile, verifies its requirements and applies its fqdn strategy.
+func (c *jobConfig) applyProfile() error {
+	profile, err := config.LoadProfile(c.profileName, c.profilesFile)
 	if err != nil {
-		slog.Warn("failed to parse USE_LOP_IDP. Using default value.", "err", err, "defaultUseLopIdp", defaultUseLopIdp)
-		useLopIdp = defaultUseLopIdp
+		return fmt.Errorf("%w: PROFILE: %w", errInvalidJobConfig, err)
+	}
+	if err = profile.Validate(c.initialDomain, c.initialFQDN, c.initialInternalIP); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+
+	c.profile = profile
+	c.enableFqdnApply = profile.FQDNApplyEnabled(c.enableFqdnApply)
+
+	return nil
+}
+
+func isHTTPURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func readConfig() jobConfig {
+	env := &envReader{}
+	waitTimeoutMinutes := env.readInt("WAIT_TIMEOUT_MINUTES", defaultWaitTimeoutMinutes)
+	enableFqdnApply := env.readBool("ENABLE_FQDN_APPLY", defaultEnableFqdnApply)
+	useLopIdp := env.readBool("USE_LOP_IDP", defaultUseLopIdp)
+
+	retryPolicy := retry.DefaultPolicy()
+	retryPolicy.MaxAttempts = env.readInt("RETRY_MAX_ATTEMPTS", defaultRetryMaxAttempts)
+	retryPolicy.InitialBackoff = time.Duration(env.readInt("RETRY_INITIAL_BACKOFF_MILLISECONDS", defaultRetryInitialBackoffMilliseconds)) * time.Millisecond
+	retryPolicy.MaxBackoff = time.Duration(env.readInt("RETRY_MAX_BACKOFF_SECONDS", defaultRetryMaxBackoffSeconds)) * time.Second
+
+	// the pod name identifies the lease holder, the hostname equals the pod name inside a pod
+	leaseIdentity := os.Getenv("POD_NAME")
+	if leaseIdentity == "" {
+		leaseIdentity, _ = os.Hostname()
 	}
 
 	return jobConfig{
-		namespace:       os.Getenv("NAMESPACE"),
-		logLevel:        os.Getenv("LOG_LEVEL"),
-		waitTimeout:     time.Duration(waitTimeoutMinutes) * time.Minute,
-		enableFqdnApply: enableFqdnApply,
-		useLopIdp:       useLopIdp,
+		namespace:          os.Getenv("NAMESPACE"),
+		logLevel:           os.Getenv("LOG_LEVEL"),
+		logFormat:          os.Getenv("LOG_FORMAT"),
+		logAddSource:       env.readBool("LOG_ADD_SOURCE", false),
+		waitTimeout:        time.Duration(waitTimeoutMinutes) * time.Minute,
+		enableFqdnApply:    enableFqdnApply,
+		useLopIdp:          useLopIdp,
+		initialDomain:      os.Getenv("INITIAL_DOMAIN"),
+		initialFQDN:        os.Getenv("INITIAL_FQDN"),
+		initialInternalIP:  os.Getenv("INITIAL_INTERNAL_IP"),
+		profileName:        os.Getenv("PROFILE"),
+		profilesFile:       readStringEnv("PROFILES_FILE", defaultProfilesFile),
+		lopIdp:             os.Getenv("LOP_IDP"),
+		externalLdap:       os.Getenv("EXTERNAL_LDAP"),
+		smtpRelay:          os.Getenv("SMTP_RELAY"),
+		proxy:              os.Getenv("PROXY"),
+		extraDefaults:      os.Getenv("EXTRA_DEFAULTS"),
+		blueprintName:      os.Getenv("BLUEPRINT_NAME"),
+		dryRun:             env.readBool("DRY_RUN", false),
+		initialAdminSecret: os.Getenv("INITIAL_ADMIN_SECRET"),
+		initialAdminTTL:    time.Duration(env.readInt("INITIAL_ADMIN_TTL_HOURS", defaultInitialAdminTTLHours)) * time.Hour,
+		retryPolicy:        retryPolicy,
+		leaseName:          readStringEnv("LEASE_NAME", defaultLeaseName),
+		leaseIdentity:      leaseIdentity,
+		leaseWaitTimeout:   time.Duration(env.readInt("LEASE_WAIT_TIMEOUT_MINUTES", defaultLeaseWaitTimeoutMinutes)) * time.Minute,
+		leaseDuration:      time.Duration(env.readInt("LEASE_DURATION_SECONDS", defaultLeaseDurationSeconds)) * time.Second,
+		runTimeout:         time.Duration(env.readInt("RUN_TIMEOUT_MINUTES", defaultRunTimeoutMinutes)) * time.Minute,
+		phaseTimeouts: config.Timeouts{
+			GlobalConfig: time.Duration(env.readInt("GLOBAL_CONFIG_TIMEOUT_SECONDS", defaultGlobalConfigTimeoutSeconds)) * time.Second,
+			DoguConfig:   time.Duration(env.readInt("DOGU_CONFIG_TIMEOUT_SECONDS", defaultDoguConfigTimeoutSeconds)) * time.Second,
+			Certificate:  time.Duration(env.readInt("CERTIFICATE_TIMEOUT_SECONDS", defaultCertificateTimeoutSeconds)) * time.Second,
+		},
+		terminationMessagePath: readStringEnv("TERMINATION_MESSAGE_PATH", defaultTerminationMessagePath),
+		metricsPushgatewayURL:  os.Getenv("METRICS_PUSHGATEWAY_URL"),
+		metricsListenAddress:   os.Getenv("METRICS_LISTEN_ADDRESS"),
+		tracingEndpoint:        os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		components:             readListEnv("WAIT_FOR_COMPONENTS"),
+		componentsTimeout:      time.Duration(env.readInt("COMPONENTS_TIMEOUT_MINUTES", defaultComponentsTimeoutMinutes)) * time.Minute,
+		envErr:                 env.err(),
+	}
+}
+
+func readStringEnv(name string, defaultValue string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
 	}
+
+	return defaultValue
+}
+
+// readListEnv reads a comma-separated list. Empty entries are left out.
+func readListEnv(name string) []string {
+	var list []string
+	for _, entry := range strings.Split(os.Getenv(name), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+
+	return list
+}
+
+// envReader reads typed environment variables. Unset variables return their default. A malformed value returns the
+// default as well, but is recorded, so that the validation of the config fails.
+type envReader struct {
+	errs []error
+}
+
+func (r *envReader) readInt(name string, defaultValue int) int {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be an integer, got %q", name, raw))
+		return defaultValue
+	}
+
+	return value
+}
+
+func (r *envReader) readBool(name string, defaultValue bool) bool {
+	raw := os.Getenv(name)
+	if raw == "" {
+		return defaultValue
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		r.errs = append(r.errs, fmt.Errorf("%s must be a boolean, got %q", name, raw))
+		return defaultValue
+	}
+
+	return value
+}
+
+// err returns the malformed values or nil.
+func (r *envReader) err() error {
+	return errors.Join(r.errs...)
 }
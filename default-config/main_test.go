@@ -2,13 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/ecosystem-core/default-config/config"
+	"github.com/cloudogu/ecosystem-core/default-config/lease"
+	"github.com/cloudogu/ecosystem-core/default-config/preflight"
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/cloudogu/ecosystem-core/default-config/retry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
 func Test_applyDefaults(t *testing.T) {
@@ -68,12 +78,6 @@ func Test_applyDefaults(t *testing.T) {
 	})
 }
 
-func Test_run(t *testing.T) {
-	t.Run("should run default-config job", func(t *testing.T) {
-
-	})
-}
-
 func Test_readConfig(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		defer func() {
@@ -109,12 +113,510 @@ func Test_readConfig(t *testing.T) {
 		assert.Equal(t, false, job.enableFqdnApply)
 		assert.Equal(t, false, job.useLopIdp)
 		assert.Equal(t, time.Duration(5)*time.Minute, job.waitTimeout)
+		assert.Equal(t, retry.DefaultMaxAttempts, job.retryPolicy.MaxAttempts)
+		assert.Equal(t, 200*time.Millisecond, job.retryPolicy.InitialBackoff)
+		assert.Equal(t, 10*time.Second, job.retryPolicy.MaxBackoff)
+		assert.Empty(t, job.profileName)
+		assert.Equal(t, defaultProfilesFile, job.profilesFile)
+	})
+	t.Run("should record malformed values for the validation", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "ecosystem")
+		t.Setenv("RETRY_MAX_ATTEMPTS", "abc")
+		t.Setenv("DRY_RUN", "maybe")
+
+		job := readConfig()
+
+		assert.Equal(t, retry.DefaultMaxAttempts, job.retryPolicy.MaxAttempts)
+		assert.False(t, job.dryRun)
+		err := job.validate()
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, `RETRY_MAX_ATTEMPTS must be an integer, got "abc"`)
+		assert.ErrorContains(t, err, `DRY_RUN must be a boolean, got "maybe"`)
+		assert.Equal(t, errorClassValidation, classifyError(context.Background(), err))
+	})
+	t.Run("should not record unset values", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "ecosystem")
+
+		job := readConfig()
+
+		assert.NoError(t, job.envErr)
+	})
+	t.Run("success with profile", func(t *testing.T) {
+		t.Setenv("PROFILE", "production")
+		t.Setenv("PROFILES_FILE", "/config/profiles.yaml")
+
+		job := readConfig()
+
+		assert.Equal(t, "production", job.profileName)
+		assert.Equal(t, "/config/profiles.yaml", job.profilesFile)
+	})
+	t.Run("success with lease", func(t *testing.T) {
+		t.Setenv("POD_NAME", "ecosystem-core-default-config-abcde")
+		t.Setenv("LEASE_NAME", "my-lease")
+		t.Setenv("LEASE_WAIT_TIMEOUT_MINUTES", "3")
+		t.Setenv("LEASE_DURATION_SECONDS", "30")
+
+		job := readConfig()
+
+		assert.Equal(t, "ecosystem-core-default-config-abcde", job.leaseIdentity)
+		assert.Equal(t, "my-lease", job.leaseName)
+		assert.Equal(t, 3*time.Minute, job.leaseWaitTimeout)
+		assert.Equal(t, 30*time.Second, job.leaseDuration)
+	})
+	t.Run("success with lease defaults", func(t *testing.T) {
+		hostname, err := os.Hostname()
+		require.NoError(t, err)
+
+		job := readConfig()
+
+		assert.Equal(t, hostname, job.leaseIdentity)
+		assert.Equal(t, defaultLeaseName, job.leaseName)
+		assert.Equal(t, 10*time.Minute, job.leaseWaitTimeout)
+		assert.Equal(t, time.Minute, job.leaseDuration)
+	})
+	t.Run("success with timeouts", func(t *testing.T) {
+		t.Setenv("RUN_TIMEOUT_MINUTES", "45")
+		t.Setenv("GLOBAL_CONFIG_TIMEOUT_SECONDS", "10")
+		t.Setenv("DOGU_CONFIG_TIMEOUT_SECONDS", "20")
+		t.Setenv("CERTIFICATE_TIMEOUT_SECONDS", "5")
+
+		job := readConfig()
+
+		assert.Equal(t, 45*time.Minute, job.runTimeout)
+		assert.Equal(t, config.Timeouts{GlobalConfig: 10 * time.Second, DoguConfig: 20 * time.Second, Certificate: 5 * time.Second}, job.phaseTimeouts)
+	})
+	t.Run("success with retry policy", func(t *testing.T) {
+		t.Setenv("RETRY_MAX_ATTEMPTS", "8")
+		t.Setenv("RETRY_INITIAL_BACKOFF_MILLISECONDS", "50")
+		t.Setenv("RETRY_MAX_BACKOFF_SECONDS", "30")
+
+		job := readConfig()
+
+		assert.Equal(t, 8, job.retryPolicy.MaxAttempts)
+		assert.Equal(t, 50*time.Millisecond, job.retryPolicy.InitialBackoff)
+		assert.Equal(t, 30*time.Second, job.retryPolicy.MaxBackoff)
+	})
+	t.Run("success with metrics", func(t *testing.T) {
+		t.Setenv("METRICS_PUSHGATEWAY_URL", "http://pushgateway:9091")
+		t.Setenv("METRICS_LISTEN_ADDRESS", ":9090")
+
+		job := readConfig()
+
+		assert.Equal(t, "http://pushgateway:9091", job.metricsPushgatewayURL)
+		assert.Equal(t, ":9090", job.metricsListenAddress)
+	})
+	t.Run("success with log format", func(t *testing.T) {
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_ADD_SOURCE", "true")
+
+		job := readConfig()
+
+		assert.Equal(t, "json", job.logFormat)
+		assert.True(t, job.logAddSource)
+	})
+	t.Run("success with components", func(t *testing.T) {
+		assert.Empty(t, readConfig().components)
+		assert.Equal(t, 10*time.Minute, readConfig().componentsTimeout)
+
+		t.Setenv("WAIT_FOR_COMPONENTS", "k8s-dogu-operator, k8s-service-discovery,,")
+		t.Setenv("COMPONENTS_TIMEOUT_MINUTES", "3")
+
+		job := readConfig()
+
+		assert.Equal(t, []string{"k8s-dogu-operator", "k8s-service-discovery"}, job.components)
+		assert.Equal(t, 3*time.Minute, job.componentsTimeout)
+	})
+	t.Run("success with tracing endpoint", func(t *testing.T) {
+		t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://otel-collector:4318")
+
+		assert.Equal(t, "http://otel-collector:4318", readConfig().tracingEndpoint)
+	})
+	t.Run("success with extra defaults, proxy and dry run", func(t *testing.T) {
+		job := readConfig()
+		assert.Empty(t, job.extraDefaults)
+		assert.False(t, job.dryRun)
+
+		t.Setenv("EXTRA_DEFAULTS", `{"globalConfig":{"proxy/enabled":"true"}}`)
+		t.Setenv("PROXY", `{"server":"proxy.example.com","port":3128}`)
+		t.Setenv("DRY_RUN", "true")
+
+		job = readConfig()
+		assert.Equal(t, `{"globalConfig":{"proxy/enabled":"true"}}`, job.extraDefaults)
+		assert.True(t, job.dryRun)
+		assert.Equal(t, `{"server":"proxy.example.com","port":3128}`, job.proxy)
+	})
+	t.Run("success with blueprint", func(t *testing.T) {
+		assert.Empty(t, readConfig().blueprintName)
+
+		t.Setenv("BLUEPRINT_NAME", "blueprint")
+
+		assert.Equal(t, "blueprint", readConfig().blueprintName)
+	})
+	t.Run("success with termination message path", func(t *testing.T) {
+		assert.Equal(t, "/dev/termination-log", readConfig().terminationMessagePath)
+
+		t.Setenv("TERMINATION_MESSAGE_PATH", "/tmp/termination-log")
+
+		assert.Equal(t, "/tmp/termination-log", readConfig().terminationMessagePath)
+	})
+}
+
+func Test_jobConfig_validate(t *testing.T) {
+	validConfig := func() jobConfig {
+		return jobConfig{
+			namespace:        "ecosystem",
+			waitTimeout:      time.Minute,
+			runTimeout:       time.Minute,
+			leaseWaitTimeout: time.Minute,
+			leaseDuration:    time.Minute,
+			retryPolicy:      retry.DefaultPolicy(),
+		}
+	}
+
+	t.Run("should accept valid config", func(t *testing.T) {
+		require.NoError(t, validConfig().validate())
+	})
+
+	t.Run("should reject invalid config", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.namespace = ""
+		cfg.runTimeout = 0
+		cfg.phaseTimeouts.Certificate = -time.Second
+		cfg.retryPolicy.MaxAttempts = 0
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "NAMESPACE must be set")
+		assert.ErrorContains(t, err, "RUN_TIMEOUT_MINUTES must be positive")
+		assert.ErrorContains(t, err, "phase timeouts must not be negative")
+		assert.ErrorContains(t, err, "RETRY_MAX_ATTEMPTS must be at least 1")
+	})
+
+	t.Run("should reject invalid pushgateway url", func(t *testing.T) {
+		for _, pushgatewayURL := range []string{"pushgateway:9091", "ftp://pushgateway", "://"} {
+			cfg := validConfig()
+			cfg.metricsPushgatewayURL = pushgatewayURL
+
+			err := cfg.validate()
+
+			require.Error(t, err, pushgatewayURL)
+			assert.ErrorContains(t, err, "METRICS_PUSHGATEWAY_URL must be an http or https URL")
+		}
+	})
+
+	t.Run("should accept pushgateway url", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.metricsPushgatewayURL = "http://prometheus-pushgateway.monitoring.svc:9091"
+
+		require.NoError(t, cfg.validate())
+	})
+
+	t.Run("should reject components without timeout", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.components = []string{"k8s-dogu-operator"}
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "COMPONENTS_TIMEOUT_MINUTES must be positive")
+	})
+
+	t.Run("should reject invalid tracing endpoint", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.tracingEndpoint = "otel-collector:4318"
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "OTEL_EXPORTER_OTLP_ENDPOINT must be an http or https URL")
+	})
+
+	t.Run("should accept tracing endpoint", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.tracingEndpoint = "http://otel-collector.monitoring.svc:4318"
+
+		require.NoError(t, cfg.validate())
+	})
+
+	t.Run("should reject lop-idp without initial domain and fqdn", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.useLopIdp = true
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "INITIAL_DOMAIN must be set if the LOP IdP is used")
+		assert.ErrorContains(t, err, "INITIAL_FQDN must be set if the LOP IdP is used")
+	})
+
+	t.Run("should reject invalid external ldap", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.externalLdap = `{"host": "dc.example.com"}`
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "EXTERNAL_LDAP: invalid external LDAP configuration: baseDn must be set")
+	})
+
+	t.Run("should reject invalid proxy", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.proxy = `{"server": "http://proxy.example.com", "port": 3128}`
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "PROXY: invalid proxy configuration: server must be a hostname or an IP address")
+	})
+
+	t.Run("should reject invalid extra defaults", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.extraDefaults = `{"doguConfig": {"official/cas": {"a": "b"}}}`
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, `EXTRA_DEFAULTS: invalid extra defaults: dogu "official/cas" must be a simple dogu name`)
+	})
+
+	t.Run("should reject invalid smtp relay", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.smtpRelay = `{"host": "mail.example.com", "tls": "ssl"}`
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, `SMTP_RELAY: invalid SMTP relay configuration: tls "ssl" must be one of`)
+	})
+
+	t.Run("should reject non-positive initial admin ttl", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.initialAdminSecret = "ecosystem-core-initial-admin"
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "INITIAL_ADMIN_TTL_HOURS must be positive")
+	})
+
+	t.Run("should reject external ldap with lop-idp", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.useLopIdp = true
+		cfg.initialDomain = "example.com"
+		cfg.initialFQDN = "ces.example.com"
+		cfg.externalLdap = `{"host": "dc.example.com", "baseDn": "DC=example,DC=com", "bindSecret": {"name": "ldap-bind"}}`
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "EXTERNAL_LDAP must not be set if the LOP IdP is used")
+	})
+
+	t.Run("should accept external ldap", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.externalLdap = `{"host": "dc.example.com", "baseDn": "DC=example,DC=com", "bindSecret": {"name": "ldap-bind"}}`
+
+		require.NoError(t, cfg.validate())
+	})
+
+	t.Run("should reject an invalid internal ip", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.initialInternalIP = "ces.example.com"
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `INITIAL_INTERNAL_IP "ces.example.com" must be an IP address`)
+	})
+
+	t.Run("should reject invalid lop-idp config", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.useLopIdp = true
+		cfg.initialDomain = "example.com"
+		cfg.initialFQDN = "ces.example.com"
+		cfg.lopIdp = `{"discoveryUri": "http://idp.example.com"}`
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "LOP_IDP: invalid LOP IdP configuration")
+	})
+
+	t.Run("should accept lop-idp with initial domain and fqdn", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.useLopIdp = true
+		cfg.initialDomain = "example.com"
+		cfg.initialFQDN = "ces.example.com"
+
+		require.NoError(t, cfg.validate())
+	})
+}
+
+func Test_jobConfig_applierOptions(t *testing.T) {
+	t.Run("should parse the configured features", func(t *testing.T) {
+		cfg := jobConfig{
+			initialDomain: "example.com",
+			initialFQDN:   "ces.example.com",
+			useLopIdp:     true,
+			lopIdp:        `{"clientSecret": {"name": "lop-idp-cas"}}`,
+			smtpRelay:     `{"host": "mail.example.com"}`,
+		}
+
+		opts := cfg.applierOptions()
+
+		assert.Equal(t, "example.com", opts.InitialDomain)
+		assert.Equal(t, "ces.example.com", opts.InitialFQDN)
+		require.NotNil(t, opts.LopIdp)
+		assert.Equal(t, "lop-idp-cas", opts.LopIdp.ClientSecret.Name)
+		require.NotNil(t, opts.SMTPRelay)
+		assert.Equal(t, "mail.example.com", opts.SMTPRelay.Host)
+		assert.Nil(t, opts.ExternalLdap)
+		assert.Nil(t, opts.Proxy)
+		assert.Nil(t, opts.ExtraDefaults)
+		assert.Nil(t, opts.Blueprint)
+		assert.Nil(t, opts.InitialAdmin)
+	})
+
+	t.Run("should ignore the lop-idp config if the lop-idp is not used", func(t *testing.T) {
+		cfg := jobConfig{lopIdp: `{"clientSecret": {"name": "lop-idp-cas"}}`}
+
+		assert.Nil(t, cfg.applierOptions().LopIdp)
+	})
+}
+
+func Test_writePlan(t *testing.T) {
+	cfg := jobConfig{
+		initialFQDN:   "ces.example.com",
+		extraDefaults: `{"globalConfig": {"fqdn": "chart.example.com", "proxy/enabled": "true"}}`,
+		blueprintName: "blueprint",
+	}
+	var out strings.Builder
+
+	err := writePlan(cfg, &out)
+
+	require.NoError(t, err)
+	assert.Regexp(t, `(?m)^global +- +fqdn +"ces.example.com" +initial$`, out.String())
+	assert.Regexp(t, `(?m)^global +- +proxy/enabled +"true" +chart$`, out.String())
+	assert.Regexp(t, `(?m)^dogu +postfix +relayhost +"n/a" +built-in$`, out.String())
+	assert.NotContains(t, out.String(), "admin_password")
+}
+
+func Test_classifyError(t *testing.T) {
+	interruptedCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		signalCtx context.Context
+		err       error
+		wantClass errorClass
+		wantCode  int
+	}{
+		{"interrupted", interruptedCtx, fmt.Errorf("failed: %w", context.Canceled), errorClassInterrupted, exitCodeInterrupted},
+		{"timeout", context.Background(), fmt.Errorf("failed: %w", context.DeadlineExceeded), errorClassTimeout, exitCodeTimeout},
+		{"validation", context.Background(), fmt.Errorf("%w: NAMESPACE must be set", errInvalidJobConfig), errorClassValidation, exitCodeValidation},
+		{"self-signed certificate", context.Background(), fmt.Errorf("failed: %w", config.ErrSelfSignedCertificate), errorClassValidation, exitCodeValidation},
+		{"preflight", context.Background(), fmt.Errorf("%w: Secret ces-container-registries does not exist", preflight.ErrFailed), errorClassValidation, exitCodeValidation},
+		{"api status", context.Background(), fmt.Errorf("failed: %w", apierrors.NewForbidden(schema.GroupResource{Resource: "configmaps"}, "global-config", assert.AnError)), errorClassAPI, exitCodeAPIFailure},
+		{"registry", context.Background(), fmt.Errorf("failed: %w", cesLibErr.NewGenericError(assert.AnError)), errorClassAPI, exitCodeAPIFailure},
+		{"lease lost", context.Background(), fmt.Errorf("%w: %w", lease.ErrLost, context.Canceled), errorClassLeaseLost, exitCodeLeaseLost},
+		{"other", context.Background(), assert.AnError, errorClassUnknown, exitCodeFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			class := classifyError(tt.signalCtx, tt.err)
+
+			assert.Equal(t, tt.wantClass, class)
+			assert.Equal(t, tt.wantCode, exitCodes[class])
+		})
+	}
+}
+
+func Test_jobConfig_applyProfile(t *testing.T) {
+	t.Run("should keep the config without profile", func(t *testing.T) {
+		cfg := jobConfig{enableFqdnApply: true}
+
+		require.NoError(t, cfg.applyProfile())
+
+		assert.True(t, cfg.enableFqdnApply)
+		assert.Equal(t, config.Profile{}, cfg.profile)
+	})
+
+	t.Run("should apply the fqdn strategy of the profile", func(t *testing.T) {
+		cfg := jobConfig{profileName: "development"}
+
+		require.NoError(t, cfg.applyProfile())
+
+		assert.True(t, cfg.enableFqdnApply)
+		assert.Equal(t, "development", cfg.profile.Name)
+	})
+
+	t.Run("should fail for unknown profile", func(t *testing.T) {
+		cfg := jobConfig{profileName: "staging"}
+
+		err := cfg.applyProfile()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, `PROFILE: invalid profile: unknown profile "staging"`)
+	})
+
+	t.Run("should fail if the requirements of the profile are not met", func(t *testing.T) {
+		cfg := jobConfig{profileName: "production", initialDomain: "example.com"}
+
+		err := cfg.applyProfile()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "INITIAL_FQDN must be set for profile production")
+	})
+
+	t.Run("should require the internal ip for the airgapped profile", func(t *testing.T) {
+		cfg := jobConfig{profileName: "airgapped", initialDomain: "example.com", initialFQDN: "ces.example.com"}
+
+		err := cfg.applyProfile()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "INITIAL_INTERNAL_IP must be set for profile airgapped")
+
+		cfg.initialInternalIP = "10.0.0.10"
+		require.NoError(t, cfg.applyProfile())
+		assert.Equal(t, "10.0.0.10", cfg.applierOptions().InitialInternalIP)
+	})
+}
+
+func Test_run(t *testing.T) {
+	t.Run("should fail on invalid config", func(t *testing.T) {
+		err := run(context.Background(), jobConfig{}, nil)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+	})
+}
+
+func Test_waitForComponents(t *testing.T) {
+	t.Run("should not wait without components", func(t *testing.T) {
+		summary := report.NewSummary()
+
+		err := waitForComponents(context.Background(), jobConfig{}, nil, summary)
+
+		require.NoError(t, err)
+		assert.Equal(t, report.PhaseSetup, summary.Phase())
 	})
 }
 
 func Test_configureLogger(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
-		configureLogger("debug")
+		configureLogger("debug", "json", false)
 		h := slog.Default().Handler()
 		ctx := context.Background()
 
@@ -128,7 +630,7 @@ func Test_configureLogger(t *testing.T) {
 		}
 	})
 	t.Run("fallback to default", func(t *testing.T) {
-		configureLogger("invalid")
+		configureLogger("invalid", "invalid", false)
 		h := slog.Default().Handler()
 		ctx := context.Background()
 
@@ -142,3 +644,9 @@ func Test_configureLogger(t *testing.T) {
 		}
 	})
 }
+
+func Test_jobConfig_initialAdmin(t *testing.T) {
+	assert.Nil(t, jobConfig{initialAdminTTL: time.Hour}.initialAdmin())
+	assert.Equal(t, &config.InitialAdmin{SecretName: "initial-admin", TTL: time.Hour},
+		jobConfig{initialAdminSecret: "initial-admin", initialAdminTTL: time.Hour}.initialAdmin())
+}
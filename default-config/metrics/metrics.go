@@ -0,0 +1,163 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+const (
+	namespace = "ecosystem_core_default_config"
+	// JobName is the job label of the metrics pushed to the Pushgateway.
+	JobName = "ecosystem-core-default-config"
+
+	shutdownTimeout = 5 * time.Second
+)
+
+var (
+	runDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "run_duration_seconds"),
+		"Duration of the default-config run.", nil, nil)
+	runSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "run_success"),
+		"Whether the finished default-config run succeeded (1) or failed (0).", nil, nil)
+	lastSuccessDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "last_success_timestamp_seconds"),
+		"Unix time of the last successful default-config run.", nil, nil)
+	phaseDurationDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "phase_duration_seconds"),
+		"Duration of the phases of the default-config run.", []string{"phase"}, nil)
+	keysDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "keys"),
+		"Number of default config keys that have been created or skipped because they already existed.", []string{"repo", "result"}, nil)
+	fqdnWaitDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "fqdn_wait_seconds"),
+		"Time waited for the external address of the load balancer.", nil, nil)
+	retriesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "", "retries"),
+		"Number of retried Kubernetes API calls.", nil, nil)
+)
+
+// Metrics exposes the progress and outcome of a default-config run in the Prometheus format.
+// The values are read from the run summary whenever the metrics are gathered.
+type Metrics struct {
+	registry *prometheus.Registry
+	summary  *report.Summary
+	now      func() time.Time
+
+	mu         sync.Mutex
+	finished   bool
+	runErr     error
+	finishedAt time.Time
+}
+
+func New(summary *report.Summary) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		summary:  summary,
+		now:      time.Now,
+	}
+	m.registry.MustRegister(m)
+
+	return m
+}
+
+// Finish records the result of the run. runErr is nil for a successful run.
+func (m *Metrics) Finish(runErr error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.finished = true
+	m.runErr = runErr
+	m.finishedAt = m.now()
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- runDurationDesc
+	ch <- runSuccessDesc
+	ch <- lastSuccessDesc
+	ch <- phaseDurationDesc
+	ch <- keysDesc
+	ch <- fqdnWaitDesc
+	ch <- retriesDesc
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(runDurationDesc, prometheus.GaugeValue, m.summary.Duration().Seconds())
+
+	for phase, duration := range m.summary.PhaseDurations() {
+		ch <- prometheus.MustNewConstMetric(phaseDurationDesc, prometheus.GaugeValue, duration.Seconds(), string(phase))
+	}
+
+	for _, repo := range m.summary.Repos() {
+		counts := m.summary.Keys(repo)
+		ch <- prometheus.MustNewConstMetric(keysDesc, prometheus.GaugeValue, float64(counts.Created), repo, "created")
+		ch <- prometheus.MustNewConstMetric(keysDesc, prometheus.GaugeValue, float64(counts.Skipped), repo, "skipped")
+	}
+
+	ch <- prometheus.MustNewConstMetric(fqdnWaitDesc, prometheus.GaugeValue, m.summary.FQDNWait().Seconds())
+	ch <- prometheus.MustNewConstMetric(retriesDesc, prometheus.GaugeValue, float64(m.summary.Retries()))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.finished {
+		return
+	}
+
+	if m.runErr != nil {
+		// the last success timestamp is left out, so that a push keeps the one of the last successful run
+		ch <- prometheus.MustNewConstMetric(runSuccessDesc, prometheus.GaugeValue, 0)
+		return
+	}
+
+	ch <- prometheus.MustNewConstMetric(runSuccessDesc, prometheus.GaugeValue, 1)
+	ch <- prometheus.MustNewConstMetric(lastSuccessDesc, prometheus.GaugeValue, float64(m.finishedAt.Unix()))
+}
+
+// Push adds the metrics to the group of the given namespace in the Pushgateway.
+// Metrics of the group that are not gathered in this run are kept.
+func (m *Metrics) Push(ctx context.Context, pushgatewayURL string, k8sNamespace string) error {
+	err := push.New(pushgatewayURL, JobName).
+		Gatherer(m.registry).
+		Grouping("namespace", k8sNamespace).
+		AddContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to push metrics to %s: %w", pushgatewayURL, err)
+	}
+
+	return nil
+}
+
+// Handler serves the metrics for scraping.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// Serve exposes the metrics on /metrics of the given address until ctx is done.
+func (m *Metrics) Serve(ctx context.Context, address string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m.Handler())
+	server := &http.Server{Addr: address, Handler: mux, ReadHeaderTimeout: shutdownTimeout}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("failed to serve metrics on %s: %w", address, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/report"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testSummary() *report.Summary {
+	summary := report.NewSummary()
+	summary.EnterPhase(report.PhaseGlobalConfig)
+	summary.AddKeys(report.RepoGlobal, report.KeyCounts{Created: 10, Skipped: 2})
+	summary.EnterPhase(report.PhaseDoguConfig)
+	summary.AddKeys(report.RepoDogu, report.KeyCounts{Created: 3})
+	summary.AddRetry()
+	summary.SetFQDNWait(1500 * time.Millisecond)
+	return summary
+}
+
+func newTestMetrics(summary *report.Summary) *Metrics {
+	m := New(summary)
+	m.now = func() time.Time { return time.Unix(1792411200, 0) }
+	return m
+}
+
+func TestMetrics_Collect(t *testing.T) {
+	t.Run("should expose progress of running job", func(t *testing.T) {
+		m := newTestMetrics(testSummary())
+
+		expected := `
+# HELP ecosystem_core_default_config_fqdn_wait_seconds Time waited for the external address of the load balancer.
+# TYPE ecosystem_core_default_config_fqdn_wait_seconds gauge
+ecosystem_core_default_config_fqdn_wait_seconds 1.5
+# HELP ecosystem_core_default_config_keys Number of default config keys that have been created or skipped because they already existed.
+# TYPE ecosystem_core_default_config_keys gauge
+ecosystem_core_default_config_keys{repo="dogu",result="created"} 3
+ecosystem_core_default_config_keys{repo="dogu",result="skipped"} 0
+ecosystem_core_default_config_keys{repo="global",result="created"} 10
+ecosystem_core_default_config_keys{repo="global",result="skipped"} 2
+# HELP ecosystem_core_default_config_retries Number of retried Kubernetes API calls.
+# TYPE ecosystem_core_default_config_retries gauge
+ecosystem_core_default_config_retries 1
+`
+		err := testutil.CollectAndCompare(m, strings.NewReader(expected),
+			"ecosystem_core_default_config_fqdn_wait_seconds",
+			"ecosystem_core_default_config_keys",
+			"ecosystem_core_default_config_retries",
+			"ecosystem_core_default_config_run_success",
+			"ecosystem_core_default_config_last_success_timestamp_seconds",
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, testutil.CollectAndCount(m, "ecosystem_core_default_config_phase_duration_seconds"))
+	})
+
+	t.Run("should expose success and timestamp of successful run", func(t *testing.T) {
+		m := newTestMetrics(testSummary())
+		m.Finish(nil)
+
+		expected := `
+# HELP ecosystem_core_default_config_last_success_timestamp_seconds Unix time of the last successful default-config run.
+# TYPE ecosystem_core_default_config_last_success_timestamp_seconds gauge
+ecosystem_core_default_config_last_success_timestamp_seconds 1.7924112e+09
+# HELP ecosystem_core_default_config_run_success Whether the finished default-config run succeeded (1) or failed (0).
+# TYPE ecosystem_core_default_config_run_success gauge
+ecosystem_core_default_config_run_success 1
+`
+		err := testutil.CollectAndCompare(m, strings.NewReader(expected),
+			"ecosystem_core_default_config_run_success",
+			"ecosystem_core_default_config_last_success_timestamp_seconds",
+		)
+		require.NoError(t, err)
+	})
+
+	t.Run("should not expose success timestamp of failed run", func(t *testing.T) {
+		m := newTestMetrics(testSummary())
+		m.Finish(assert.AnError)
+
+		expected := `
+# HELP ecosystem_core_default_config_run_success Whether the finished default-config run succeeded (1) or failed (0).
+# TYPE ecosystem_core_default_config_run_success gauge
+ecosystem_core_default_config_run_success 0
+`
+		err := testutil.CollectAndCompare(m, strings.NewReader(expected),
+			"ecosystem_core_default_config_run_success",
+			"ecosystem_core_default_config_last_success_timestamp_seconds",
+		)
+		require.NoError(t, err)
+	})
+}
+
+func TestMetrics_Push(t *testing.T) {
+	t.Run("should add metrics to group of namespace", func(t *testing.T) {
+		var method, path, body string
+		pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			path = r.URL.Path
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer pushgateway.Close()
+
+		m := newTestMetrics(testSummary())
+		m.Finish(nil)
+
+		err := m.Push(context.Background(), pushgateway.URL, "ecosystem")
+
+		require.NoError(t, err)
+		// POST replaces only the pushed metrics, so that the last success timestamp survives a failed run
+		assert.Equal(t, http.MethodPost, method)
+		assert.Equal(t, "/metrics/job/ecosystem-core-default-config/namespace/ecosystem", path)
+		assert.Contains(t, body, "ecosystem_core_default_config_last_success_timestamp_seconds")
+	})
+
+	t.Run("should fail if pushgateway rejects metrics", func(t *testing.T) {
+		pushgateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer pushgateway.Close()
+
+		err := newTestMetrics(testSummary()).Push(context.Background(), pushgateway.URL, "ecosystem")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to push metrics to "+pushgateway.URL)
+	})
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	t.Run("should serve metrics", func(t *testing.T) {
+		server := httptest.NewServer(newTestMetrics(testSummary()).Handler())
+		defer server.Close()
+
+		resp, err := http.Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Contains(t, string(body), `ecosystem_core_default_config_keys{repo="global",result="created"} 10`)
+	})
+}
+
+func TestMetrics_Serve(t *testing.T) {
+	t.Run("should stop serving when context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error)
+
+		go func() {
+			done <- newTestMetrics(testSummary()).Serve(ctx, "127.0.0.1:0")
+		}()
+		cancel()
+
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("metrics server did not stop")
+		}
+	})
+
+	t.Run("should fail on invalid address", func(t *testing.T) {
+		err := newTestMetrics(testSummary()).Serve(context.Background(), "invalid-address")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to serve metrics on invalid-address")
+	})
+}
@@ -0,0 +1,92 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package main
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockAdminPasswordRotator is an autogenerated mock type for the adminPasswordRotator type
+type mockAdminPasswordRotator struct {
+	mock.Mock
+}
+
+type mockAdminPasswordRotator_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockAdminPasswordRotator) EXPECT() *mockAdminPasswordRotator_Expecter {
+	return &mockAdminPasswordRotator_Expecter{mock: &_m.Mock}
+}
+
+// Rotate provides a mock function with given fields: ctx
+func (_m *mockAdminPasswordRotator) Rotate(ctx context.Context) (string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rotate")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockAdminPasswordRotator_Rotate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Rotate'
+type mockAdminPasswordRotator_Rotate_Call struct {
+	*mock.Call
+}
+
+// Rotate is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *mockAdminPasswordRotator_Expecter) Rotate(ctx interface{}) *mockAdminPasswordRotator_Rotate_Call {
+	return &mockAdminPasswordRotator_Rotate_Call{Call: _e.mock.On("Rotate", ctx)}
+}
+
+func (_c *mockAdminPasswordRotator_Rotate_Call) Run(run func(ctx context.Context)) *mockAdminPasswordRotator_Rotate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *mockAdminPasswordRotator_Rotate_Call) Return(_a0 string, _a1 error) *mockAdminPasswordRotator_Rotate_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockAdminPasswordRotator_Rotate_Call) RunAndReturn(run func(context.Context) (string, error)) *mockAdminPasswordRotator_Rotate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockAdminPasswordRotator creates a new instance of mockAdminPasswordRotator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockAdminPasswordRotator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockAdminPasswordRotator {
+	mock := &mockAdminPasswordRotator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
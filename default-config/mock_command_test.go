@@ -0,0 +1,83 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package main
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// mockCommand is an autogenerated mock type for the command type
+type mockCommand struct {
+	mock.Mock
+}
+
+type mockCommand_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockCommand) EXPECT() *mockCommand_Expecter {
+	return &mockCommand_Expecter{mock: &_m.Mock}
+}
+
+// Execute provides a mock function with given fields: signalCtx, stopSignals
+func (_m *mockCommand) Execute(signalCtx context.Context, stopSignals context.CancelFunc) int {
+	ret := _m.Called(signalCtx, stopSignals)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Execute")
+	}
+
+	var r0 int
+	if rf, ok := ret.Get(0).(func(context.Context, context.CancelFunc) int); ok {
+		r0 = rf(signalCtx, stopSignals)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	return r0
+}
+
+// mockCommand_Execute_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Execute'
+type mockCommand_Execute_Call struct {
+	*mock.Call
+}
+
+// Execute is a helper method to define mock.On call
+//   - signalCtx context.Context
+//   - stopSignals context.CancelFunc
+func (_e *mockCommand_Expecter) Execute(signalCtx interface{}, stopSignals interface{}) *mockCommand_Execute_Call {
+	return &mockCommand_Execute_Call{Call: _e.mock.On("Execute", signalCtx, stopSignals)}
+}
+
+func (_c *mockCommand_Execute_Call) Run(run func(signalCtx context.Context, stopSignals context.CancelFunc)) *mockCommand_Execute_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(context.CancelFunc))
+	})
+	return _c
+}
+
+func (_c *mockCommand_Execute_Call) Return(_a0 int) *mockCommand_Execute_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *mockCommand_Execute_Call) RunAndReturn(run func(context.Context, context.CancelFunc) int) *mockCommand_Execute_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockCommand creates a new instance of mockCommand. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockCommand(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockCommand {
+	mock := &mockCommand{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
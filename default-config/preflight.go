@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/compatibility"
+	"github.com/cloudogu/ecosystem-core/default-config/preflight"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+const defaultPreflightTimeoutSeconds = 60
+
+// preflightConfig configures the preflight command, which verifies the preconditions before the ecosystem is installed.
+type preflightConfig struct {
+	namespace    string
+	logLevel     string
+	logFormat    string
+	logAddSource bool
+	timeout      time.Duration
+	// verifyRegistries logs in to the registries with the credentials of the Secrets.
+	verifyRegistries bool
+	// helmChart is looked up in the Helm registry to verify its credentials, e.g. "k8s/k8s-dogu-operator:3.27.0".
+	helmChart string
+	// componentValues are the chart values that select the components as JSON. If set, the versions of the
+	// components are checked against the compatibility matrix.
+	componentValues string
+	chartVersion    string
+
+	// envErr contains the environment variables with malformed values.
+	envErr error
+}
+
+func readPreflightConfig() preflightConfig {
+	env := &envReader{}
+	return preflightConfig{
+		namespace:        os.Getenv("NAMESPACE"),
+		logLevel:         os.Getenv("LOG_LEVEL"),
+		logFormat:        os.Getenv("LOG_FORMAT"),
+		logAddSource:     env.readBool("LOG_ADD_SOURCE", false),
+		timeout:          time.Duration(env.readInt("PREFLIGHT_TIMEOUT_SECONDS", defaultPreflightTimeoutSeconds)) * time.Second,
+		verifyRegistries: env.readBool("PREFLIGHT_VERIFY_REGISTRIES", false),
+		helmChart:        os.Getenv("PREFLIGHT_HELM_CHART"),
+		componentValues:  os.Getenv("PREFLIGHT_COMPONENT_VALUES"),
+		chartVersion:     os.Getenv("PREFLIGHT_CHART_VERSION"),
+		envErr:           env.err(),
+	}
+}
+
+func (c preflightConfig) validate() error {
+	var errs []error
+	if c.envErr != nil {
+		errs = append(errs, c.envErr)
+	}
+	if c.namespace == "" {
+		errs = append(errs, errors.New("NAMESPACE must be set"))
+	}
+	if c.timeout <= 0 {
+		errs = append(errs, errors.New("PREFLIGHT_TIMEOUT_SECONDS must be positive"))
+	}
+	if _, err := c.components(); err != nil {
+		errs = append(errs, fmt.Errorf("PREFLIGHT_COMPONENT_VALUES: %w", err))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", errInvalidJobConfig, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+// components returns the versions of the components the chart installs, or nil if the component values are not set.
+func (c preflightConfig) components() (map[string]string, error) {
+	if c.componentValues == "" {
+		return nil, nil
+	}
+
+	values, err := compatibility.ParseValues([]byte(c.componentValues))
+	if err != nil {
+		return nil, err
+	}
+
+	return values.EffectiveComponents(c.chartVersion), nil
+}
+
+func preflightCommand(signalCtx context.Context, stopSignals context.CancelFunc) int {
+	err := runPreflight(signalCtx, readPreflightConfig())
+	stopSignals()
+
+	if err != nil {
+		class := classifyError(signalCtx, err)
+		code := exitCodes[class]
+		slog.Error("preflight failed", "err", err, "errorClass", class, "exitCode", code)
+		return code
+	}
+
+	slog.Info("preflight completed")
+	return 0
+}
+
+func runPreflight(ctx context.Context, cfg preflightConfig) error {
+	configureLogger(cfg.logLevel, cfg.logFormat, cfg.logAddSource)
+
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cfg.timeout)
+	defer cancel()
+
+	clusterConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return fmt.Errorf("failed to read kube config: %w", err)
+	}
+
+	clientSet, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	components, err := cfg.components()
+	if err != nil {
+		return err
+	}
+
+	slog.Info("checking preconditions...", "namespace", cfg.namespace, "verifyRegistries", cfg.verifyRegistries, "components", len(components))
+	checker := preflight.NewChecker(
+		clientSet.Discovery(),
+		clientSet.CoreV1().Secrets(cfg.namespace),
+		clientSet.CoreV1().ConfigMaps(cfg.namespace),
+		preflight.Options{VerifyRegistries: cfg.verifyRegistries, HelmChart: cfg.helmChart, Components: components},
+	)
+
+	return checker.Run(ctx)
+}
@@ -0,0 +1,93 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package preflight
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// mockDiscoveryClient is an autogenerated mock type for the discoveryClient type
+type mockDiscoveryClient struct {
+	mock.Mock
+}
+
+type mockDiscoveryClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockDiscoveryClient) EXPECT() *mockDiscoveryClient_Expecter {
+	return &mockDiscoveryClient_Expecter{mock: &_m.Mock}
+}
+
+// ServerResourcesForGroupVersion provides a mock function with given fields: groupVersion
+func (_m *mockDiscoveryClient) ServerResourcesForGroupVersion(groupVersion string) (*v1.APIResourceList, error) {
+	ret := _m.Called(groupVersion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ServerResourcesForGroupVersion")
+	}
+
+	var r0 *v1.APIResourceList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) (*v1.APIResourceList, error)); ok {
+		return rf(groupVersion)
+	}
+	if rf, ok := ret.Get(0).(func(string) *v1.APIResourceList); ok {
+		r0 = rf(groupVersion)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.APIResourceList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(groupVersion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockDiscoveryClient_ServerResourcesForGroupVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ServerResourcesForGroupVersion'
+type mockDiscoveryClient_ServerResourcesForGroupVersion_Call struct {
+	*mock.Call
+}
+
+// ServerResourcesForGroupVersion is a helper method to define mock.On call
+//   - groupVersion string
+func (_e *mockDiscoveryClient_Expecter) ServerResourcesForGroupVersion(groupVersion interface{}) *mockDiscoveryClient_ServerResourcesForGroupVersion_Call {
+	return &mockDiscoveryClient_ServerResourcesForGroupVersion_Call{Call: _e.mock.On("ServerResourcesForGroupVersion", groupVersion)}
+}
+
+func (_c *mockDiscoveryClient_ServerResourcesForGroupVersion_Call) Run(run func(groupVersion string)) *mockDiscoveryClient_ServerResourcesForGroupVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *mockDiscoveryClient_ServerResourcesForGroupVersion_Call) Return(_a0 *v1.APIResourceList, _a1 error) *mockDiscoveryClient_ServerResourcesForGroupVersion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockDiscoveryClient_ServerResourcesForGroupVersion_Call) RunAndReturn(run func(string) (*v1.APIResourceList, error)) *mockDiscoveryClient_ServerResourcesForGroupVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockDiscoveryClient creates a new instance of mockDiscoveryClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockDiscoveryClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockDiscoveryClient {
+	mock := &mockDiscoveryClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,92 @@
+package preflight
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// dockerConfig is the format of .dockerconfigjson and the config.json of Helm registries.
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Auth     string `json:"auth"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// parseDockerConfig parses the config and verifies that it contains credentials for at least one registry.
+// Every registry needs either a base64 encoded "username:password" in auth or a username and a password.
+func parseDockerConfig(raw []byte) (dockerConfig, error) {
+	var config dockerConfig
+	if err := json.Unmarshal(raw, &config); err != nil {
+		return dockerConfig{}, fmt.Errorf("failed to parse json: %w", err)
+	}
+
+	if len(config.Auths) == 0 {
+		return dockerConfig{}, errors.New("no registry in auths")
+	}
+
+	for registry, auth := range config.Auths {
+		if _, err := auth.credentials(); err != nil {
+			return dockerConfig{}, fmt.Errorf("registry %s: %w", registry, err)
+		}
+	}
+
+	return config, nil
+}
+
+func validateDockerConfig(raw []byte) error {
+	_, err := parseDockerConfig(raw)
+	return err
+}
+
+func (a dockerAuth) credentials() (credentials, error) {
+	if a.Auth == "" {
+		if a.Username == "" || a.Password == "" {
+			return credentials{}, errors.New("auth or username and password must be set")
+		}
+		return credentials{username: a.Username, password: a.Password}, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(a.Auth)
+	if err != nil {
+		return credentials{}, fmt.Errorf("auth is not base64 encoded: %w", err)
+	}
+
+	username, password, found := strings.Cut(string(decoded), ":")
+	if !found || username == "" || password == "" {
+		return credentials{}, errors.New(`auth must be the base64 encoded "username:password"`)
+	}
+
+	return credentials{username: username, password: password}, nil
+}
+
+func validateHelmRepository(data map[string]string) error {
+	if strings.Contains(data[helmRepositoryEndpoint], "://") {
+		return fmt.Errorf("%s must be a host without scheme, e.g. registry.cloudogu.com", helmRepositoryEndpoint)
+	}
+
+	for _, key := range []string{helmRepositoryPlainHTTP, helmRepositoryInsecure} {
+		value, ok := data[key]
+		if !ok {
+			continue
+		}
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be true or false", key)
+		}
+	}
+
+	return nil
+}
+
+func isHTTPURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
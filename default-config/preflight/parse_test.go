@@ -0,0 +1,41 @@
+package preflight
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_validateDockerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"auth", `{"auths":{"registry.cloudogu.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`, ""},
+		{"username and password", `{"auths":{"registry.cloudogu.com":{"username":"user","password":"password"}}}`, ""},
+		{"invalid json", `{"auths":`, "failed to parse json"},
+		{"no auths", `{"auths":{}}`, "no registry in auths"},
+		{"no credentials", `{"auths":{"registry.cloudogu.com":{"username":"user"}}}`, "registry registry.cloudogu.com: auth or username and password must be set"},
+		{"auth not base64", `{"auths":{"registry.cloudogu.com":{"auth":"user:password"}}}`, "auth is not base64 encoded"},
+		{"auth without password", `{"auths":{"registry.cloudogu.com":{"auth":"dXNlcg=="}}}`, `auth must be the base64 encoded "username:password"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateDockerConfig([]byte(tt.config))
+
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateHelmRepository(t *testing.T) {
+	assert.NoError(t, validateHelmRepository(map[string]string{"endpoint": "registry.cloudogu.com", "plainHttp": "false", "insecureTls": "true"}))
+	assert.ErrorContains(t, validateHelmRepository(map[string]string{"endpoint": "https://registry.cloudogu.com"}), "endpoint must be a host without scheme")
+	assert.ErrorContains(t, validateHelmRepository(map[string]string{"endpoint": "registry.cloudogu.com", "plainHttp": "no"}), "plainHttp must be true or false")
+	assert.ErrorContains(t, validateHelmRepository(map[string]string{"endpoint": "registry.cloudogu.com", "insecureTls": "1x"}), "insecureTls must be true or false")
+}
@@ -0,0 +1,260 @@
+package preflight
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/cloudogu/ecosystem-core/default-config/compatibility"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrFailed is returned if at least one check failed.
+var ErrFailed = errors.New("preflight checks failed")
+
+// Names of the resources the ecosystem requires before it is installed.
+const (
+	HelmRegistrySecret       = "component-operator-helm-registry"
+	ContainerRegistrySecret  = "ces-container-registries"
+	DoguRegistrySecret       = "k8s-dogu-operator-dogu-registry"
+	HelmRepositoryConfigMap  = "component-operator-helm-repository"
+	componentGroupVersion    = "k8s.cloudogu.com/v1"
+	componentResourceName    = "components"
+	helmRegistryConfigKey    = "config.json"
+	doguRegistryEndpointKey  = "endpoint"
+	doguRegistryUsernameKey  = "username"
+	doguRegistryPasswordKey  = "password"
+	doguRegistryURLSchemaKey = "urlschema"
+	helmRepositoryEndpoint   = "endpoint"
+	helmRepositorySchema     = "schema"
+	helmRepositoryPlainHTTP  = "plainHttp"
+	helmRepositoryInsecure   = "insecureTls"
+)
+
+type discoveryClient interface {
+	ServerResourcesForGroupVersion(groupVersion string) (*metav1.APIResourceList, error)
+}
+
+type secretClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error)
+}
+
+type configMapClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error)
+}
+
+// check verifies a single precondition. It returns nil if the precondition is met.
+// A check is skipped if one of the checks it depends on did not pass.
+type check struct {
+	name      string
+	run       func(ctx context.Context) error
+	dependsOn []string
+}
+
+// Options configure the optional checks.
+type Options struct {
+	// VerifyRegistries performs an authenticated request against each registry with the credentials of the
+	// Secrets, so that wrong credentials are detected before components or dogus are installed.
+	VerifyRegistries bool
+	// HelmChart is the chart whose manifest is looked up in the Helm registry, e.g. "k8s/k8s-dogu-operator:3.27.0".
+	// If empty, only the authentication at the Helm registry is verified.
+	HelmChart string
+	// Components maps the components the chart installs to their versions. If set, their versions are checked
+	// against the compatibility matrix.
+	Components map[string]string
+}
+
+// Checker verifies the preconditions of the ecosystem before it is installed, e.g. the credentials of the registries.
+// Unlike the Helm lookup in the chart, it also works in Argo CD and validates the contents of the resources.
+type Checker struct {
+	discovery  discoveryClient
+	secrets    secretClient
+	configMaps configMapClient
+	opts       Options
+	registries *registryVerifier
+}
+
+func NewChecker(discovery discoveryClient, secrets secretClient, configMaps configMapClient, opts Options) *Checker {
+	return &Checker{discovery: discovery, secrets: secrets, configMaps: configMaps, opts: opts, registries: newRegistryVerifier()}
+}
+
+// Run executes all checks and returns an error wrapping ErrFailed that describes each failed check.
+// The result of each check is logged in a report at the end.
+func (c *Checker) Run(ctx context.Context) error {
+	var errs []error
+	var passed, failed, skipped []string
+	for _, chk := range c.checks() {
+		if slices.ContainsFunc(chk.dependsOn, func(name string) bool { return !slices.Contains(passed, name) }) {
+			slog.Warn("preflight check skipped", "check", chk.name, "dependsOn", chk.dependsOn)
+			skipped = append(skipped, chk.name)
+			continue
+		}
+
+		if err := chk.run(ctx); err != nil {
+			slog.Error("preflight check failed", "check", chk.name, "err", err)
+			errs = append(errs, fmt.Errorf("%s: %w", chk.name, err))
+			failed = append(failed, chk.name)
+			continue
+		}
+
+		slog.Info("preflight check passed", "check", chk.name)
+		passed = append(passed, chk.name)
+	}
+
+	slog.Info("preflight report", "passed", passed, "failed", failed, "skipped", skipped)
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrFailed, errors.Join(errs...))
+	}
+
+	return nil
+}
+
+func (c *Checker) checks() []check {
+	checks := []check{
+		{name: "component-crd", run: c.checkComponentCRD},
+		{name: HelmRegistrySecret, run: c.checkHelmRegistry},
+		{name: ContainerRegistrySecret, run: c.checkContainerRegistry},
+		{name: DoguRegistrySecret, run: c.checkDoguRegistry},
+		{name: HelmRepositoryConfigMap, run: c.checkHelmRepository},
+	}
+
+	if c.opts.Components != nil {
+		checks = append(checks, check{name: "component-compatibility", run: c.checkCompatibility})
+	}
+
+	if c.opts.VerifyRegistries {
+		checks = append(checks,
+			check{name: "dogu-registry-login", run: c.verifyDoguRegistry, dependsOn: []string{DoguRegistrySecret}},
+			check{name: "container-registry-login", run: c.verifyContainerRegistries, dependsOn: []string{ContainerRegistrySecret}},
+			check{name: "helm-registry-login", run: c.verifyHelmRegistry, dependsOn: []string{HelmRegistrySecret, HelmRepositoryConfigMap}},
+		)
+	}
+
+	return checks
+}
+
+func (c *Checker) checkComponentCRD(context.Context) error {
+	resources, err := c.discovery.ServerResourcesForGroupVersion(componentGroupVersion)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("CRD %s/Component is not installed", componentGroupVersion)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to discover %s: %w", componentGroupVersion, err)
+	}
+
+	if !slices.ContainsFunc(resources.APIResources, func(r metav1.APIResource) bool { return r.Name == componentResourceName }) {
+		return fmt.Errorf("CRD %s/Component is not installed", componentGroupVersion)
+	}
+
+	return nil
+}
+
+func (c *Checker) checkCompatibility(context.Context) error {
+	matrix, err := compatibility.DefaultMatrix()
+	if err != nil {
+		return err
+	}
+
+	return matrix.Check(c.opts.Components)
+}
+
+func (c *Checker) checkHelmRegistry(ctx context.Context) error {
+	data, err := c.secretData(ctx, HelmRegistrySecret, "", helmRegistryConfigKey)
+	if err != nil {
+		return err
+	}
+
+	if err = validateDockerConfig(data[helmRegistryConfigKey]); err != nil {
+		return fmt.Errorf("invalid %s: %w", helmRegistryConfigKey, err)
+	}
+
+	return nil
+}
+
+func (c *Checker) checkContainerRegistry(ctx context.Context) error {
+	data, err := c.secretData(ctx, ContainerRegistrySecret, corev1.SecretTypeDockerConfigJson, corev1.DockerConfigJsonKey)
+	if err != nil {
+		return err
+	}
+
+	if err = validateDockerConfig(data[corev1.DockerConfigJsonKey]); err != nil {
+		return fmt.Errorf("invalid %s: %w", corev1.DockerConfigJsonKey, err)
+	}
+
+	return nil
+}
+
+func (c *Checker) checkDoguRegistry(ctx context.Context) error {
+	data, err := c.secretData(ctx, DoguRegistrySecret, "", doguRegistryEndpointKey, doguRegistryUsernameKey, doguRegistryPasswordKey)
+	if err != nil {
+		return err
+	}
+
+	if !isHTTPURL(string(data[doguRegistryEndpointKey])) {
+		return fmt.Errorf("%s must be an http or https URL", doguRegistryEndpointKey)
+	}
+
+	if schema, ok := data[doguRegistryURLSchemaKey]; ok && !slices.Contains([]string{"default", "index"}, string(schema)) {
+		return fmt.Errorf("%s must be %q or %q", doguRegistryURLSchemaKey, "default", "index")
+	}
+
+	return nil
+}
+
+func (c *Checker) checkHelmRepository(ctx context.Context) error {
+	configMap, err := c.configMaps.Get(ctx, HelmRepositoryConfigMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("ConfigMap %s does not exist", HelmRepositoryConfigMap)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s: %w", HelmRepositoryConfigMap, err)
+	}
+
+	if err = requireKeys(configMap.Data, helmRepositoryEndpoint, helmRepositorySchema, helmRepositoryPlainHTTP); err != nil {
+		return err
+	}
+
+	return validateHelmRepository(configMap.Data)
+}
+
+// secretData returns the data of the secret if it has the given type and contains the given keys.
+// An empty type accepts any type.
+func (c *Checker) secretData(ctx context.Context, name string, secretType corev1.SecretType, keys ...string) (map[string][]byte, error) {
+	secret, err := c.secrets.Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, fmt.Errorf("Secret %s does not exist", name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Secret %s: %w", name, err)
+	}
+
+	if secretType != "" && secret.Type != secretType {
+		return nil, fmt.Errorf("Secret %s must be of type %s, but is of type %s", name, secretType, secret.Type)
+	}
+
+	if err = requireKeys(secret.Data, keys...); err != nil {
+		return nil, err
+	}
+
+	return secret.Data, nil
+}
+
+func requireKeys[V string | []byte](data map[string]V, keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if len(data[key]) == 0 {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required key(s): %v", missing)
+	}
+
+	return nil
+}
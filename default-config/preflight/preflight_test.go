@@ -0,0 +1,143 @@
+package preflight
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const (
+	testNamespace    = "ecosystem"
+	testDockerConfig = `{"auths":{"registry.cloudogu.com":{"auth":"dXNlcjpwYXNzd29yZA=="}}}`
+)
+
+func validResources() []runtime.Object {
+	return []runtime.Object{
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: HelmRegistrySecret, Namespace: testNamespace},
+			Data:       map[string][]byte{"config.json": []byte(testDockerConfig)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: ContainerRegistrySecret, Namespace: testNamespace},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data:       map[string][]byte{".dockerconfigjson": []byte(testDockerConfig)},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: DoguRegistrySecret, Namespace: testNamespace},
+			Data: map[string][]byte{
+				"endpoint":  []byte("https://dogu.cloudogu.com/api/v2/dogus"),
+				"username":  []byte("user"),
+				"password":  []byte("password"),
+				"urlschema": []byte("default"),
+			},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: HelmRepositoryConfigMap, Namespace: testNamespace},
+			Data:       map[string]string{"endpoint": "registry.cloudogu.com", "schema": "oci", "plainHttp": "false"},
+		},
+	}
+}
+
+func newChecker(withCRD bool, objects ...runtime.Object) (*Checker, *fake.Clientset) {
+	clientSet := fake.NewClientset(objects...)
+	if withCRD {
+		clientSet.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+			GroupVersion: "k8s.cloudogu.com/v1",
+			APIResources: []metav1.APIResource{{Name: "components", Kind: "Component", Namespaced: true}},
+		}}
+	}
+
+	return NewChecker(clientSet.Discovery(), clientSet.CoreV1().Secrets(testNamespace), clientSet.CoreV1().ConfigMaps(testNamespace), Options{}), clientSet
+}
+
+func TestChecker_Run(t *testing.T) {
+	t.Run("should pass if all preconditions are met", func(t *testing.T) {
+		checker, _ := newChecker(true, validResources()...)
+
+		err := checker.Run(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should report every missing precondition", func(t *testing.T) {
+		checker, _ := newChecker(false)
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrFailed)
+		assert.ErrorContains(t, err, "component-crd: CRD k8s.cloudogu.com/v1/Component is not installed")
+		assert.ErrorContains(t, err, "component-operator-helm-registry: Secret component-operator-helm-registry does not exist")
+		assert.ErrorContains(t, err, "ces-container-registries: Secret ces-container-registries does not exist")
+		assert.ErrorContains(t, err, "k8s-dogu-operator-dogu-registry: Secret k8s-dogu-operator-dogu-registry does not exist")
+		assert.ErrorContains(t, err, "component-operator-helm-repository: ConfigMap component-operator-helm-repository does not exist")
+	})
+
+	t.Run("should fail if the group exists without the component resource", func(t *testing.T) {
+		checker, clientSet := newChecker(false, validResources()...)
+		clientSet.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{GroupVersion: "k8s.cloudogu.com/v1"}}
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "CRD k8s.cloudogu.com/v1/Component is not installed")
+	})
+
+	t.Run("should report missing keys and invalid contents", func(t *testing.T) {
+		resources := validResources()
+		resources[0].(*corev1.Secret).Data = map[string][]byte{"config.json": []byte("{")}
+		resources[1].(*corev1.Secret).Type = corev1.SecretTypeOpaque
+		resources[2].(*corev1.Secret).Data["endpoint"] = []byte("dogu.cloudogu.com")
+		delete(resources[3].(*corev1.ConfigMap).Data, "schema")
+		checker, _ := newChecker(true, resources...)
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "component-operator-helm-registry: invalid config.json: failed to parse json")
+		assert.ErrorContains(t, err, "Secret ces-container-registries must be of type kubernetes.io/dockerconfigjson, but is of type Opaque")
+		assert.ErrorContains(t, err, "k8s-dogu-operator-dogu-registry: endpoint must be an http or https URL")
+		assert.ErrorContains(t, err, "component-operator-helm-repository: missing required key(s): [schema]")
+	})
+
+	t.Run("should report api errors", func(t *testing.T) {
+		checker, clientSet := newChecker(true, validResources()...)
+		clientSet.PrependReactor("get", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get Secret ces-container-registries")
+	})
+
+	t.Run("should check the compatibility of the components", func(t *testing.T) {
+		checker, _ := newChecker(true, validResources()...)
+		checker.opts.Components = map[string]string{"k8s-dogu-operator": "3.27.0", "k8s-dogu-operator-crd": "2.11.0", "k8s-warp-menu-entry-crd": "1.0.0"}
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrFailed)
+		assert.ErrorContains(t, err, "component-compatibility: k8s-dogu-operator 3.27.0 requires k8s-dogu-operator-crd >=2.13.0, but 2.11.0 is installed")
+	})
+
+	t.Run("should pass with compatible components", func(t *testing.T) {
+		checker, _ := newChecker(true, validResources()...)
+		checker.opts.Components = map[string]string{"k8s-dogu-operator": "3.27.0", "k8s-dogu-operator-crd": "2.13.0", "k8s-warp-menu-entry-crd": "1.0.0"}
+
+		err := checker.Run(context.Background())
+
+		require.NoError(t, err)
+	})
+}
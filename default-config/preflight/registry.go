@@ -0,0 +1,357 @@
+package preflight
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// probeDogu is requested from the dogu registry to verify the credentials, because every ecosystem requires it.
+const probeDogu = "official/cas"
+
+// ociManifestMediaTypes are accepted on manifest lookups. Helm charts are stored as OCI image manifests.
+var ociManifestMediaTypes = []string{
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// errCredentialsRejected is returned if a registry answers with 401 or 403 to an authenticated request.
+var errCredentialsRejected = errors.New("credentials were rejected")
+
+type credentials struct {
+	username string
+	password string
+}
+
+// registryVerifier performs authenticated requests against the registries.
+type registryVerifier struct {
+	transport *http.Transport
+}
+
+func newRegistryVerifier() *registryVerifier {
+	return &registryVerifier{transport: http.DefaultTransport.(*http.Transport).Clone()}
+}
+
+func (v *registryVerifier) client(insecureTLS bool) *http.Client {
+	transport := v.transport.Clone()
+	if insecureTLS {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.InsecureSkipVerify = true
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// verifyDoguRegistry requests the versions of the probe dogu with basic authentication.
+func (v *registryVerifier) verifyDoguRegistry(ctx context.Context, endpoint, urlSchema string, creds credentials) error {
+	requestURL := doguVersionsURL(endpoint, urlSchema, probeDogu)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.SetBasicAuth(creds.username, creds.password)
+
+	resp, err := v.client(false).Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request %s: %w", requestURL, err)
+	}
+	defer drain(resp)
+
+	return checkStatus(requestURL, resp.StatusCode)
+}
+
+// doguVersionsURL returns the URL of the versions of a dogu like the dogu registry client of the k8s-dogu-operator.
+func doguVersionsURL(endpoint, urlSchema, dogu string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if urlSchema == "index" {
+		return fmt.Sprintf("%s/%s/_versions.json", endpoint, dogu)
+	}
+
+	return fmt.Sprintf("%s/dogus/%s/_versions", strings.TrimSuffix(endpoint, "/dogus"), dogu)
+}
+
+// verifyOCIRegistry performs the /v2/ handshake of the OCI distribution spec with the credentials.
+func (v *registryVerifier) verifyOCIRegistry(ctx context.Context, baseURL string, insecureTLS bool, creds credentials) error {
+	return v.ociRequest(ctx, http.MethodGet, baseURL+"/v2/", "", insecureTLS, creds)
+}
+
+// verifyOCIManifest looks up the manifest of repository:tag with pull permission.
+func (v *registryVerifier) verifyOCIManifest(ctx context.Context, baseURL, repository, tag string, insecureTLS bool, creds credentials) error {
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", baseURL, repository, tag)
+	return v.ociRequest(ctx, http.MethodHead, manifestURL, "repository:"+repository+":pull", insecureTLS, creds)
+}
+
+// ociRequest sends the request anonymously first. If the registry requires authentication, the request is repeated
+// with basic authentication or with a bearer token that is fetched from the realm of the registry.
+func (v *registryVerifier) ociRequest(ctx context.Context, method, requestURL, scope string, insecureTLS bool, creds credentials) error {
+	client := v.client(insecureTLS)
+
+	resp, err := v.send(ctx, client, method, requestURL, "")
+	if err != nil {
+		return err
+	}
+	drain(resp)
+	if resp.StatusCode != http.StatusUnauthorized {
+		return checkStatus(requestURL, resp.StatusCode)
+	}
+
+	authorization, err := v.authorize(ctx, client, resp.Header.Get("WWW-Authenticate"), scope, creds)
+	if err != nil {
+		return err
+	}
+
+	resp, err = v.send(ctx, client, method, requestURL, authorization)
+	if err != nil {
+		return err
+	}
+	drain(resp)
+
+	return checkStatus(requestURL, resp.StatusCode)
+}
+
+func (v *registryVerifier) send(ctx context.Context, client *http.Client, method, requestURL, authorization string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", strings.Join(ociManifestMediaTypes, ", "))
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request %s: %w", requestURL, err)
+	}
+
+	return resp, nil
+}
+
+// authorize returns the Authorization header for the challenge of the registry.
+func (v *registryVerifier) authorize(ctx context.Context, client *http.Client, challenge, scope string, creds credentials) (string, error) {
+	scheme, params := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		return "Basic " + basicAuth(creds), nil
+	case "bearer":
+		token, err := v.fetchToken(ctx, client, params, scope, creds)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q", challenge)
+	}
+}
+
+// fetchToken requests a token from the realm of a bearer challenge with basic authentication.
+func (v *registryVerifier) fetchToken(ctx context.Context, client *http.Client, params map[string]string, scope string, creds credentials) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid realm %q in authentication challenge", params["realm"])
+	}
+
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	query.Set("account", creds.username)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.SetBasicAuth(creds.username, creds.password)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to request token: %w", err)
+	}
+	defer drain(resp)
+
+	if err = checkStatus("token realm "+realm.Host, resp.StatusCode); err != nil {
+		return "", err
+	}
+
+	var body struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse token response: %w", err)
+	}
+
+	if body.Token != "" {
+		return body.Token, nil
+	}
+	if body.AccessToken != "" {
+		return body.AccessToken, nil
+	}
+
+	return "", errors.New("token response contains no token")
+}
+
+// parseChallenge parses a WWW-Authenticate header like `Bearer realm="https://auth",service="registry"`.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ",")) {
+		key, value, found := strings.Cut(rest, "=")
+		if !found {
+			break
+		}
+
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.QuotedPrefix(value); err == nil {
+			rest = value[len(unquoted):]
+			value, _ = strconv.Unquote(unquoted)
+		} else {
+			value, rest, _ = strings.Cut(value, ",")
+		}
+		params[strings.ToLower(strings.TrimSpace(key))] = value
+	}
+
+	return strings.ToLower(scheme), params
+}
+
+func checkStatus(target string, status int) error {
+	switch {
+	case status >= 200 && status < 300:
+		return nil
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return fmt.Errorf("%s: %w (status %d)", target, errCredentialsRejected, status)
+	default:
+		return fmt.Errorf("%s: unexpected status %d", target, status)
+	}
+}
+
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+}
+
+func basicAuth(creds credentials) string {
+	return base64.StdEncoding.EncodeToString([]byte(creds.username + ":" + creds.password))
+}
+
+// registryBaseURL returns the base URL of an OCI registry host of a docker config.
+// Hosts may be given with scheme and path like "https://index.docker.io/v1/".
+func registryBaseURL(host string, plainHTTP bool) string {
+	scheme := "https"
+	if plainHTTP {
+		scheme = "http"
+	}
+
+	if u, err := url.Parse(host); err == nil && u.Host != "" {
+		scheme, host = u.Scheme, u.Host
+	}
+
+	if host == "docker.io" || host == "index.docker.io" {
+		host = "registry-1.docker.io"
+	}
+
+	return scheme + "://" + strings.TrimSuffix(host, "/")
+}
+
+func (c *Checker) verifyDoguRegistry(ctx context.Context) error {
+	secret, err := c.secrets.Get(ctx, DoguRegistrySecret, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get Secret %s: %w", DoguRegistrySecret, err)
+	}
+
+	return c.registries.verifyDoguRegistry(ctx,
+		string(secret.Data[doguRegistryEndpointKey]),
+		string(secret.Data[doguRegistryURLSchemaKey]),
+		credentials{username: string(secret.Data[doguRegistryUsernameKey]), password: string(secret.Data[doguRegistryPasswordKey])},
+	)
+}
+
+// verifyContainerRegistries verifies the credentials of every registry in the .dockerconfigjson.
+func (c *Checker) verifyContainerRegistries(ctx context.Context) error {
+	secret, err := c.secrets.Get(ctx, ContainerRegistrySecret, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get Secret %s: %w", ContainerRegistrySecret, err)
+	}
+
+	config, err := parseDockerConfig(secret.Data[corev1.DockerConfigJsonKey])
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, host := range slices.Sorted(maps.Keys(config.Auths)) {
+		creds, _ := config.Auths[host].credentials()
+		if err = c.registries.verifyOCIRegistry(ctx, registryBaseURL(host, false), false, creds); err != nil {
+			errs = append(errs, fmt.Errorf("registry %s: %w", host, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// verifyHelmRegistry looks up the manifest of the configured chart with the credentials of the Helm registry.
+func (c *Checker) verifyHelmRegistry(ctx context.Context) error {
+	configMap, err := c.configMaps.Get(ctx, HelmRepositoryConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get ConfigMap %s: %w", HelmRepositoryConfigMap, err)
+	}
+	secret, err := c.secrets.Get(ctx, HelmRegistrySecret, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get Secret %s: %w", HelmRegistrySecret, err)
+	}
+
+	config, err := parseDockerConfig(secret.Data[helmRegistryConfigKey])
+	if err != nil {
+		return err
+	}
+
+	endpoint := configMap.Data[helmRepositoryEndpoint]
+	auth, ok := config.Auths[endpoint]
+	if !ok {
+		return fmt.Errorf("%s contains no credentials for %s", helmRegistryConfigKey, endpoint)
+	}
+	creds, _ := auth.credentials()
+
+	plainHTTP, _ := strconv.ParseBool(configMap.Data[helmRepositoryPlainHTTP])
+	insecureTLS, _ := strconv.ParseBool(configMap.Data[helmRepositoryInsecure])
+	baseURL := registryBaseURL(endpoint, plainHTTP)
+
+	if c.opts.HelmChart == "" {
+		return c.registries.verifyOCIRegistry(ctx, baseURL, insecureTLS, creds)
+	}
+
+	repository, tag, found := cutLast(c.opts.HelmChart, ":")
+	if !found || repository == "" || tag == "" {
+		return fmt.Errorf("invalid chart %q, expected repository:tag", c.opts.HelmChart)
+	}
+
+	return c.registries.verifyOCIManifest(ctx, baseURL, repository, tag, insecureTLS, creds)
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
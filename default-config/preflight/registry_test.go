@@ -0,0 +1,250 @@
+package preflight
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	testUsername = "user"
+	testPassword = "password"
+	testToken    = "test-token"
+	testChart    = "k8s/k8s-dogu-operator:3.27.0"
+)
+
+// newTokenRegistry emulates an OCI registry with the token authentication of the distribution spec.
+func newTokenRegistry(t *testing.T, newServer func(http.Handler) *httptest.Server) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /token", func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != testUsername || password != testPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		assert.Equal(t, "test-registry", r.URL.Query().Get("service"))
+		assert.Equal(t, testUsername, r.URL.Query().Get("account"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"token": testToken, "scope": r.URL.Query().Get("scope")})
+	})
+	authorized := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer "+testToken {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test-registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next(w, r)
+		}
+	}
+	mux.HandleFunc("GET /v2/", authorized(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("{}"))
+	}))
+	mux.HandleFunc("HEAD /v2/k8s/k8s-dogu-operator/manifests/{tag}", authorized(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Accept"), "application/vnd.oci.image.manifest.v1+json")
+		if r.PathValue("tag") != "3.27.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+	}))
+
+	server = newServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newBasicRegistry emulates an OCI registry with basic authentication.
+func newBasicRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != testUsername || password != testPassword {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("{}"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newDoguRegistry emulates the dogu registry with basic authentication.
+func newDoguRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != testUsername || password != testPassword {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v2/dogus/official/cas/_versions" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`["7.0.8-1"]`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dockerConfigFor(password string, hosts ...string) []byte {
+	auths := map[string]any{}
+	for _, host := range hosts {
+		auths[host] = map[string]string{"auth": base64.StdEncoding.EncodeToString([]byte(testUsername + ":" + password))}
+	}
+	config, _ := json.Marshal(map[string]any{"auths": auths})
+	return config
+}
+
+// registryResources returns the resources of validResources pointing to the given registries.
+func registryResources(password, doguEndpoint, helmHost string, plainHTTP bool, insecureTLS bool, containerRegistries ...string) []runtime.Object {
+	resources := validResources()
+	resources[0].(*corev1.Secret).Data["config.json"] = dockerConfigFor(password, helmHost)
+	resources[1].(*corev1.Secret).Data[".dockerconfigjson"] = dockerConfigFor(password, containerRegistries...)
+	resources[2].(*corev1.Secret).Data["endpoint"] = []byte(doguEndpoint)
+	resources[2].(*corev1.Secret).Data["password"] = []byte(password)
+	resources[3].(*corev1.ConfigMap).Data["endpoint"] = helmHost
+	resources[3].(*corev1.ConfigMap).Data["plainHttp"] = boolString(plainHTTP)
+	resources[3].(*corev1.ConfigMap).Data["insecureTls"] = boolString(insecureTLS)
+	return resources
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func hostOf(server *httptest.Server) string {
+	return strings.TrimPrefix(strings.TrimPrefix(server.URL, "http://"), "https://")
+}
+
+func TestChecker_Run_verifyRegistries(t *testing.T) {
+	doguRegistry := newDoguRegistry(t)
+	tokenRegistry := newTokenRegistry(t, httptest.NewServer)
+	basicRegistry := newBasicRegistry(t)
+	tlsRegistry := newTokenRegistry(t, httptest.NewTLSServer)
+
+	t.Run("should pass with valid credentials", func(t *testing.T) {
+		checker, _ := newChecker(true, registryResources(testPassword, doguRegistry.URL+"/api/v2/dogus/", hostOf(tokenRegistry), true, false,
+			tokenRegistry.URL, basicRegistry.URL)...)
+		checker.opts = Options{VerifyRegistries: true, HelmChart: testChart}
+
+		err := checker.Run(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should only authenticate at the helm registry without chart", func(t *testing.T) {
+		checker, _ := newChecker(true, registryResources(testPassword, doguRegistry.URL+"/api/v2/dogus", hostOf(basicRegistry), true, false,
+			basicRegistry.URL)...)
+		checker.opts = Options{VerifyRegistries: true}
+
+		err := checker.Run(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should accept self-signed certificates with insecureTls", func(t *testing.T) {
+		checker, _ := newChecker(true, registryResources(testPassword, doguRegistry.URL+"/api/v2/dogus", hostOf(tlsRegistry), false, true,
+			basicRegistry.URL)...)
+		checker.opts = Options{VerifyRegistries: true, HelmChart: testChart}
+
+		err := checker.Run(context.Background())
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should report rejected credentials of each registry", func(t *testing.T) {
+		checker, _ := newChecker(true, registryResources("wrong", doguRegistry.URL+"/api/v2/dogus", hostOf(tokenRegistry), true, false,
+			tokenRegistry.URL, basicRegistry.URL)...)
+		checker.opts = Options{VerifyRegistries: true, HelmChart: testChart}
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, ErrFailed)
+		assert.ErrorIs(t, err, errCredentialsRejected)
+		assert.ErrorContains(t, err, "dogu-registry-login: "+doguRegistry.URL+"/api/v2/dogus/official/cas/_versions: credentials were rejected (status 401)")
+		assert.ErrorContains(t, err, "registry "+basicRegistry.URL+": "+basicRegistry.URL+"/v2/: credentials were rejected (status 401)")
+		assert.ErrorContains(t, err, "container-registry-login: registry ")
+		assert.ErrorContains(t, err, "registry "+tokenRegistry.URL+": token realm "+hostOf(tokenRegistry)+": credentials were rejected (status 401)")
+		assert.ErrorContains(t, err, "helm-registry-login: token realm "+hostOf(tokenRegistry)+": credentials were rejected (status 401)")
+	})
+
+	t.Run("should report missing chart", func(t *testing.T) {
+		checker, _ := newChecker(true, registryResources(testPassword, doguRegistry.URL+"/api/v2/dogus", hostOf(tokenRegistry), true, false,
+			basicRegistry.URL)...)
+		checker.opts = Options{VerifyRegistries: true, HelmChart: "k8s/k8s-dogu-operator:0.0.1"}
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "helm-registry-login: "+tokenRegistry.URL+"/v2/k8s/k8s-dogu-operator/manifests/0.0.1: unexpected status 404")
+	})
+
+	t.Run("should reject self-signed certificates without insecureTls", func(t *testing.T) {
+		checker, _ := newChecker(true, registryResources(testPassword, doguRegistry.URL+"/api/v2/dogus", hostOf(tlsRegistry), false, false,
+			basicRegistry.URL)...)
+		checker.opts = Options{VerifyRegistries: true, HelmChart: testChart}
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "helm-registry-login: failed to request "+tlsRegistry.URL+"/v2/k8s/k8s-dogu-operator/manifests/3.27.0")
+		assert.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("should skip verification of invalid secrets", func(t *testing.T) {
+		resources := registryResources(testPassword, doguRegistry.URL+"/api/v2/dogus", hostOf(tokenRegistry), true, false, basicRegistry.URL)
+		checker, _ := newChecker(true, resources[1:]...)
+		checker.opts = Options{VerifyRegistries: true, HelmChart: testChart}
+
+		err := checker.Run(context.Background())
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "Secret component-operator-helm-registry does not exist")
+		assert.NotContains(t, err.Error(), "helm-registry-login")
+	})
+}
+
+func Test_parseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com",scope="repository:k8s/chart:pull,push"`)
+
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:k8s/chart:pull,push",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm=registry`)
+	assert.Equal(t, "basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "registry"}, params)
+}
+
+func Test_doguVersionsURL(t *testing.T) {
+	assert.Equal(t, "https://dogu.cloudogu.com/api/v2/dogus/official/cas/_versions", doguVersionsURL("https://dogu.cloudogu.com/api/v2/dogus", "default", "official/cas"))
+	assert.Equal(t, "https://dogu.cloudogu.com/api/v2/dogus/official/cas/_versions", doguVersionsURL("https://dogu.cloudogu.com/api/v2/", "", "official/cas"))
+	assert.Equal(t, "https://nexus.example.com/dogus/official/cas/_versions.json", doguVersionsURL("https://nexus.example.com/dogus/", "index", "official/cas"))
+}
+
+func Test_registryBaseURL(t *testing.T) {
+	assert.Equal(t, "https://registry.cloudogu.com", registryBaseURL("registry.cloudogu.com", false))
+	assert.Equal(t, "http://localhost:5000", registryBaseURL("localhost:5000", true))
+	assert.Equal(t, "https://registry-1.docker.io", registryBaseURL("https://index.docker.io/v1/", false))
+	assert.Equal(t, "https://registry-1.docker.io", registryBaseURL("docker.io", false))
+}
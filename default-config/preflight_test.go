@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_readPreflightConfig(t *testing.T) {
+	t.Run("success with defaults", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "ecosystem")
+
+		cfg := readPreflightConfig()
+
+		assert.Equal(t, "ecosystem", cfg.namespace)
+		assert.Equal(t, time.Minute, cfg.timeout)
+		assert.False(t, cfg.verifyRegistries)
+		assert.Empty(t, cfg.helmChart)
+	})
+	t.Run("success with registry verification", func(t *testing.T) {
+		t.Setenv("PREFLIGHT_VERIFY_REGISTRIES", "true")
+		t.Setenv("PREFLIGHT_HELM_CHART", "k8s/k8s-dogu-operator:3.27.0")
+
+		cfg := readPreflightConfig()
+
+		assert.True(t, cfg.verifyRegistries)
+		assert.Equal(t, "k8s/k8s-dogu-operator:3.27.0", cfg.helmChart)
+	})
+	t.Run("success with component values", func(t *testing.T) {
+		t.Setenv("PREFLIGHT_COMPONENT_VALUES", `{"components":{"k8s-dogu-operator":{"version":"3.27.0"}}}`)
+		t.Setenv("PREFLIGHT_CHART_VERSION", "4.8.1")
+
+		cfg := readPreflightConfig()
+
+		components, err := cfg.components()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"ecosystem-core": "4.8.1", "k8s-component-operator": "", "k8s-dogu-operator": "3.27.0"}, components)
+	})
+	t.Run("success with timeout", func(t *testing.T) {
+		t.Setenv("PREFLIGHT_TIMEOUT_SECONDS", "5")
+
+		cfg := readPreflightConfig()
+
+		assert.Equal(t, 5*time.Second, cfg.timeout)
+	})
+}
+
+func Test_preflightConfig_validate(t *testing.T) {
+	t.Run("should accept valid config", func(t *testing.T) {
+		cfg := preflightConfig{namespace: "ecosystem", timeout: time.Minute}
+
+		require.NoError(t, cfg.validate())
+	})
+
+	t.Run("should reject invalid config", func(t *testing.T) {
+		err := preflightConfig{}.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "NAMESPACE must be set")
+		assert.ErrorContains(t, err, "PREFLIGHT_TIMEOUT_SECONDS must be positive")
+	})
+
+	t.Run("should reject invalid component values", func(t *testing.T) {
+		cfg := preflightConfig{namespace: "ecosystem", timeout: time.Minute, componentValues: `{"components":[]}`}
+
+		err := cfg.validate()
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+		assert.ErrorContains(t, err, "PREFLIGHT_COMPONENT_VALUES: failed to parse values")
+	})
+}
+
+func Test_runPreflight(t *testing.T) {
+	t.Run("should fail on invalid config", func(t *testing.T) {
+		err := runPreflight(context.Background(), preflightConfig{})
+
+		require.Error(t, err)
+		assert.Equal(t, errorClassValidation, classifyError(context.Background(), err))
+	})
+}
@@ -0,0 +1,147 @@
+package registryconfig
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Config contains the credentials of the dogu, container and Helm registries.
+// The variables match the .env.template of the repository. Passwords are base64 encoded.
+type Config struct {
+	DoguRegistryURL       string
+	DoguRegistryURLSchema string
+	DoguRegistryUsername  string
+	DoguRegistryPassword  string
+
+	DockerRegistryURL      string
+	DockerRegistryUsername string
+	DockerRegistryPassword string
+	DockerRegistryEmail    string
+
+	HelmRegistryHost        string
+	HelmRegistrySchema      string
+	HelmRegistryPlainHTTP   string
+	HelmRegistryInsecureTLS string
+	HelmRegistryUsername    string
+	HelmRegistryPassword    string
+}
+
+// ReadConfig reads the config with lookup. Variables that are not set default to the values of the .env.template.
+func ReadConfig(lookup func(key string) (string, bool)) Config {
+	get := func(key, defaultValue string) string {
+		if value, ok := lookup(key); ok && value != "" {
+			return value
+		}
+		return defaultValue
+	}
+
+	return Config{
+		DoguRegistryURL:         get("DOGU_REGISTRY_URL", "https://dogu.cloudogu.com/api/v2/dogus"),
+		DoguRegistryURLSchema:   get("DOGU_REGISTRY_URL_SCHEMA", "default"),
+		DoguRegistryUsername:    get("DOGU_REGISTRY_USERNAME", ""),
+		DoguRegistryPassword:    get("DOGU_REGISTRY_PASSWORD", ""),
+		DockerRegistryURL:       get("DOCKER_REGISTRY_URL", "registry.cloudogu.com"),
+		DockerRegistryUsername:  get("DOCKER_REGISTRY_USERNAME", ""),
+		DockerRegistryPassword:  get("DOCKER_REGISTRY_PASSWORD", ""),
+		DockerRegistryEmail:     get("DOCKER_REGISTRY_EMAIL", ""),
+		HelmRegistryHost:        get("HELM_REGISTRY_HOST", "registry.cloudogu.com"),
+		HelmRegistrySchema:      get("HELM_REGISTRY_SCHEMA", "oci"),
+		HelmRegistryPlainHTTP:   get("HELM_REGISTRY_PLAIN_HTTP", "false"),
+		HelmRegistryInsecureTLS: get("HELM_REGISTRY_INSECURE_TLS", "false"),
+		HelmRegistryUsername:    get("HELM_REGISTRY_USERNAME", ""),
+		HelmRegistryPassword:    get("HELM_REGISTRY_PASSWORD", ""),
+	}
+}
+
+// ReadEnvFile reads the KEY=VALUE lines of a .env file. Empty lines and comments are skipped,
+// values may be enclosed in single or double quotes.
+func ReadEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid line %d in env file %s: expected KEY=VALUE", lineNumber, path)
+		}
+
+		values[strings.TrimSpace(key)] = unquote(strings.TrimSpace(value))
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+
+	return values, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// Validate checks that all credentials are set and that the values match the format the preflight checks expect.
+func (c Config) Validate() error {
+	var errs []error
+	required := []struct{ key, value string }{
+		{"DOGU_REGISTRY_USERNAME", c.DoguRegistryUsername},
+		{"DOGU_REGISTRY_PASSWORD", c.DoguRegistryPassword},
+		{"DOCKER_REGISTRY_USERNAME", c.DockerRegistryUsername},
+		{"DOCKER_REGISTRY_PASSWORD", c.DockerRegistryPassword},
+		{"HELM_REGISTRY_USERNAME", c.HelmRegistryUsername},
+		{"HELM_REGISTRY_PASSWORD", c.HelmRegistryPassword},
+	}
+	for _, r := range required {
+		if r.value == "" {
+			errs = append(errs, fmt.Errorf("%s must be set", r.key))
+		}
+	}
+
+	for _, p := range []struct{ key, value string }{
+		{"DOGU_REGISTRY_PASSWORD", c.DoguRegistryPassword},
+		{"DOCKER_REGISTRY_PASSWORD", c.DockerRegistryPassword},
+		{"HELM_REGISTRY_PASSWORD", c.HelmRegistryPassword},
+	} {
+		if _, err := base64.StdEncoding.DecodeString(p.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s must be base64 encoded", p.key))
+		}
+	}
+
+	if u, err := url.Parse(c.DoguRegistryURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, errors.New("DOGU_REGISTRY_URL must be an http or https URL"))
+	}
+	if c.DoguRegistryURLSchema != "default" && c.DoguRegistryURLSchema != "index" {
+		errs = append(errs, errors.New(`DOGU_REGISTRY_URL_SCHEMA must be "default" or "index"`))
+	}
+	if strings.Contains(c.HelmRegistryHost, "://") {
+		errs = append(errs, errors.New("HELM_REGISTRY_HOST must be a host without scheme"))
+	}
+	for _, b := range []struct{ key, value string }{
+		{"HELM_REGISTRY_PLAIN_HTTP", c.HelmRegistryPlainHTTP},
+		{"HELM_REGISTRY_INSECURE_TLS", c.HelmRegistryInsecureTLS},
+	} {
+		if _, err := strconv.ParseBool(b.value); err != nil {
+			errs = append(errs, fmt.Errorf("%s must be true or false", b.key))
+		}
+	}
+
+	return errors.Join(errs...)
+}
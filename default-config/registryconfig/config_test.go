@@ -0,0 +1,113 @@
+package registryconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validConfig() Config {
+	return ReadConfig(func(key string) (string, bool) {
+		value, ok := map[string]string{
+			"DOGU_REGISTRY_USERNAME":   "dogu-user",
+			"DOGU_REGISTRY_PASSWORD":   "ZG9ndS1wYXNzd29yZA==",
+			"DOCKER_REGISTRY_USERNAME": "docker-user",
+			"DOCKER_REGISTRY_PASSWORD": "ZG9ja2VyLXBhc3N3b3Jk",
+			"DOCKER_REGISTRY_EMAIL":    "test@example.com",
+			"HELM_REGISTRY_USERNAME":   "helm-user",
+			"HELM_REGISTRY_PASSWORD":   "aGVsbS1wYXNzd29yZA==",
+		}[key]
+		return value, ok
+	})
+}
+
+func TestReadConfig(t *testing.T) {
+	cfg := validConfig()
+
+	assert.Equal(t, "https://dogu.cloudogu.com/api/v2/dogus", cfg.DoguRegistryURL)
+	assert.Equal(t, "default", cfg.DoguRegistryURLSchema)
+	assert.Equal(t, "dogu-user", cfg.DoguRegistryUsername)
+	assert.Equal(t, "registry.cloudogu.com", cfg.DockerRegistryURL)
+	assert.Equal(t, "registry.cloudogu.com", cfg.HelmRegistryHost)
+	assert.Equal(t, "oci", cfg.HelmRegistrySchema)
+	assert.Equal(t, "false", cfg.HelmRegistryPlainHTTP)
+	assert.Equal(t, "false", cfg.HelmRegistryInsecureTLS)
+}
+
+func TestReadEnvFile(t *testing.T) {
+	t.Run("should read variables", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte(`# credentials for the dogu registry
+DOGU_REGISTRY_URL=https://dogu.cloudogu.com/api/v2/dogus
+
+export NAMESPACE=ecosystem
+DOGU_REGISTRY_USERNAME="dogu user"
+DOGU_REGISTRY_PASSWORD='cGFzcz13b3Jk'
+DOCKER_REGISTRY_USERNAME=
+`), 0o600))
+
+		values, err := ReadEnvFile(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"DOGU_REGISTRY_URL":        "https://dogu.cloudogu.com/api/v2/dogus",
+			"NAMESPACE":                "ecosystem",
+			"DOGU_REGISTRY_USERNAME":   "dogu user",
+			"DOGU_REGISTRY_PASSWORD":   "cGFzcz13b3Jk",
+			"DOCKER_REGISTRY_USERNAME": "",
+		}, values)
+	})
+
+	t.Run("should fail on invalid line", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte("NAMESPACE=ecosystem\nDOGU_REGISTRY_URL\n"), 0o600))
+
+		_, err := ReadEnvFile(path)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid line 2")
+	})
+
+	t.Run("should fail on missing file", func(t *testing.T) {
+		_, err := ReadEnvFile(filepath.Join(t.TempDir(), ".env"))
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to open env file")
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	t.Run("should accept valid config", func(t *testing.T) {
+		require.NoError(t, validConfig().Validate())
+	})
+
+	t.Run("should reject missing credentials", func(t *testing.T) {
+		err := ReadConfig(func(string) (string, bool) { return "", false }).Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "DOGU_REGISTRY_USERNAME must be set")
+		assert.ErrorContains(t, err, "DOCKER_REGISTRY_PASSWORD must be set")
+		assert.ErrorContains(t, err, "HELM_REGISTRY_PASSWORD must be set")
+	})
+
+	t.Run("should reject invalid values", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.DoguRegistryPassword = "not base64!"
+		cfg.DoguRegistryURL = "dogu.cloudogu.com"
+		cfg.DoguRegistryURLSchema = "v2"
+		cfg.HelmRegistryHost = "oci://registry.cloudogu.com"
+		cfg.HelmRegistryPlainHTTP = "no"
+
+		err := cfg.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "DOGU_REGISTRY_PASSWORD must be base64 encoded")
+		assert.ErrorContains(t, err, "DOGU_REGISTRY_URL must be an http or https URL")
+		assert.ErrorContains(t, err, `DOGU_REGISTRY_URL_SCHEMA must be "default" or "index"`)
+		assert.ErrorContains(t, err, "HELM_REGISTRY_HOST must be a host without scheme")
+		assert.ErrorContains(t, err, "HELM_REGISTRY_PLAIN_HTTP must be true or false")
+	})
+}
@@ -0,0 +1,219 @@
+// Code generated by mockery v2.53.3. DO NOT EDIT.
+
+package registryconfig
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// mockConfigMapClient is an autogenerated mock type for the configMapClient type
+type mockConfigMapClient struct {
+	mock.Mock
+}
+
+type mockConfigMapClient_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *mockConfigMapClient) EXPECT() *mockConfigMapClient_Expecter {
+	return &mockConfigMapClient_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function with given fields: ctx, configMap, opts
+func (_m *mockConfigMapClient) Create(ctx context.Context, configMap *v1.ConfigMap, opts metav1.CreateOptions) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, configMap, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *v1.ConfigMap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.ConfigMap, metav1.CreateOptions) (*v1.ConfigMap, error)); ok {
+		return rf(ctx, configMap, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.ConfigMap, metav1.CreateOptions) *v1.ConfigMap); ok {
+		r0 = rf(ctx, configMap, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.ConfigMap, metav1.CreateOptions) error); ok {
+		r1 = rf(ctx, configMap, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockConfigMapClient_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type mockConfigMapClient_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - configMap *v1.ConfigMap
+//   - opts metav1.CreateOptions
+func (_e *mockConfigMapClient_Expecter) Create(ctx interface{}, configMap interface{}, opts interface{}) *mockConfigMapClient_Create_Call {
+	return &mockConfigMapClient_Create_Call{Call: _e.mock.On("Create", ctx, configMap, opts)}
+}
+
+func (_c *mockConfigMapClient_Create_Call) Run(run func(ctx context.Context, configMap *v1.ConfigMap, opts metav1.CreateOptions)) *mockConfigMapClient_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1.ConfigMap), args[2].(metav1.CreateOptions))
+	})
+	return _c
+}
+
+func (_c *mockConfigMapClient_Create_Call) Return(_a0 *v1.ConfigMap, _a1 error) *mockConfigMapClient_Create_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockConfigMapClient_Create_Call) RunAndReturn(run func(context.Context, *v1.ConfigMap, metav1.CreateOptions) (*v1.ConfigMap, error)) *mockConfigMapClient_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, name, opts
+func (_m *mockConfigMapClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, name, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *v1.ConfigMap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.GetOptions) (*v1.ConfigMap, error)); ok {
+		return rf(ctx, name, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, metav1.GetOptions) *v1.ConfigMap); ok {
+		r0 = rf(ctx, name, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, metav1.GetOptions) error); ok {
+		r1 = rf(ctx, name, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockConfigMapClient_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type mockConfigMapClient_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - opts metav1.GetOptions
+func (_e *mockConfigMapClient_Expecter) Get(ctx interface{}, name interface{}, opts interface{}) *mockConfigMapClient_Get_Call {
+	return &mockConfigMapClient_Get_Call{Call: _e.mock.On("Get", ctx, name, opts)}
+}
+
+func (_c *mockConfigMapClient_Get_Call) Run(run func(ctx context.Context, name string, opts metav1.GetOptions)) *mockConfigMapClient_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(metav1.GetOptions))
+	})
+	return _c
+}
+
+func (_c *mockConfigMapClient_Get_Call) Return(_a0 *v1.ConfigMap, _a1 error) *mockConfigMapClient_Get_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockConfigMapClient_Get_Call) RunAndReturn(run func(context.Context, string, metav1.GetOptions) (*v1.ConfigMap, error)) *mockConfigMapClient_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function with given fields: ctx, configMap, opts
+func (_m *mockConfigMapClient) Update(ctx context.Context, configMap *v1.ConfigMap, opts metav1.UpdateOptions) (*v1.ConfigMap, error) {
+	ret := _m.Called(ctx, configMap, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 *v1.ConfigMap
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.ConfigMap, metav1.UpdateOptions) (*v1.ConfigMap, error)); ok {
+		return rf(ctx, configMap, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *v1.ConfigMap, metav1.UpdateOptions) *v1.ConfigMap); ok {
+		r0 = rf(ctx, configMap, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*v1.ConfigMap)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *v1.ConfigMap, metav1.UpdateOptions) error); ok {
+		r1 = rf(ctx, configMap, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// mockConfigMapClient_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type mockConfigMapClient_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - configMap *v1.ConfigMap
+//   - opts metav1.UpdateOptions
+func (_e *mockConfigMapClient_Expecter) Update(ctx interface{}, configMap interface{}, opts interface{}) *mockConfigMapClient_Update_Call {
+	return &mockConfigMapClient_Update_Call{Call: _e.mock.On("Update", ctx, configMap, opts)}
+}
+
+func (_c *mockConfigMapClient_Update_Call) Run(run func(ctx context.Context, configMap *v1.ConfigMap, opts metav1.UpdateOptions)) *mockConfigMapClient_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*v1.ConfigMap), args[2].(metav1.UpdateOptions))
+	})
+	return _c
+}
+
+func (_c *mockConfigMapClient_Update_Call) Return(_a0 *v1.ConfigMap, _a1 error) *mockConfigMapClient_Update_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *mockConfigMapClient_Update_Call) RunAndReturn(run func(context.Context, *v1.ConfigMap, metav1.UpdateOptions) (*v1.ConfigMap, error)) *mockConfigMapClient_Update_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// newMockConfigMapClient creates a new instance of mockConfigMapClient. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func newMockConfigMapClient(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *mockConfigMapClient {
+	mock := &mockConfigMapClient{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
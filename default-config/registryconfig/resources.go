@@ -0,0 +1,239 @@
+package registryconfig
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+
+	"github.com/cloudogu/ecosystem-core/default-config/preflight"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+type secretClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error)
+	Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error)
+	Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error)
+}
+
+type configMapClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error)
+	Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error)
+	Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error)
+}
+
+// Resources are the Secrets and ConfigMaps with the registry credentials that the ecosystem requires.
+type Resources struct {
+	Secrets    []*corev1.Secret
+	ConfigMaps []*corev1.ConfigMap
+}
+
+// dockerConfig is the format of .dockerconfigjson and the config.json of Helm registries.
+type dockerConfig struct {
+	Auths map[string]dockerAuth `json:"auths"`
+}
+
+type dockerAuth struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Auth     string `json:"auth"`
+}
+
+// Resources returns the Secrets and ConfigMaps of the namespace with the keys the preflight checks expect.
+// The config must be valid.
+func (c Config) Resources(namespace string) (Resources, error) {
+	doguPassword, err := base64.StdEncoding.DecodeString(c.DoguRegistryPassword)
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to decode DOGU_REGISTRY_PASSWORD: %w", err)
+	}
+	dockerPassword, err := base64.StdEncoding.DecodeString(c.DockerRegistryPassword)
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to decode DOCKER_REGISTRY_PASSWORD: %w", err)
+	}
+	helmPassword, err := base64.StdEncoding.DecodeString(c.HelmRegistryPassword)
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to decode HELM_REGISTRY_PASSWORD: %w", err)
+	}
+
+	dockerConfigJSON, err := json.Marshal(dockerConfig{Auths: map[string]dockerAuth{c.DockerRegistryURL: {
+		Username: c.DockerRegistryUsername,
+		Password: string(dockerPassword),
+		Email:    c.DockerRegistryEmail,
+		Auth:     basicAuth(c.DockerRegistryUsername, string(dockerPassword)),
+	}}})
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to marshal %s: %w", corev1.DockerConfigJsonKey, err)
+	}
+
+	helmConfigJSON, err := json.Marshal(dockerConfig{Auths: map[string]dockerAuth{c.HelmRegistryHost: {
+		Auth: basicAuth(c.HelmRegistryUsername, string(helmPassword)),
+	}}})
+	if err != nil {
+		return Resources{}, fmt.Errorf("failed to marshal config.json: %w", err)
+	}
+
+	return Resources{
+		Secrets: []*corev1.Secret{
+			newSecret(namespace, preflight.DoguRegistrySecret, corev1.SecretTypeOpaque, map[string][]byte{
+				"endpoint":  []byte(c.DoguRegistryURL),
+				"urlschema": []byte(c.DoguRegistryURLSchema),
+				"username":  []byte(c.DoguRegistryUsername),
+				"password":  doguPassword,
+			}),
+			newSecret(namespace, preflight.ContainerRegistrySecret, corev1.SecretTypeDockerConfigJson, map[string][]byte{
+				corev1.DockerConfigJsonKey: dockerConfigJSON,
+			}),
+			newSecret(namespace, preflight.HelmRegistrySecret, corev1.SecretTypeOpaque, map[string][]byte{
+				"config.json": helmConfigJSON,
+			}),
+		},
+		ConfigMaps: []*corev1.ConfigMap{{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+			ObjectMeta: metav1.ObjectMeta{Name: preflight.HelmRepositoryConfigMap, Namespace: namespace},
+			Data: map[string]string{
+				"endpoint":    c.HelmRegistryHost,
+				"schema":      c.HelmRegistrySchema,
+				"plainHttp":   c.HelmRegistryPlainHTTP,
+				"insecureTls": c.HelmRegistryInsecureTLS,
+			},
+		}},
+	}, nil
+}
+
+func newSecret(namespace, name string, secretType corev1.SecretType, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Type:       secretType,
+		Data:       data,
+	}
+}
+
+func basicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// WriteManifests writes the resources as multi-document YAML, e.g. for GitOps repositories.
+func WriteManifests(w io.Writer, resources Resources) error {
+	var objects []any
+	for _, secret := range resources.Secrets {
+		objects = append(objects, secret)
+	}
+	for _, configMap := range resources.ConfigMaps {
+		objects = append(objects, configMap)
+	}
+
+	for _, obj := range objects {
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+		if err != nil {
+			return fmt.Errorf("failed to convert manifest: %w", err)
+		}
+		// the zero creation timestamp is not omitted by the marshaller
+		unstructured.RemoveNestedField(content, "metadata", "creationTimestamp")
+
+		manifest, err := yaml.Marshal(content)
+		if err != nil {
+			return fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+
+		if _, err = fmt.Fprintf(w, "---\n%s", manifest); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Writer creates the resources or updates them if they already exist, so that it can be run repeatedly.
+type Writer struct {
+	secrets    secretClient
+	configMaps configMapClient
+}
+
+func NewWriter(secrets secretClient, configMaps configMapClient) *Writer {
+	return &Writer{secrets: secrets, configMaps: configMaps}
+}
+
+// Apply creates or updates the resources. Labels and annotations of existing resources are kept.
+func (w *Writer) Apply(ctx context.Context, resources Resources) error {
+	for _, secret := range resources.Secrets {
+		if err := w.applySecret(ctx, secret); err != nil {
+			return err
+		}
+	}
+
+	for _, configMap := range resources.ConfigMaps {
+		if err := w.applyConfigMap(ctx, configMap); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) applySecret(ctx context.Context, secret *corev1.Secret) error {
+	existing, err := w.secrets.Get(ctx, secret.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err = w.secrets.Create(ctx, secret, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create secret %q: %w", secret.Name, err)
+		}
+		slog.Info("created secret", "secret", secret.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get secret %q: %w", secret.Name, err)
+	}
+
+	if existing.Type != secret.Type {
+		return fmt.Errorf("secret %q is of type %s instead of %s, delete it to recreate it", secret.Name, existing.Type, secret.Type)
+	}
+
+	if maps.EqualFunc(existing.Data, secret.Data, func(a, b []byte) bool { return string(a) == string(b) }) {
+		slog.Info("secret is up to date", "secret", secret.Name)
+		return nil
+	}
+
+	existing.Data = secret.Data
+	if _, err = w.secrets.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update secret %q: %w", secret.Name, err)
+	}
+	slog.Info("updated secret", "secret", secret.Name)
+
+	return nil
+}
+
+func (w *Writer) applyConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	existing, err := w.configMaps.Get(ctx, configMap.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if _, err = w.configMaps.Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("failed to create configmap %q: %w", configMap.Name, err)
+		}
+		slog.Info("created configmap", "configmap", configMap.Name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get configmap %q: %w", configMap.Name, err)
+	}
+
+	if maps.Equal(existing.Data, configMap.Data) {
+		slog.Info("configmap is up to date", "configmap", configMap.Name)
+		return nil
+	}
+
+	existing.Data = configMap.Data
+	if _, err = w.configMaps.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update configmap %q: %w", configMap.Name, err)
+	}
+	slog.Info("updated configmap", "configmap", configMap.Name)
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package registryconfig
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/cloudogu/ecosystem-core/default-config/preflight"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "ecosystem"
+
+func newWriter(objects ...runtime.Object) (*Writer, *fake.Clientset) {
+	clientSet := fake.NewClientset(objects...)
+	return NewWriter(clientSet.CoreV1().Secrets(testNamespace), clientSet.CoreV1().ConfigMaps(testNamespace)), clientSet
+}
+
+func writeActions(clientSet *fake.Clientset) []string {
+	var actions []string
+	for _, action := range clientSet.Actions() {
+		if action.GetVerb() != "get" {
+			actions = append(actions, action.GetVerb()+" "+action.GetResource().Resource)
+		}
+	}
+	return actions
+}
+
+func TestConfig_Resources(t *testing.T) {
+	resources, err := validConfig().Resources(testNamespace)
+
+	require.NoError(t, err)
+	require.Len(t, resources.Secrets, 3)
+	assert.Equal(t, map[string][]byte{
+		"endpoint":  []byte("https://dogu.cloudogu.com/api/v2/dogus"),
+		"urlschema": []byte("default"),
+		"username":  []byte("dogu-user"),
+		"password":  []byte("dogu-password"),
+	}, resources.Secrets[0].Data)
+	assert.Equal(t, corev1.SecretTypeDockerConfigJson, resources.Secrets[1].Type)
+	assert.JSONEq(t, `{"auths":{"registry.cloudogu.com":{"username":"docker-user","password":"docker-password","email":"test@example.com","auth":"ZG9ja2VyLXVzZXI6ZG9ja2VyLXBhc3N3b3Jk"}}}`,
+		string(resources.Secrets[1].Data[".dockerconfigjson"]))
+	assert.JSONEq(t, `{"auths":{"registry.cloudogu.com":{"auth":"aGVsbS11c2VyOmhlbG0tcGFzc3dvcmQ="}}}`,
+		string(resources.Secrets[2].Data["config.json"]))
+	assert.Equal(t, map[string]string{"endpoint": "registry.cloudogu.com", "schema": "oci", "plainHttp": "false", "insecureTls": "false"},
+		resources.ConfigMaps[0].Data)
+}
+
+func TestWriter_Apply(t *testing.T) {
+	resources, err := validConfig().Resources(testNamespace)
+	require.NoError(t, err)
+
+	t.Run("should create resources that pass the preflight checks", func(t *testing.T) {
+		writer, clientSet := newWriter()
+
+		err := writer.Apply(context.Background(), resources)
+
+		require.NoError(t, err)
+		clientSet.Discovery().(*fakediscovery.FakeDiscovery).Resources = []*metav1.APIResourceList{{
+			GroupVersion: "k8s.cloudogu.com/v1",
+			APIResources: []metav1.APIResource{{Name: "components"}},
+		}}
+		checker := preflight.NewChecker(clientSet.Discovery(), clientSet.CoreV1().Secrets(testNamespace), clientSet.CoreV1().ConfigMaps(testNamespace), preflight.Options{})
+		assert.NoError(t, checker.Run(context.Background()))
+	})
+
+	t.Run("should not change up to date resources", func(t *testing.T) {
+		writer, clientSet := newWriter()
+		require.NoError(t, writer.Apply(context.Background(), resources))
+		clientSet.ClearActions()
+
+		err := writer.Apply(context.Background(), resources)
+
+		require.NoError(t, err)
+		assert.Empty(t, writeActions(clientSet))
+	})
+
+	t.Run("should update existing resources and keep their labels", func(t *testing.T) {
+		writer, clientSet := newWriter(
+			&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: preflight.DoguRegistrySecret, Namespace: testNamespace, Labels: map[string]string{"app": "ces"}},
+				Type:       corev1.SecretTypeOpaque,
+				Data:       map[string][]byte{"password": []byte("old")},
+			},
+			&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: preflight.HelmRepositoryConfigMap, Namespace: testNamespace},
+				Data:       map[string]string{"endpoint": "old"},
+			},
+		)
+
+		err := writer.Apply(context.Background(), resources)
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"update secrets", "create secrets", "create secrets", "update configmaps"}, writeActions(clientSet))
+		secret, err := clientSet.CoreV1().Secrets(testNamespace).Get(context.Background(), preflight.DoguRegistrySecret, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "dogu-password", string(secret.Data["password"]))
+		assert.Equal(t, map[string]string{"app": "ces"}, secret.Labels)
+	})
+
+	t.Run("should fail if existing secret has another type", func(t *testing.T) {
+		writer, _ := newWriter(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: preflight.ContainerRegistrySecret, Namespace: testNamespace},
+			Type:       corev1.SecretTypeOpaque,
+		})
+
+		err := writer.Apply(context.Background(), resources)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `secret "ces-container-registries" is of type Opaque instead of kubernetes.io/dockerconfigjson`)
+	})
+
+	t.Run("should fail on api error", func(t *testing.T) {
+		writer, clientSet := newWriter()
+		clientSet.PrependReactor("create", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+
+		err := writer.Apply(context.Background(), resources)
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, `failed to create secret "k8s-dogu-operator-dogu-registry"`)
+	})
+}
+
+func TestWriteManifests(t *testing.T) {
+	resources, err := validConfig().Resources(testNamespace)
+	require.NoError(t, err)
+	var out bytes.Buffer
+
+	err = WriteManifests(&out, resources)
+
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), `---
+apiVersion: v1
+data:
+  config.json: eyJhdXRocyI6eyJyZWdpc3RyeS5jbG91ZG9ndS5jb20iOnsiYXV0aCI6ImFHVnNiUzExYzJWeU9taGxiRzB0Y0dGemMzZHZjbVE9In19fQ==
+kind: Secret
+metadata:
+  name: component-operator-helm-registry
+  namespace: ecosystem
+type: Opaque
+`)
+	assert.Equal(t, 4, bytes.Count(out.Bytes(), []byte("---\n")))
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cloudogu/ecosystem-core/default-config/registryconfig"
+	"github.com/cloudogu/ecosystem-core/default-config/retry"
+	"k8s.io/client-go/kubernetes"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// registryConfigsOptions configures the registry-configs command, which creates or updates the Secrets and ConfigMaps
+// with the registry credentials. The variables are read from the environment and an optional env file.
+type registryConfigsOptions struct {
+	envFile string
+	dryRun  bool
+}
+
+func parseRegistryConfigsOptions(args []string) (registryConfigsOptions, error) {
+	var opts registryConfigsOptions
+	flags := flag.NewFlagSet("registry-configs", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.envFile, "env-file", "", "file with the variables of the .env.template")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print the resources as YAML manifests instead of applying them")
+
+	if err := flags.Parse(args); err != nil {
+		return registryConfigsOptions{}, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+
+	return opts, nil
+}
+
+// readRegistryConfigsLookup returns a lookup of the variables. Environment variables take precedence over the env file.
+func readRegistryConfigsLookup(envFile string) (func(key string) (string, bool), error) {
+	fileValues := map[string]string{}
+	if envFile != "" {
+		var err error
+		if fileValues, err = registryconfig.ReadEnvFile(envFile); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+		}
+	}
+
+	return func(key string) (string, bool) {
+		if value, ok := os.LookupEnv(key); ok && value != "" {
+			return value, true
+		}
+		value, ok := fileValues[key]
+		return value, ok
+	}, nil
+}
+
+func registryConfigsCommand(signalCtx context.Context, stopSignals context.CancelFunc) int {
+	err := runRegistryConfigs(signalCtx, os.Args[2:], os.Stdout)
+	stopSignals()
+
+	if err != nil {
+		class := classifyError(signalCtx, err)
+		code := exitCodes[class]
+		slog.Error("failed to create registry configs", "err", err, "errorClass", class, "exitCode", code)
+		return code
+	}
+
+	return 0
+}
+
+func runRegistryConfigs(ctx context.Context, args []string, stdout io.Writer) error {
+	opts, err := parseRegistryConfigsOptions(args)
+	if err != nil {
+		return err
+	}
+
+	lookup, err := readRegistryConfigsLookup(opts.envFile)
+	if err != nil {
+		return err
+	}
+
+	logLevel, ok := lookup("LOG_LEVEL")
+	if !ok {
+		logLevel = "info"
+	}
+	logFormat, _ := lookup("LOG_FORMAT")
+	// the log is written to stderr, so that the manifests of a dry run can be redirected from stdout
+	configureLogger(logLevel, logFormat, false)
+
+	namespace, _ := lookup("NAMESPACE")
+	if namespace == "" {
+		return fmt.Errorf("%w: NAMESPACE must be set", errInvalidJobConfig)
+	}
+
+	cfg := registryconfig.ReadConfig(lookup)
+	if err = cfg.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+
+	resources, err := cfg.Resources(namespace)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		return registryconfig.WriteManifests(stdout, resources)
+	}
+
+	kubeContext, _ := lookup("KUBE_CONTEXT_NAME")
+	clusterConfig, err := ctrlconfig.GetConfigWithContext(kubeContext)
+	if err != nil {
+		return fmt.Errorf("failed to read kube config: %w", err)
+	}
+
+	clientSet, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	slog.Info("applying registry configs...", "namespace", namespace)
+	policy := retry.DefaultPolicy()
+	writer := registryconfig.NewWriter(
+		retry.NewSecretClient(clientSet.CoreV1().Secrets(namespace), policy),
+		retry.NewConfigMapClient(clientSet.CoreV1().ConfigMaps(namespace), policy),
+	)
+	if err = writer.Apply(ctx, resources); err != nil {
+		return err
+	}
+	slog.Info("...applied registry configs")
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRegistryEnvFile = `NAMESPACE=ecosystem
+DOGU_REGISTRY_USERNAME=dogu-user
+DOGU_REGISTRY_PASSWORD=ZG9ndS1wYXNzd29yZA==
+DOCKER_REGISTRY_USERNAME=docker-user
+DOCKER_REGISTRY_PASSWORD=ZG9ja2VyLXBhc3N3b3Jk
+HELM_REGISTRY_USERNAME=helm-user
+HELM_REGISTRY_PASSWORD=aGVsbS1wYXNzd29yZA==
+`
+
+func writeRegistryEnvFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(path, []byte(testRegistryEnvFile), 0o600))
+	return path
+}
+
+func Test_runRegistryConfigs(t *testing.T) {
+	t.Run("should write manifests on dry run", func(t *testing.T) {
+		var out bytes.Buffer
+
+		err := runRegistryConfigs(context.Background(), []string{"--env-file", writeRegistryEnvFile(t), "--dry-run"}, &out)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "name: k8s-dogu-operator-dogu-registry\n  namespace: ecosystem\n")
+		assert.Contains(t, out.String(), "name: ces-container-registries\n")
+		assert.Contains(t, out.String(), "name: component-operator-helm-registry\n")
+		assert.Contains(t, out.String(), "name: component-operator-helm-repository\n")
+	})
+
+	t.Run("should prefer environment variables over the env file", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "other")
+		var out bytes.Buffer
+
+		err := runRegistryConfigs(context.Background(), []string{"--env-file", writeRegistryEnvFile(t), "--dry-run"}, &out)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "namespace: other\n")
+		assert.NotContains(t, out.String(), "namespace: ecosystem\n")
+	})
+
+	t.Run("should fail on invalid config", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "ecosystem")
+
+		err := runRegistryConfigs(context.Background(), []string{"--dry-run"}, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.Equal(t, errorClassValidation, classifyError(context.Background(), err))
+		assert.ErrorContains(t, err, "DOGU_REGISTRY_USERNAME must be set")
+	})
+
+	t.Run("should fail without namespace", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "")
+		path := filepath.Join(t.TempDir(), ".env")
+		require.NoError(t, os.WriteFile(path, []byte("DOGU_REGISTRY_USERNAME=dogu-user\n"), 0o600))
+
+		err := runRegistryConfigs(context.Background(), []string{"--env-file", path, "--dry-run"}, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "NAMESPACE must be set")
+	})
+
+	t.Run("should fail on unknown flag", func(t *testing.T) {
+		err := runRegistryConfigs(context.Background(), []string{"--unknown"}, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+	})
+
+	t.Run("should fail on missing env file", func(t *testing.T) {
+		err := runRegistryConfigs(context.Background(), []string{"--env-file", filepath.Join(t.TempDir(), ".env")}, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+	})
+}
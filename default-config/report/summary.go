@@ -0,0 +1,255 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Phase names a step of the default-config run.
+type Phase string
+
+const (
+	PhaseSetup        Phase = "setup"
+	PhaseLease        Phase = "lease"
+	PhaseComponents   Phase = "components"
+	PhaseGlobalConfig Phase = "global-config"
+	PhaseCertificate  Phase = "certificate"
+	PhaseDoguConfig   Phase = "dogu-config"
+	PhaseFQDN         Phase = "fqdn"
+	PhaseDone         Phase = "done"
+)
+
+// Names of the config repositories the key counts are collected for.
+const (
+	RepoGlobal        = "global"
+	RepoDogu          = "dogu"
+	RepoSensitiveDogu = "sensitive-dogu"
+)
+
+// maxMessageLength is the maximum size of a termination message accepted by the kubelet.
+const maxMessageLength = 4096
+
+// KeyCounts counts the default config keys that have been created or skipped because they already existed.
+type KeyCounts struct {
+	Created int
+	Skipped int
+}
+
+// Summary collects the outcome of a default-config run. All methods can be called on a nil Summary.
+type Summary struct {
+	mu             sync.Mutex
+	now            func() time.Time
+	started        time.Time
+	phase          Phase
+	phaseStarted   time.Time
+	phaseDurations map[Phase]time.Duration
+	counts         map[string]KeyCounts
+	fqdnWait       time.Duration
+	retries        int
+}
+
+func NewSummary() *Summary {
+	return newSummary(time.Now)
+}
+
+func newSummary(now func() time.Time) *Summary {
+	started := now()
+	return &Summary{
+		now:            now,
+		started:        started,
+		phase:          PhaseSetup,
+		phaseStarted:   started,
+		phaseDurations: map[Phase]time.Duration{},
+		counts:         map[string]KeyCounts{},
+	}
+}
+
+// EnterPhase marks the end of the current phase and the start of the given phase.
+// A phase that is entered multiple times accumulates its durations.
+func (s *Summary) EnterPhase(phase Phase) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	s.phaseDurations[s.phase] += now.Sub(s.phaseStarted)
+	s.phase = phase
+	s.phaseStarted = now
+}
+
+// PhaseDurations returns the time spent in each phase including the time spent in the current phase so far.
+func (s *Summary) PhaseDurations() map[Phase]time.Duration {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	durations := maps.Clone(s.phaseDurations)
+	if s.phase != PhaseDone {
+		durations[s.phase] += s.now().Sub(s.phaseStarted)
+	}
+	return durations
+}
+
+// Duration returns the time since the start of the run.
+func (s *Summary) Duration() time.Duration {
+	if s == nil {
+		return 0
+	}
+
+	return s.now().Sub(s.started)
+}
+
+// Phase returns the phase the run is currently in or has failed in.
+func (s *Summary) Phase() Phase {
+	if s == nil {
+		return ""
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.phase
+}
+
+// AddKeys adds the given counts to the counts of the given config repository.
+func (s *Summary) AddKeys(repo string, counts KeyCounts) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current := s.counts[repo]
+	current.Created += counts.Created
+	current.Skipped += counts.Skipped
+	s.counts[repo] = current
+}
+
+// Keys returns the counts of the given config repository.
+func (s *Summary) Keys(repo string) KeyCounts {
+	if s == nil {
+		return KeyCounts{}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.counts[repo]
+}
+
+// SetFQDNWait records how long the run waited for the external address of the load balancer.
+func (s *Summary) SetFQDNWait(wait time.Duration) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fqdnWait = wait
+}
+
+// FQDNWait returns how long the run waited for the external address of the load balancer.
+func (s *Summary) FQDNWait() time.Duration {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fqdnWait
+}
+
+// AddRetry counts a retried API call.
+func (s *Summary) AddRetry() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.retries++
+}
+
+// Retries returns the number of retried API calls.
+func (s *Summary) Retries() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.retries
+}
+
+// Repos returns the names of the config repositories keys have been counted for.
+func (s *Summary) Repos() []string {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Sorted(maps.Keys(s.counts))
+}
+
+// Message renders the summary as a single logfmt line. errorClass and err are empty for a successful run.
+func (s *Summary) Message(errorClass string, err error) string {
+	var b strings.Builder
+
+	if err == nil {
+		b.WriteString("result=succeeded")
+	} else {
+		b.WriteString("result=failed")
+	}
+	fmt.Fprintf(&b, " phase=%s", s.Phase())
+	if errorClass != "" {
+		fmt.Fprintf(&b, " errorClass=%s", errorClass)
+	}
+
+	if s != nil {
+		s.mu.Lock()
+		for _, repo := range slices.Sorted(maps.Keys(s.counts)) {
+			fmt.Fprintf(&b, " %s.created=%d %s.skipped=%d", repo, s.counts[repo].Created, repo, s.counts[repo].Skipped)
+		}
+		s.mu.Unlock()
+	}
+
+	if err != nil {
+		remaining := maxMessageLength - b.Len() - len(" error=")
+		b.WriteString(" error=")
+		b.WriteString(truncate(strconv.Quote(err.Error()), remaining))
+	}
+
+	return b.String()
+}
+
+// WriteTerminationMessage writes msg to the termination message file of the container.
+// Kubernetes shows its content as the reason of a failed pod.
+func WriteTerminationMessage(path string, msg string) error {
+	if err := os.WriteFile(path, []byte(msg), 0o644); err != nil {
+		return fmt.Errorf("failed to write termination message to %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func truncate(s string, maxLength int) string {
+	const ellipsis = "...\""
+	if len(s) <= maxLength {
+		return s
+	}
+
+	if maxLength <= len(ellipsis) {
+		return ""
+	}
+
+	return s[:maxLength-len(ellipsis)] + ellipsis
+}
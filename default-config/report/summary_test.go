@@ -0,0 +1,137 @@
+package report
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummary_Message(t *testing.T) {
+	t.Run("should render successful run", func(t *testing.T) {
+		summary := NewSummary()
+		summary.AddKeys(RepoGlobal, KeyCounts{Created: 3, Skipped: 1})
+		summary.AddKeys(RepoDogu, KeyCounts{Created: 2})
+		summary.AddKeys(RepoGlobal, KeyCounts{Created: 1})
+		summary.EnterPhase(PhaseDone)
+
+		msg := summary.Message("", nil)
+
+		assert.Equal(t, "result=succeeded phase=done dogu.created=2 dogu.skipped=0 global.created=4 global.skipped=1", msg)
+	})
+
+	t.Run("should render failed run", func(t *testing.T) {
+		summary := NewSummary()
+		summary.AddKeys(RepoGlobal, KeyCounts{Created: 3})
+		summary.EnterPhase(PhaseDoguConfig)
+
+		msg := summary.Message("api", errors.New(`failed to save "ldap"`))
+
+		assert.Equal(t, `result=failed phase=dogu-config errorClass=api global.created=3 global.skipped=0 error="failed to save \"ldap\""`, msg)
+	})
+
+	t.Run("should truncate long errors", func(t *testing.T) {
+		summary := NewSummary()
+
+		msg := summary.Message("unknown", errors.New(strings.Repeat("x", 2*maxMessageLength)))
+
+		assert.Len(t, msg, maxMessageLength)
+		assert.True(t, strings.HasSuffix(msg, `xxx..."`))
+	})
+
+	t.Run("should render nil summary", func(t *testing.T) {
+		var summary *Summary
+		summary.EnterPhase(PhaseLease)
+		summary.AddKeys(RepoGlobal, KeyCounts{Created: 1})
+
+		msg := summary.Message("validation", assert.AnError)
+
+		assert.Equal(t, `result=failed phase= errorClass=validation error="assert.AnError general error for testing"`, msg)
+		assert.Equal(t, KeyCounts{}, summary.Keys(RepoGlobal))
+	})
+}
+
+func TestSummary_PhaseDurations(t *testing.T) {
+	t.Run("should accumulate durations per phase", func(t *testing.T) {
+		now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		summary := newSummary(func() time.Time { return now })
+
+		now = now.Add(time.Second)
+		summary.EnterPhase(PhaseGlobalConfig)
+		now = now.Add(2 * time.Second)
+		summary.EnterPhase(PhaseCertificate)
+		now = now.Add(3 * time.Second)
+		summary.EnterPhase(PhaseGlobalConfig)
+		now = now.Add(4 * time.Second)
+
+		assert.Equal(t, map[Phase]time.Duration{
+			PhaseSetup:        time.Second,
+			PhaseGlobalConfig: 6 * time.Second,
+			PhaseCertificate:  3 * time.Second,
+		}, summary.PhaseDurations())
+		assert.Equal(t, 10*time.Second, summary.Duration())
+	})
+
+	t.Run("should not count time after the run is done", func(t *testing.T) {
+		now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+		summary := newSummary(func() time.Time { return now })
+
+		now = now.Add(time.Second)
+		summary.EnterPhase(PhaseDone)
+		now = now.Add(time.Minute)
+
+		assert.Equal(t, map[Phase]time.Duration{PhaseSetup: time.Second}, summary.PhaseDurations())
+	})
+}
+
+func TestSummary_counters(t *testing.T) {
+	t.Run("should count retries, fqdn wait and repos", func(t *testing.T) {
+		summary := NewSummary()
+		summary.AddRetry()
+		summary.AddRetry()
+		summary.SetFQDNWait(3 * time.Second)
+		summary.AddKeys(RepoSensitiveDogu, KeyCounts{Created: 1})
+		summary.AddKeys(RepoDogu, KeyCounts{Skipped: 1})
+
+		assert.Equal(t, 2, summary.Retries())
+		assert.Equal(t, 3*time.Second, summary.FQDNWait())
+		assert.Equal(t, []string{RepoDogu, RepoSensitiveDogu}, summary.Repos())
+	})
+
+	t.Run("should ignore nil summary", func(t *testing.T) {
+		var summary *Summary
+		summary.AddRetry()
+		summary.SetFQDNWait(time.Second)
+
+		assert.Zero(t, summary.Retries())
+		assert.Zero(t, summary.FQDNWait())
+		assert.Zero(t, summary.Duration())
+		assert.Nil(t, summary.PhaseDurations())
+		assert.Nil(t, summary.Repos())
+	})
+}
+
+func TestWriteTerminationMessage(t *testing.T) {
+	t.Run("should write message to file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "termination-log")
+
+		err := WriteTerminationMessage(path, "result=succeeded phase=done")
+
+		require.NoError(t, err)
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "result=succeeded phase=done", string(content))
+	})
+
+	t.Run("should fail if file cannot be written", func(t *testing.T) {
+		err := WriteTerminationMessage(filepath.Join(t.TempDir(), "missing", "termination-log"), "msg")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to write termination message")
+	})
+}
@@ -0,0 +1,124 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// ConfigMapClient retries transient errors of the wrapped client. Conflicts are returned to the caller,
+// because repeating an update with the same resource version cannot succeed.
+// It wraps the clients of the config repositories as well, because the repositories hide the cause of an error, so that
+// it cannot be classified afterwards.
+type ConfigMapClient struct {
+	corev1client.ConfigMapInterface
+	policy Policy
+}
+
+func NewConfigMapClient(client corev1client.ConfigMapInterface, policy Policy) *ConfigMapClient {
+	return &ConfigMapClient{ConfigMapInterface: client, policy: policy}
+}
+
+func (c *ConfigMapClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.ConfigMap, error) {
+	var result *corev1.ConfigMap
+	err := c.policy.Do(ctx, fmt.Sprintf("get configmap %q", name), IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.ConfigMapInterface.Get(ctx, name, opts)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *ConfigMapClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.ConfigMapList, error) {
+	var result *corev1.ConfigMapList
+	err := c.policy.Do(ctx, "list configmaps", IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.ConfigMapInterface.List(ctx, opts)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *ConfigMapClient) Create(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.CreateOptions) (*corev1.ConfigMap, error) {
+	var result *corev1.ConfigMap
+	err := c.policy.Do(ctx, fmt.Sprintf("create configmap %q", configMap.Name), IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.ConfigMapInterface.Create(ctx, configMap, opts)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *ConfigMapClient) Update(ctx context.Context, configMap *corev1.ConfigMap, opts metav1.UpdateOptions) (*corev1.ConfigMap, error) {
+	var result *corev1.ConfigMap
+	err := c.policy.Do(ctx, fmt.Sprintf("update configmap %q", configMap.Name), IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.ConfigMapInterface.Update(ctx, configMap, opts)
+		return err
+	})
+
+	return result, err
+}
+
+// SecretClient retries transient errors of the wrapped client. Conflicts are returned to the caller,
+// because repeating an update with the same resource version cannot succeed.
+// It wraps the clients of the config repositories as well, because the repositories hide the cause of an error, so that
+// it cannot be classified afterwards.
+type SecretClient struct {
+	corev1client.SecretInterface
+	policy Policy
+}
+
+func NewSecretClient(client corev1client.SecretInterface, policy Policy) *SecretClient {
+	return &SecretClient{SecretInterface: client, policy: policy}
+}
+
+func (c *SecretClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Secret, error) {
+	var result *corev1.Secret
+	err := c.policy.Do(ctx, fmt.Sprintf("get secret %q", name), IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.SecretInterface.Get(ctx, name, opts)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *SecretClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.SecretList, error) {
+	var result *corev1.SecretList
+	err := c.policy.Do(ctx, "list secrets", IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.SecretInterface.List(ctx, opts)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *SecretClient) Create(ctx context.Context, secret *corev1.Secret, opts metav1.CreateOptions) (*corev1.Secret, error) {
+	var result *corev1.Secret
+	err := c.policy.Do(ctx, fmt.Sprintf("create secret %q", secret.Name), IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.SecretInterface.Create(ctx, secret, opts)
+		return err
+	})
+
+	return result, err
+}
+
+func (c *SecretClient) Update(ctx context.Context, secret *corev1.Secret, opts metav1.UpdateOptions) (*corev1.Secret, error) {
+	var result *corev1.Secret
+	err := c.policy.Do(ctx, fmt.Sprintf("update secret %q", secret.Name), IsTransient, func(ctx context.Context) error {
+		var err error
+		result, err = c.SecretInterface.Update(ctx, secret, opts)
+		return err
+	})
+
+	return result, err
+}
@@ -0,0 +1,139 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+)
+
+const (
+	DefaultMaxAttempts    = 5
+	DefaultInitialBackoff = 200 * time.Millisecond
+	DefaultMaxBackoff     = 10 * time.Second
+	defaultMultiplier     = 2.0
+	defaultJitter         = 0.5
+)
+
+// Policy describes how often and how long an operation is retried.
+// The backoff grows exponentially from InitialBackoff up to MaxBackoff and is randomized by Jitter
+// (a fraction between 0 and 1 of the current backoff).
+type Policy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	// OnRetry is called before waiting for the next attempt. It may be nil.
+	OnRetry func(operation string, attempt int, err error)
+}
+
+func DefaultPolicy() Policy {
+	return Policy{
+		MaxAttempts:    DefaultMaxAttempts,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+		Multiplier:     defaultMultiplier,
+		Jitter:         defaultJitter,
+	}
+}
+
+// Do calls fn until it succeeds, returns an error that should not be retried or the maximum number of attempts is reached.
+func (p Policy) Do(ctx context.Context, operation string, retryable func(err error) bool, fn func(ctx context.Context) error) error {
+	maxAttempts := max(p.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if !retryable(err) {
+			return err
+		}
+
+		if attempt >= maxAttempts {
+			return fmt.Errorf("giving up on %s after %d attempts: %w", operation, attempt, err)
+		}
+
+		backoff := p.backoff(attempt)
+		slog.Warn("retryable error, trying again", "operation", operation, "attempt", attempt, "backoff", backoff, "err", err)
+		if p.OnRetry != nil {
+			p.OnRetry(operation, attempt, err)
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("stopped retrying %s: %w", operation, errors.Join(ctx.Err(), err))
+		case <-timer.C:
+		}
+	}
+}
+
+func (p Policy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+	backoff -= backoff * jitter * rand.Float64()
+
+	return time.Duration(backoff)
+}
+
+// IsTransient reports whether err is caused by a temporary problem of the api-server or the connection to it,
+// e.g. timeouts, throttling or 5xx responses.
+func IsTransient(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if cesLibErr.IsConnectionError(err) {
+		return true
+	}
+
+	if apierrors.IsServerTimeout(err) || apierrors.IsTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsInternalError(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsUnexpectedServerError(err) {
+		return true
+	}
+
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) && statusErr.Status().Code >= 500 {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return utilnet.IsConnectionRefused(err) || utilnet.IsConnectionReset(err) || utilnet.IsProbableEOF(err)
+}
+
+// IsConflict reports whether err is caused by a concurrent modification of the same resource.
+func IsConflict(err error) bool {
+	return cesLibErr.IsConflictError(err) || apierrors.IsConflict(err)
+}
+
+// IsRetryable reports whether an operation failing with err may succeed when it is repeated.
+// Forbidden, unauthorized, invalid and not found errors are considered fatal.
+func IsRetryable(err error) bool {
+	return IsConflict(err) || IsTransient(err)
+}
@@ -0,0 +1,161 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	cesLibErr "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var testResource = schema.GroupResource{Resource: "configmaps"}
+
+func testPolicy() Policy {
+	return Policy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Multiplier:     2,
+		Jitter:         0.5,
+	}
+}
+
+func TestPolicy_Do(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should succeed without retry", func(t *testing.T) {
+		calls := 0
+
+		err := testPolicy().Do(testCtx, "op", IsRetryable, func(ctx context.Context) error {
+			calls++
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should retry retryable errors until success", func(t *testing.T) {
+		calls := 0
+		var retries []int
+		policy := testPolicy()
+		policy.OnRetry = func(operation string, attempt int, err error) {
+			assert.Equal(t, "op", operation)
+			retries = append(retries, attempt)
+		}
+
+		err := policy.Do(testCtx, "op", IsRetryable, func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return apierrors.NewConflict(testResource, "global-config", assert.AnError)
+			}
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Equal(t, []int{1, 2}, retries)
+	})
+
+	t.Run("should give up after max attempts", func(t *testing.T) {
+		calls := 0
+
+		err := testPolicy().Do(testCtx, "op", IsRetryable, func(ctx context.Context) error {
+			calls++
+			return apierrors.NewServiceUnavailable("down")
+		})
+
+		require.Error(t, err)
+		assert.True(t, apierrors.IsServiceUnavailable(err))
+		assert.ErrorContains(t, err, "giving up on op after 3 attempts")
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("should not retry fatal errors", func(t *testing.T) {
+		calls := 0
+
+		err := testPolicy().Do(testCtx, "op", IsRetryable, func(ctx context.Context) error {
+			calls++
+			return apierrors.NewForbidden(testResource, "global-config", assert.AnError)
+		})
+
+		require.Error(t, err)
+		assert.True(t, apierrors.IsForbidden(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("should stop retrying when context is cancelled", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(testCtx)
+		policy := testPolicy()
+		policy.InitialBackoff = time.Hour
+		policy.MaxBackoff = time.Hour
+
+		err := policy.Do(cancelCtx, "op", IsRetryable, func(ctx context.Context) error {
+			cancel()
+			return apierrors.NewTimeoutError("slow", 1)
+		})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.True(t, apierrors.IsTimeout(err))
+		assert.ErrorContains(t, err, "stopped retrying op")
+	})
+}
+
+func TestPolicy_backoff(t *testing.T) {
+	t.Run("should grow exponentially up to max backoff", func(t *testing.T) {
+		policy := Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+		assert.Equal(t, 100*time.Millisecond, policy.backoff(1))
+		assert.Equal(t, 200*time.Millisecond, policy.backoff(2))
+		assert.Equal(t, 400*time.Millisecond, policy.backoff(3))
+		assert.Equal(t, time.Second, policy.backoff(10))
+	})
+
+	t.Run("should apply jitter", func(t *testing.T) {
+		policy := Policy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2, Jitter: 0.5}
+
+		for range 100 {
+			backoff := policy.backoff(2)
+			assert.GreaterOrEqual(t, backoff, 100*time.Millisecond)
+			assert.LessOrEqual(t, backoff, 200*time.Millisecond)
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"api conflict", apierrors.NewConflict(testResource, "cm", assert.AnError), true},
+		{"registry conflict", fmt.Errorf("wrapped: %w", cesLibErr.NewConflictError(assert.AnError)), true},
+		{"registry connection", cesLibErr.NewConnectionError(assert.AnError), true},
+		{"server timeout", apierrors.NewServerTimeout(testResource, "get", 1), true},
+		{"timeout", apierrors.NewTimeoutError("slow", 1), true},
+		{"too many requests", apierrors.NewTooManyRequests("slow down", 1), true},
+		{"internal error", apierrors.NewInternalError(assert.AnError), true},
+		{"service unavailable", apierrors.NewServiceUnavailable("down"), true},
+		{"bad gateway", apierrors.NewGenericServerResponse(502, "get", testResource, "cm", "", 0, false), true},
+		{"connection refused", fmt.Errorf("dial: %w", syscall.ECONNREFUSED), true},
+		{"forbidden", apierrors.NewForbidden(testResource, "cm", assert.AnError), false},
+		{"unauthorized", apierrors.NewUnauthorized("who are you"), false},
+		{"invalid", apierrors.NewInvalid(schema.GroupKind{Kind: "ConfigMap"}, "cm", nil), false},
+		{"not found", apierrors.NewNotFound(testResource, "cm"), false},
+		{"registry generic", cesLibErr.NewGenericError(assert.AnError), false},
+		{"other", assert.AnError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+
+	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
+	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+)
+
+type globalConfigRepo interface {
+	Get(ctx context.Context) (regLibConfig.GlobalConfig, error)
+	Create(ctx context.Context, globalConfig regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error)
+	SaveOrMerge(ctx context.Context, globalConfig regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error)
+}
+
+type doguConfigRepo interface {
+	Get(ctx context.Context, name cesLibDogu.SimpleName) (regLibConfig.DoguConfig, error)
+	Create(ctx context.Context, doguConfig regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error)
+	Update(ctx context.Context, doguConfig regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error)
+	SaveOrMerge(ctx context.Context, doguConfig regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error)
+}
+
+// GlobalConfigRepository repeats a conflicting SaveOrMerge of the wrapped repository according to a Policy, so that
+// the local changes are merged into the latest remote state again. Transient errors are retried by the client of the
+// wrapped repository, see NewConfigMapClient, because the repository hides their cause.
+type GlobalConfigRepository struct {
+	globalConfigRepo
+	policy Policy
+}
+
+func NewGlobalConfigRepository(repo globalConfigRepo, policy Policy) *GlobalConfigRepository {
+	return &GlobalConfigRepository{globalConfigRepo: repo, policy: policy}
+}
+
+func (r *GlobalConfigRepository) SaveOrMerge(ctx context.Context, globalConfig regLibConfig.GlobalConfig) (regLibConfig.GlobalConfig, error) {
+	var result regLibConfig.GlobalConfig
+	err := r.policy.Do(ctx, "save global config", IsConflict, func(ctx context.Context) error {
+		var err error
+		result, err = r.globalConfigRepo.SaveOrMerge(ctx, globalConfig)
+		return err
+	})
+
+	return result, err
+}
+
+// DoguConfigRepository repeats a conflicting SaveOrMerge of the wrapped (sensitive) dogu config repository according
+// to a Policy, so that the local changes are merged into the latest remote state again. A conflicting Update is not
+// repeated, because it replaces the remote state. Transient errors are retried by the client of the wrapped
+// repository, see NewConfigMapClient and NewSecretClient, because the repository hides their cause.
+type DoguConfigRepository struct {
+	doguConfigRepo
+	policy Policy
+}
+
+func NewDoguConfigRepository(repo doguConfigRepo, policy Policy) *DoguConfigRepository {
+	return &DoguConfigRepository{doguConfigRepo: repo, policy: policy}
+}
+
+func (r *DoguConfigRepository) SaveOrMerge(ctx context.Context, doguConfig regLibConfig.DoguConfig) (regLibConfig.DoguConfig, error) {
+	var result regLibConfig.DoguConfig
+	err := r.policy.Do(ctx, fmt.Sprintf("save dogu config %q", doguConfig.DoguName), IsConflict, func(ctx context.Context) error {
+		var err error
+		result, err = r.doguConfigRepo.SaveOrMerge(ctx, doguConfig)
+		return err
+	})
+
+	return result, err
+}
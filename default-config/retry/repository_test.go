@@ -0,0 +1,227 @@
+package retry
+
+import (
+	"context"
+	"testing"
+
+	cesLibDogu "github.com/cloudogu/ces-commons-lib/dogu"
+	regLibConfig "github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "ecosystem"
+
+// failFirst makes the first n matching calls of the fake client fail with the given error.
+func failFirst(n int, err error) k8stesting.ReactionFunc {
+	calls := 0
+	return func(action k8stesting.Action) (bool, runtime.Object, error) {
+		calls++
+		if calls <= n {
+			return true, nil, err
+		}
+		return false, nil, nil
+	}
+}
+
+// newGlobalConfigRepo wires the repository like the default-config job does.
+func newGlobalConfigRepo(t *testing.T, clientSet *fake.Clientset) *GlobalConfigRepository {
+	t.Helper()
+	policy := testPolicy()
+	cmClient := NewConfigMapClient(clientSet.CoreV1().ConfigMaps(testNamespace), policy)
+	return NewGlobalConfigRepository(repository.NewGlobalConfigRepository(cmClient), policy)
+}
+
+func TestGlobalConfigRepository_SaveOrMerge(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should merge again after a conflict with a concurrent writer", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		repo := newGlobalConfigRepo(t, clientSet)
+
+		created, err := repo.Create(testCtx, regLibConfig.CreateGlobalConfig(regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		// simulate a concurrent writer
+		concurrentWriter := repository.NewGlobalConfigRepository(clientSet.CoreV1().ConfigMaps(testNamespace))
+		concurrentCfg, err := concurrentWriter.Get(testCtx)
+		require.NoError(t, err)
+		changed, err := concurrentCfg.Set("other", "concurrent")
+		require.NoError(t, err)
+		_, err = concurrentWriter.SaveOrMerge(testCtx, regLibConfig.GlobalConfig{Config: changed})
+		require.NoError(t, err)
+
+		clientSet.PrependReactor("update", "configmaps", failFirst(2, apierrors.NewConflict(testResource, "global-config", assert.AnError)))
+
+		changed, err = created.Set("domain", "ces.local")
+		require.NoError(t, err)
+
+		_, err = repo.SaveOrMerge(testCtx, regLibConfig.GlobalConfig{Config: changed})
+		require.NoError(t, err)
+
+		actual, err := repo.Get(testCtx)
+		require.NoError(t, err)
+		domain, _ := actual.Get("domain")
+		assert.Equal(t, "ces.local", domain.String())
+		other, _ := actual.Get("other")
+		assert.Equal(t, "concurrent", other.String())
+	})
+
+	t.Run("should retry transient api-server errors", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		repo := newGlobalConfigRepo(t, clientSet)
+		_, err := repo.Create(testCtx, regLibConfig.CreateGlobalConfig(regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		lists := 0
+		clientSet.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			lists++
+			if lists <= 2 {
+				return true, nil, apierrors.NewServiceUnavailable("etcd leader changed")
+			}
+			return false, nil, nil
+		})
+
+		_, err = repo.Get(testCtx)
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, lists)
+	})
+
+	t.Run("should give up on persisting transient api-server errors without multiplying the retries", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		repo := newGlobalConfigRepo(t, clientSet)
+		_, err := repo.Create(testCtx, regLibConfig.CreateGlobalConfig(regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		lists := 0
+		clientSet.PrependReactor("list", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			lists++
+			return true, nil, apierrors.NewServiceUnavailable("etcd leader changed")
+		})
+
+		_, err = repo.Get(testCtx)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "giving up on list configmaps after 3 attempts")
+		assert.Equal(t, 3, lists)
+	})
+
+	t.Run("should give up on persisting conflicts", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		repo := newGlobalConfigRepo(t, clientSet)
+		created, err := repo.Create(testCtx, regLibConfig.CreateGlobalConfig(regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		clientSet.PrependReactor("update", "configmaps", failFirst(100, apierrors.NewConflict(testResource, "global-config", assert.AnError)))
+
+		changed, err := created.Set("domain", "ces.local")
+		require.NoError(t, err)
+
+		_, err = repo.SaveOrMerge(testCtx, regLibConfig.GlobalConfig{Config: changed})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "giving up on save global config after 3 attempts")
+	})
+
+	t.Run("should not retry forbidden errors", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		repo := newGlobalConfigRepo(t, clientSet)
+		created, err := repo.Create(testCtx, regLibConfig.CreateGlobalConfig(regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		updates := 0
+		clientSet.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updates++
+			return true, nil, apierrors.NewForbidden(testResource, "global-config", assert.AnError)
+		})
+
+		changed, err := created.Set("domain", "ces.local")
+		require.NoError(t, err)
+
+		_, err = repo.SaveOrMerge(testCtx, regLibConfig.GlobalConfig{Config: changed})
+
+		require.Error(t, err)
+		assert.Equal(t, 1, updates)
+	})
+}
+
+func TestDoguConfigRepository_SaveOrMerge(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should merge dogu config again after a conflict", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		policy := testPolicy()
+		cmClient := NewConfigMapClient(clientSet.CoreV1().ConfigMaps(testNamespace), policy)
+		repo := NewDoguConfigRepository(repository.NewDoguConfigRepository(cmClient), policy)
+
+		created, err := repo.Create(testCtx, regLibConfig.CreateDoguConfig("ldap", regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		clientSet.PrependReactor("update", "configmaps", failFirst(1, apierrors.NewConflict(testResource, "ldap-config", assert.AnError)))
+
+		changed, err := created.Set("admin_username", "admin")
+		require.NoError(t, err)
+
+		_, err = repo.SaveOrMerge(testCtx, regLibConfig.DoguConfig{DoguName: "ldap", Config: changed})
+		require.NoError(t, err)
+
+		actual, err := repo.Get(testCtx, cesLibDogu.SimpleName("ldap"))
+		require.NoError(t, err)
+		username, _ := actual.Get("admin_username")
+		assert.Equal(t, "admin", username.String())
+	})
+}
+
+func TestDoguConfigRepository_Get(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should retry transient api-server errors", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		policy := testPolicy()
+		cmClient := NewConfigMapClient(clientSet.CoreV1().ConfigMaps(testNamespace), policy)
+		repo := NewDoguConfigRepository(repository.NewDoguConfigRepository(cmClient), policy)
+		_, err := repo.Create(testCtx, regLibConfig.CreateDoguConfig("ldap", regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		clientSet.PrependReactor("list", "configmaps", failFirst(2, apierrors.NewTooManyRequests("throttled", 1)))
+
+		_, err = repo.Get(testCtx, cesLibDogu.SimpleName("ldap"))
+
+		require.NoError(t, err)
+	})
+}
+
+func TestDoguConfigRepository_Update(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should not update dogu config again after a conflict", func(t *testing.T) {
+		clientSet := fake.NewClientset()
+		policy := testPolicy()
+		secretClient := NewSecretClient(clientSet.CoreV1().Secrets(testNamespace), policy)
+		repo := NewDoguConfigRepository(repository.NewSensitiveDoguConfigRepository(secretClient), policy)
+
+		created, err := repo.Create(testCtx, regLibConfig.CreateDoguConfig("ldap", regLibConfig.Entries{}))
+		require.NoError(t, err)
+
+		updates := 0
+		clientSet.PrependReactor("update", "secrets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+			updates++
+			return true, nil, apierrors.NewConflict(testResource, "ldap-config", assert.AnError)
+		})
+
+		changed, err := created.Set("admin_password", "secret")
+		require.NoError(t, err)
+
+		_, err = repo.Update(testCtx, regLibConfig.DoguConfig{DoguName: "ldap", Config: changed})
+
+		require.Error(t, err)
+		assert.True(t, IsConflict(err))
+		assert.Equal(t, 1, updates)
+	})
+}
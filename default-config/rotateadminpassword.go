@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cloudogu/ecosystem-core/default-config/config"
+	"github.com/cloudogu/ecosystem-core/default-config/event"
+	"github.com/cloudogu/ecosystem-core/default-config/retry"
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	ctrlconfig "sigs.k8s.io/controller-runtime/pkg/client/config"
+)
+
+// rotateAdminPasswordOptions configures the rotate-admin-password command, which replaces the password of the LDAP
+// admin in the sensitive config of the ldap dogu.
+type rotateAdminPasswordOptions struct {
+	envFile      string
+	annotateDogu bool
+}
+
+func parseRotateAdminPasswordOptions(args []string) (rotateAdminPasswordOptions, error) {
+	var opts rotateAdminPasswordOptions
+	flags := flag.NewFlagSet("rotate-admin-password", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.envFile, "env-file", "", "file with the variables NAMESPACE and KUBE_CONTEXT_NAME")
+	flags.BoolVar(&opts.annotateDogu, "annotate-dogu", false, "annotate the Dogu CR of the ldap dogu with the time of the rotation")
+
+	if err := flags.Parse(args); err != nil {
+		return rotateAdminPasswordOptions{}, fmt.Errorf("%w: %w", errInvalidJobConfig, err)
+	}
+
+	return opts, nil
+}
+
+func rotateAdminPasswordCommand(signalCtx context.Context, stopSignals context.CancelFunc) int {
+	err := runRotateAdminPassword(signalCtx, os.Args[2:], os.Stdout)
+	stopSignals()
+
+	if err != nil {
+		class := classifyError(signalCtx, err)
+		code := exitCodes[class]
+		slog.Error("failed to rotate admin password", "err", err, "errorClass", class, "exitCode", code)
+		return code
+	}
+
+	return 0
+}
+
+func runRotateAdminPassword(ctx context.Context, args []string, stdout io.Writer) error {
+	opts, err := parseRotateAdminPasswordOptions(args)
+	if err != nil {
+		return err
+	}
+
+	lookup, err := readRegistryConfigsLookup(opts.envFile)
+	if err != nil {
+		return err
+	}
+
+	logLevel, ok := lookup("LOG_LEVEL")
+	if !ok {
+		logLevel = "info"
+	}
+	logFormat, _ := lookup("LOG_FORMAT")
+	// the log is written to stderr, so that the password can be redirected from stdout
+	configureLogger(logLevel, logFormat, false)
+
+	namespace, _ := lookup("NAMESPACE")
+	if namespace == "" {
+		return fmt.Errorf("%w: NAMESPACE must be set", errInvalidJobConfig)
+	}
+
+	kubeContext, _ := lookup("KUBE_CONTEXT_NAME")
+	clusterConfig, err := ctrlconfig.GetConfigWithContext(kubeContext)
+	if err != nil {
+		return fmt.Errorf("failed to read kube config: %w", err)
+	}
+
+	clientSet, err := kubernetes.NewForConfig(clusterConfig)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	var doguClient dynamic.ResourceInterface
+	if opts.annotateDogu {
+		dynamicClient, err := dynamic.NewForConfig(clusterConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create dynamic client: %w", err)
+		}
+		doguClient = dynamicClient.Resource(config.DoguResource).Namespace(namespace)
+	}
+
+	policy := retry.DefaultPolicy()
+	// the clients retry transient errors, the repositories only repeat a conflicting merge
+	configMapClient := retry.NewConfigMapClient(clientSet.CoreV1().ConfigMaps(namespace), policy)
+	secretClient := retry.NewSecretClient(clientSet.CoreV1().Secrets(namespace), policy)
+	identity, _ := os.Hostname()
+
+	rotator := config.NewAdminPasswordRotator(
+		retry.NewGlobalConfigRepository(repository.NewGlobalConfigRepository(configMapClient), policy),
+		retry.NewDoguConfigRepository(repository.NewDoguConfigRepository(configMapClient), policy),
+		retry.NewDoguConfigRepository(repository.NewSensitiveDoguConfigRepository(secretClient), policy),
+		doguClient,
+		event.NewRecorder(clientSet.CoreV1().Events(namespace), namespace, identity),
+	)
+
+	return rotateAdminPassword(ctx, rotator, stdout)
+}
+
+type adminPasswordRotator interface {
+	Rotate(ctx context.Context) (string, error)
+}
+
+// rotateAdminPassword writes the new password to stdout as soon as it has been stored, even if the rotation fails
+// afterwards, because the old password is no longer valid then.
+func rotateAdminPassword(ctx context.Context, rotator adminPasswordRotator, stdout io.Writer) error {
+	password, err := rotator.Rotate(ctx)
+	if password != "" {
+		if _, pErr := fmt.Fprintln(stdout, password); pErr != nil {
+			slog.Error("failed to write the new password", "err", pErr)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseRotateAdminPasswordOptions(t *testing.T) {
+	t.Run("should not annotate dogu by default", func(t *testing.T) {
+		opts, err := parseRotateAdminPasswordOptions(nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, rotateAdminPasswordOptions{}, opts)
+	})
+
+	t.Run("should parse flags", func(t *testing.T) {
+		opts, err := parseRotateAdminPasswordOptions([]string{"--env-file", "../.env", "--annotate-dogu"})
+
+		require.NoError(t, err)
+		assert.Equal(t, rotateAdminPasswordOptions{envFile: "../.env", annotateDogu: true}, opts)
+	})
+
+	t.Run("should fail on unknown flag", func(t *testing.T) {
+		_, err := parseRotateAdminPasswordOptions([]string{"--restart"})
+
+		require.Error(t, err)
+		assert.ErrorIs(t, err, errInvalidJobConfig)
+	})
+}
+
+func Test_runRotateAdminPassword(t *testing.T) {
+	t.Run("should fail without namespace", func(t *testing.T) {
+		t.Setenv("NAMESPACE", "")
+
+		err := runRotateAdminPassword(context.Background(), nil, &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.Equal(t, errorClassValidation, classifyError(context.Background(), err))
+		assert.ErrorContains(t, err, "NAMESPACE must be set")
+	})
+}
+
+func Test_rotateAdminPassword(t *testing.T) {
+	testCtx := context.Background()
+
+	t.Run("should write new password", func(t *testing.T) {
+		rotator := newMockAdminPasswordRotator(t)
+		rotator.EXPECT().Rotate(testCtx).Return("newPassword", nil)
+		var out bytes.Buffer
+
+		err := rotateAdminPassword(testCtx, rotator, &out)
+
+		require.NoError(t, err)
+		assert.Equal(t, "newPassword\n", out.String())
+	})
+
+	t.Run("should write stored password on error", func(t *testing.T) {
+		rotator := newMockAdminPasswordRotator(t)
+		rotator.EXPECT().Rotate(testCtx).Return("newPassword", assert.AnError)
+		var out bytes.Buffer
+
+		err := rotateAdminPassword(testCtx, rotator, &out)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, "newPassword\n", out.String())
+	})
+
+	t.Run("should write nothing if password was not stored", func(t *testing.T) {
+		rotator := newMockAdminPasswordRotator(t)
+		rotator.EXPECT().Rotate(testCtx).Return("", assert.AnError)
+		var out bytes.Buffer
+
+		err := rotateAdminPassword(testCtx, rotator, &out)
+
+		require.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, out.String())
+	})
+}
@@ -0,0 +1,173 @@
+package tracing
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// useSpanRecorder records the spans in memory until the end of the test.
+func useSpanRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	// the initial global provider cannot be set again, but a noop provider behaves the same
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	return recorder
+}
+
+type testCollector struct {
+	mu       sync.Mutex
+	requests []*collectortrace.ExportTraceServiceRequest
+}
+
+func (c *testCollector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	request := &collectortrace.ExportTraceServiceRequest{}
+	if r.URL.Path != "/v1/traces" || proto.Unmarshal(body, request) != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, request)
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.WriteHeader(http.StatusOK)
+}
+
+func TestSetup(t *testing.T) {
+	t.Run("should export spans to collector", func(t *testing.T) {
+		defer otel.SetTracerProvider(noop.NewTracerProvider())
+		collector := &testCollector{}
+		server := httptest.NewServer(collector)
+		defer server.Close()
+
+		shutdown, err := Setup(context.Background(), server.URL, "ecosystem", "default-config-abcde")
+		require.NoError(t, err)
+
+		ctx, parent := Start(context.Background(), "default-config")
+		_, child := Start(ctx, "global-config", attribute.String("dogu", "ldap"))
+		End(child, nil)
+		End(parent, nil)
+		require.NoError(t, shutdown(context.Background()))
+
+		require.Len(t, collector.requests, 1)
+		resourceSpans := collector.requests[0].ResourceSpans
+		require.Len(t, resourceSpans, 1)
+		resourceAttrs := map[string]string{}
+		for _, attr := range resourceSpans[0].Resource.Attributes {
+			resourceAttrs[attr.Key] = attr.Value.GetStringValue()
+		}
+		assert.Equal(t, ServiceName, resourceAttrs["service.name"])
+		assert.Equal(t, "ecosystem", resourceAttrs["k8s.namespace.name"])
+		assert.Equal(t, "default-config-abcde", resourceAttrs["k8s.pod.name"])
+
+		var names []string
+		for _, scopeSpans := range resourceSpans[0].ScopeSpans {
+			for _, span := range scopeSpans.Spans {
+				names = append(names, span.Name)
+			}
+		}
+		assert.ElementsMatch(t, []string{"default-config", "global-config"}, names)
+	})
+}
+
+func TestStart(t *testing.T) {
+	t.Run("should return context unchanged without setup", func(t *testing.T) {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		ctx := context.Background()
+
+		spanCtx, span := Start(ctx, "fqdn")
+
+		assert.Equal(t, ctx, spanCtx)
+		assert.False(t, span.IsRecording())
+	})
+
+	t.Run("should start child span", func(t *testing.T) {
+		recorder := useSpanRecorder(t)
+
+		ctx, parent := Start(context.Background(), "default-config")
+		_, child := Start(ctx, "fqdn", attribute.String("service", "ces-loadbalancer"))
+		End(child, nil)
+		End(parent, nil)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, "fqdn", spans[0].Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.Contains(t, spans[0].Attributes(), attribute.String("service", "ces-loadbalancer"))
+	})
+}
+
+func TestEnd(t *testing.T) {
+	t.Run("should mark span as failed", func(t *testing.T) {
+		recorder := useSpanRecorder(t)
+
+		_, span := Start(context.Background(), "fqdn")
+		End(span, assert.AnError)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Error, spans[0].Status().Code)
+		assert.Equal(t, assert.AnError.Error(), spans[0].Status().Description)
+		require.Len(t, spans[0].Events(), 1)
+		assert.Equal(t, "exception", spans[0].Events()[0].Name)
+	})
+
+	t.Run("should not mark successful span as failed", func(t *testing.T) {
+		recorder := useSpanRecorder(t)
+
+		_, span := Start(context.Background(), "fqdn")
+		End(span, nil)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	})
+}
+
+func TestWrapTransport(t *testing.T) {
+	t.Run("should create child span and propagate context", func(t *testing.T) {
+		recorder := useSpanRecorder(t)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		defer otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+
+		var traceparent string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			traceparent = r.Header.Get("traceparent")
+		}))
+		defer server.Close()
+		client := &http.Client{Transport: WrapTransport(http.DefaultTransport)}
+
+		ctx, parent := Start(context.Background(), "default-config")
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		End(parent, nil)
+
+		spans := recorder.Ended()
+		require.Len(t, spans, 2)
+		assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.Contains(t, traceparent, parent.SpanContext().TraceID().String())
+	})
+}
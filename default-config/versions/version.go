@@ -0,0 +1,183 @@
+package versions
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a component version like "3.27.0" or "75.3.5-7". A numeric suffix is the revision of the packaged
+// upstream version and orders after the version without suffix. Any other suffix marks a pre-release.
+type Version struct {
+	Major, Minor, Patch int
+	Revision            int
+	PreRelease          string
+	raw                 string
+}
+
+// ParseVersion parses a version with an optional "v" prefix, e.g. the tag "v3.27.0".
+func ParseVersion(raw string) (Version, error) {
+	core, suffix, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(raw), "v"), "-")
+	numbers, err := parseNumbers(core, 3)
+	if err != nil {
+		return Version{}, fmt.Errorf("invalid version %q: %w", raw, err)
+	}
+
+	version := Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], raw: strings.TrimPrefix(strings.TrimSpace(raw), "v")}
+	if suffix != "" {
+		if revision, err := strconv.Atoi(suffix); err == nil && revision >= 0 {
+			version.Revision = revision
+		} else {
+			version.PreRelease = suffix
+		}
+	}
+
+	return version, nil
+}
+
+// parseNumbers parses up to n dot-separated numbers. Missing numbers are zero.
+func parseNumbers(raw string, n int) ([]int, error) {
+	parts := strings.Split(raw, ".")
+	if raw == "" || len(parts) > n {
+		return nil, fmt.Errorf("expected up to %d dot-separated numbers", n)
+	}
+
+	numbers := make([]int, n)
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, fmt.Errorf("%q is not a number", part)
+		}
+		numbers[i] = number
+	}
+
+	return numbers, nil
+}
+
+func (v Version) String() string {
+	return v.raw
+}
+
+// Compare returns -1, 0 or 1 if v is older than, equal to or newer than other.
+// Pre-releases order before the release and are compared lexically.
+func (v Version) Compare(other Version) int {
+	if c := cmp.Compare(v.Major, other.Major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Minor, other.Minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.Patch, other.Patch); c != 0 {
+		return c
+	}
+	if v.PreRelease != other.PreRelease {
+		if v.PreRelease == "" {
+			return 1
+		}
+		if other.PreRelease == "" {
+			return -1
+		}
+		return cmp.Compare(v.PreRelease, other.PreRelease)
+	}
+	return cmp.Compare(v.Revision, other.Revision)
+}
+
+// Constraint restricts the versions a component is updated to, e.g. ">=3.0.0, <4.0.0", "^3.2" or "~1.10.4".
+// The comma-separated conditions must all be met. A version without operator matches only itself.
+type Constraint struct {
+	conditions []condition
+	raw        string
+}
+
+type condition struct {
+	operator string
+	version  Version
+}
+
+// operators are ordered so that the longer operators are matched first.
+var operators = []string{">=", "<=", "!=", ">", "<", "=", "^", "~"}
+
+func ParseConstraint(raw string) (Constraint, error) {
+	constraint := Constraint{raw: strings.TrimSpace(raw)}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: empty condition", raw)
+		}
+
+		operator := "="
+		for _, op := range operators {
+			if strings.HasPrefix(part, op) {
+				operator = op
+				part = strings.TrimSpace(strings.TrimPrefix(part, op))
+				break
+			}
+		}
+
+		version, err := ParseVersion(part)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", raw, err)
+		}
+
+		conditions, err := expand(operator, version)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", raw, err)
+		}
+		constraint.conditions = append(constraint.conditions, conditions...)
+	}
+
+	return constraint, nil
+}
+
+// expand resolves the caret and tilde ranges to comparisons.
+func expand(operator string, version Version) ([]condition, error) {
+	switch operator {
+	case "^":
+		upper := Version{Major: version.Major + 1}
+		if version.Major == 0 && version.Minor > 0 {
+			upper = Version{Minor: version.Minor + 1}
+		} else if version.Major == 0 {
+			upper = Version{Minor: version.Minor, Patch: version.Patch + 1}
+		}
+		return []condition{{">=", version}, {"<", upper}}, nil
+	case "~":
+		return []condition{{">=", version}, {"<", Version{Major: version.Major, Minor: version.Minor + 1}}}, nil
+	case "=", "!=", ">", ">=", "<", "<=":
+		return []condition{{operator, version}}, nil
+	default:
+		return nil, errors.New("unknown operator " + operator)
+	}
+}
+
+// Check returns true if the version meets all conditions. The zero constraint matches every version.
+func (c Constraint) Check(version Version) bool {
+	for _, cond := range c.conditions {
+		result := version.Compare(cond.version)
+		var ok bool
+		switch cond.operator {
+		case "=":
+			ok = result == 0
+		case "!=":
+			ok = result != 0
+		case ">":
+			ok = result > 0
+		case ">=":
+			ok = result >= 0
+		case "<":
+			ok = result < 0
+		case "<=":
+			ok = result <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
@@ -0,0 +1,119 @@
+package versions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Version
+	}{
+		{"3.27.0", Version{Major: 3, Minor: 27, raw: "3.27.0"}},
+		{"v1.14.0", Version{Major: 1, Minor: 14, raw: "1.14.0"}},
+		{"75.3.5-7", Version{Major: 75, Minor: 3, Patch: 5, Revision: 7, raw: "75.3.5-7"}},
+		{"4.0.0-rc.1", Version{Major: 4, PreRelease: "rc.1", raw: "4.0.0-rc.1"}},
+		{"1.2", Version{Major: 1, Minor: 2, raw: "1.2"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseVersion(tt.raw)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, raw := range []string{"", "latest", "1.2.3.4", "1.x.0", "-1.0.0"} {
+		t.Run("should fail on "+raw, func(t *testing.T) {
+			_, err := ParseVersion(raw)
+
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"3.27.0", "3.27.0", 0},
+		{"3.27.0", "3.28.0", -1},
+		{"4.0.0", "3.28.9", 1},
+		{"1.10.4", "1.9.9", 1},
+		{"75.3.5-7", "75.3.5-5", 1},
+		{"75.3.5-1", "75.3.5", 1},
+		{"4.0.0-rc1", "4.0.0", -1},
+		{"4.0.0-rc1", "3.9.0", 1},
+		{"4.0.0-rc1", "4.0.0-rc2", -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" "+tt.b, func(t *testing.T) {
+			a, err := ParseVersion(tt.a)
+			require.NoError(t, err)
+			b, err := ParseVersion(tt.b)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, a.Compare(b))
+		})
+	}
+}
+
+func TestConstraint_Check(t *testing.T) {
+	tests := []struct {
+		constraint string
+		matches    []string
+		rejects    []string
+	}{
+		{"3.27.0", []string{"3.27.0"}, []string{"3.27.1"}},
+		{"=3.27.0", []string{"3.27.0"}, []string{"3.26.0"}},
+		{"!=3.27.0", []string{"3.27.1"}, []string{"3.27.0"}},
+		{">=3.0.0, <4.0.0", []string{"3.0.0", "3.99.1"}, []string{"2.9.9", "4.0.0"}},
+		{">3.0.0,<=3.1.0", []string{"3.0.1", "3.1.0"}, []string{"3.0.0", "3.1.1"}},
+		{"<76", []string{"75.3.5-8"}, []string{"76.0.0-1"}},
+		{"^3.2", []string{"3.2.0", "3.9.0"}, []string{"3.1.9", "4.0.0"}},
+		{"^0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4"}},
+		{"~1.10.4", []string{"1.10.4", "1.10.9"}, []string{"1.11.0", "1.10.3"}},
+		{"^75.3.5-5", []string{"75.3.5-5", "75.4.0"}, []string{"75.3.5-4", "76.0.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+
+			for _, raw := range tt.matches {
+				version, err := ParseVersion(raw)
+				require.NoError(t, err)
+				assert.True(t, constraint.Check(version), raw)
+			}
+			for _, raw := range tt.rejects {
+				version, err := ParseVersion(raw)
+				require.NoError(t, err)
+				assert.False(t, constraint.Check(version), raw)
+			}
+		})
+	}
+
+	t.Run("should match every version with the zero constraint", func(t *testing.T) {
+		version, err := ParseVersion("1.0.0")
+		require.NoError(t, err)
+
+		assert.True(t, Constraint{}.Check(version))
+	})
+}
+
+func TestParseConstraint(t *testing.T) {
+	for _, raw := range []string{"", ">=1.0.0,", "=>1.0.0", "<latest"} {
+		t.Run("should fail on "+raw, func(t *testing.T) {
+			_, err := ParseConstraint(raw)
+
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "invalid constraint")
+		})
+	}
+}
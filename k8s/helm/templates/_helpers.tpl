@@ -152,6 +152,60 @@ be rendered as text output into the generated YAML.
 {{- end -}}
 
 
+{{/*
+Returns the comma-separated names of the enabled components the default-config job waits for.
+If defaultConfig.env.waitForComponentNames is set, only these of the enabled components are returned.
+*/}}
+{{- define "ecosystem-core.waitForComponentNames" -}}
+{{- $maps := list (include "ecosystem-core.effectiveComponents" . | fromYaml) -}}
+{{- if .Values.backup.enabled }}
+  {{- $maps = append $maps .Values.backup.components -}}
+{{- end }}
+{{- if .Values.monitoring.enabled }}
+  {{- $maps = append $maps .Values.monitoring.components -}}
+{{- end }}
+{{- $only := .Values.defaultConfig.env.waitForComponentNames | default list -}}
+{{- $names := list -}}
+{{- range $m := $maps }}
+  {{- range $n, $c := $m }}
+    {{- $name := $c.name | default $n -}}
+    {{- if and (not $c.disabled) (or (empty $only) (has $name $only)) }}
+      {{- $names = append $names $name -}}
+    {{- end }}
+  {{- end }}
+{{- end }}
+{{- $names | sortAlpha | join "," -}}
+{{- end -}}
+
+
+{{/*
+Returns the chart "<helm namespace>/<name>:<version>" the preflight job looks up in the Helm registry to verify its credentials.
+Defaults to the chart of the k8s-dogu-operator component. Returns nothing if its version is "latest", then only the login is verified.
+*/}}
+{{- define "ecosystem-core.preflightHelmChart" -}}
+{{- if .Values.preflight.helmChart -}}
+{{- .Values.preflight.helmChart -}}
+{{- else -}}
+{{- $doguOp := index .Values.components "k8s-dogu-operator" | default dict -}}
+{{- if and $doguOp.version (ne $doguOp.version "latest") -}}
+{{- printf "%s/%s:%s" ($doguOp.helmNamespace | default "k8s") ($doguOp.name | default "k8s-dogu-operator") $doguOp.version -}}
+{{- end -}}
+{{- end -}}
+{{- end -}}
+
+
+{{/*
+Returns the values that select the installed components as JSON for the compatibility check of the preflight job.
+Only the enabled flag and the image tag of the k8s-component-operator are included, its tag is its version.
+*/}}
+{{- define "ecosystem-core.componentValues" -}}
+{{- $operator := index .Values "k8s-component-operator" | default dict -}}
+{{- $values := pick .Values "use-lop-idp" "components" "backup" "monitoring" -}}
+{{- $_ := set $values "k8s-component-operator" (dict "enabled" $operator.enabled "manager" (dict "image" (dict "tag" (dig "manager" "image" "tag" "" $operator)))) -}}
+{{- $values | toJson -}}
+{{- end -}}
+
+
 {{/* Renders a single Component CR from a map entry (name + component spec) */}}
 {{- define "ecosystem-core.renderComponent" -}}
 {{- $name := .name -}}
@@ -20,11 +20,6 @@ update_versions_modify_files() {
   componentOperatorVersion=$(./.bin/yq '.dependencies[] | select(.name=="k8s-component-operator").version' < ${chartLockYAML})
   ./.bin/yq -i ".k8s-component-operator.manager.image.tag = \"${componentOperatorVersion}\"" "${valuesYAML}"
   ./.bin/yq -i ".values.images.componentOperator |= sub(\":(([0-9]+)\.([0-9]+)\.([0-9]+)((?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))|(?:\+[0-9A-Za-z-]+))?)\", \":${componentOperatorVersion}\")" "${componentPatchTplYAML}"
-
-  # set kubectl-version in component-patch-tpl.yaml
-  local kubectlVersion
-  kubectlVersion=$(./.bin/yq '.cleanup.image.tag' < ${valuesYAML})
-  ./.bin/yq -i ".values.images.kubectl |= sub(\":(([0-9]+)\.([0-9]+)\.([0-9]+)((?:-([0-9A-Za-z-]+(?:\.[0-9A-Za-z-]+)*))|(?:\+[0-9A-Za-z-]+))?)\", \":${kubectlVersion}\")" "${componentPatchTplYAML}"
 }
 
 update_versions_stage_modified_files() {
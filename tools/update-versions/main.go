@@ -0,0 +1,243 @@
+// update-versions updates the component versions of the chart to their newest releases. It is a development tool
+// of the repository and not part of the default-config image, e.g. "go run . --dry-run".
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/cloudogu/ecosystem-core/default-config/logging"
+	"github.com/cloudogu/ecosystem-core/default-config/registryconfig"
+	"github.com/cloudogu/ecosystem-core/tools/update-versions/updater"
+)
+
+const updateVersionsRequestTimeout = 30 * time.Second
+
+const (
+	exitCodeFailure        = 1
+	exitCodeInvalidOptions = 3
+)
+
+var errInvalidOptions = errors.New("invalid options")
+
+// updateVersionsOptions configures the update of the component versions of the chart.
+// The paths default to the files of the repository relative to the tools/update-versions directory.
+type updateVersionsOptions struct {
+	valuesFile      string
+	chartFile       string
+	repoMapping     string
+	constraintsFile string
+	source          string
+	fixtureFile     string
+	helmNamespace   string
+	envFile         string
+	dryRun          bool
+}
+
+func parseUpdateVersionsOptions(args []string) (updateVersionsOptions, error) {
+	var opts updateVersionsOptions
+	flags := flag.NewFlagSet("update-versions", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVar(&opts.valuesFile, "values", "../../k8s/helm/values.yaml", "values.yaml with the component versions")
+	flags.StringVar(&opts.chartFile, "chart", "../../k8s/helm/Chart.yaml", "Chart.yaml with the k8s-component-operator dependency")
+	flags.StringVar(&opts.repoMapping, "repo-mapping", "../../repo-mapping.txt", "component=repository lines for the github source")
+	flags.StringVar(&opts.constraintsFile, "constraints", "../../version-constraints.txt", "component=constraint lines, e.g. k8s-prometheus=<76.0.0")
+	flags.StringVar(&opts.source, "source", "github", `release source: "github", "helm" or "fixture"`)
+	flags.StringVar(&opts.fixtureFile, "fixture", "", "YAML file with the versions of each component for the fixture source")
+	flags.StringVar(&opts.helmNamespace, "helm-namespace", "k8s", "namespace of the charts for the helm source")
+	flags.StringVar(&opts.envFile, "env-file", "", "file with the HELM_REGISTRY_* variables of the .env.template")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "print the updates without writing the files")
+
+	if err := flags.Parse(args); err != nil {
+		return updateVersionsOptions{}, fmt.Errorf("%w: %w", errInvalidOptions, err)
+	}
+
+	if opts.source == "fixture" && opts.fixtureFile == "" {
+		return updateVersionsOptions{}, fmt.Errorf("%w: --fixture must be set for the fixture source", errInvalidOptions)
+	}
+
+	return opts, nil
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	err := runUpdateVersions(ctx, os.Args[1:], os.Stdout)
+	stop()
+
+	if err != nil {
+		code := exitCodeFailure
+		if errors.Is(err, errInvalidOptions) {
+			code = exitCodeInvalidOptions
+		}
+		slog.Error("failed to update versions", "err", err, "exitCode", code)
+		os.Exit(code)
+	}
+}
+
+// runUpdateVersions updates the files and writes the updates as changelog entry to stdout.
+func runUpdateVersions(ctx context.Context, args []string, stdout io.Writer) error {
+	opts, err := parseUpdateVersionsOptions(args)
+	if err != nil {
+		return err
+	}
+
+	lookup, err := readLookup(opts.envFile)
+	if err != nil {
+		return err
+	}
+
+	logLevel, ok := lookup("LOG_LEVEL")
+	if !ok {
+		logLevel = "info"
+	}
+	logFormat, _ := lookup("LOG_FORMAT")
+	// the log is written to stderr, so that the changelog entry can be redirected from stdout
+	configureLogger(logLevel, logFormat)
+
+	source, err := newReleaseSource(opts, lookup)
+	if err != nil {
+		return err
+	}
+
+	constraints, err := updater.ReadConstraints(opts.constraintsFile)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidOptions, err)
+	}
+
+	values, err := readDocument(opts.valuesFile)
+	if err != nil {
+		return err
+	}
+	chart, err := readDocument(opts.chartFile)
+	if err != nil {
+		return err
+	}
+
+	slog.Info("updating updater...", "values", opts.valuesFile, "source", opts.source)
+	updates, updateErr := updater.NewUpdater(source, constraints).Update(ctx, values, chart)
+	for _, update := range updates {
+		slog.Info("bump version", "component", update.Component, "from", update.From, "to", update.To)
+	}
+
+	if !opts.dryRun && len(updates) > 0 {
+		if err = os.WriteFile(opts.valuesFile, values.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", opts.valuesFile, err)
+		}
+		if err = os.WriteFile(opts.chartFile, chart.Bytes(), 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", opts.chartFile, err)
+		}
+	}
+
+	if err = updater.WriteChangelog(stdout, updates); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	if updateErr != nil {
+		return fmt.Errorf("failed to resolve the versions of some components: %w", updateErr)
+	}
+	slog.Info("...updated versions", "updates", len(updates))
+
+	return nil
+}
+
+func newReleaseSource(opts updateVersionsOptions, lookup func(key string) (string, bool)) (updater.Source, error) {
+	client := &http.Client{Timeout: updateVersionsRequestTimeout}
+
+	switch opts.source {
+	case "github":
+		repositories, err := updater.ReadMapping(opts.repoMapping)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidOptions, err)
+		}
+		apiURL, ok := lookup("GITHUB_API_URL")
+		if !ok || apiURL == "" {
+			apiURL = updater.DefaultGitHubURL
+		}
+		token, _ := lookup("GITHUB_TOKEN")
+		if token == "" {
+			slog.Warn("GITHUB_TOKEN is not set, the requests to the GitHub API are rate limited")
+		}
+		return updater.NewGitHubSource(client, apiURL, updater.DefaultGitHubOwner, token, repositories), nil
+	case "helm":
+		cfg := registryconfig.ReadConfig(lookup)
+		password, err := base64.StdEncoding.DecodeString(cfg.HelmRegistryPassword)
+		if err != nil {
+			return nil, fmt.Errorf("%w: HELM_REGISTRY_PASSWORD must be base64 encoded", errInvalidOptions)
+		}
+		host := cfg.HelmRegistryHost
+		if plainHTTP, _ := strconv.ParseBool(cfg.HelmRegistryPlainHTTP); plainHTTP {
+			host = "http://" + host
+		}
+		if insecure, _ := strconv.ParseBool(cfg.HelmRegistryInsecureTLS); insecure {
+			client.Transport = &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}
+		}
+		return updater.NewHelmSource(client, host, opts.helmNamespace, cfg.HelmRegistryUsername, string(password)), nil
+	case "fixture":
+		source, err := updater.ReadFixture(opts.fixtureFile)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidOptions, err)
+		}
+		return source, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown source %q", errInvalidOptions, opts.source)
+	}
+}
+
+func readDocument(path string) (*updater.Document, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read %s: %w", errInvalidOptions, path, err)
+	}
+
+	document, err := updater.ParseDocument(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errInvalidOptions, path, err)
+	}
+
+	return document, nil
+}
+
+// readLookup returns a lookup of the environment that falls back to the values of the env file.
+func readLookup(envFile string) (func(key string) (string, bool), error) {
+	fileValues := map[string]string{}
+	if envFile != "" {
+		var err error
+		if fileValues, err = registryconfig.ReadEnvFile(envFile); err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidOptions, err)
+		}
+	}
+
+	return func(key string) (string, bool) {
+		if value, ok := os.LookupEnv(key); ok && value != "" {
+			return value, true
+		}
+		value, ok := fileValues[key]
+		return value, ok
+	}, nil
+}
+
+func configureLogger(logLevel string, logFormat string) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(logLevel)); err != nil {
+		slog.Error("error parsing log level. Setting log level to INFO.", "err", err)
+		level = slog.LevelInfo
+	}
+
+	format, err := logging.ParseFormat(logFormat)
+	if err != nil {
+		slog.Error("error parsing log format. Setting log format to text.", "err", err)
+	}
+
+	slog.SetDefault(slog.New(logging.NewHandler(os.Stderr, format, level, false)))
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testUpdateValues = `components:
+  # the operator of the dogus
+  k8s-dogu-operator:
+    version: 3.27.0
+k8s-component-operator:
+  manager:
+    image:
+      tag: 1.14.0
+`
+	testUpdateChart = `dependencies:
+  - name: k8s-component-operator
+    version: "^1.14.0"
+`
+	testUpdateFixture = `k8s-dogu-operator: [3.27.0, 3.28.0, 4.0.0]
+k8s-component-operator: [v1.15.0]
+`
+)
+
+// writeUpdateVersionsFiles returns the args of the update-versions command for files in a temp dir.
+func writeUpdateVersionsFiles(t *testing.T, constraints string) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"values.yaml":             testUpdateValues,
+		"Chart.yaml":              testUpdateChart,
+		"fixture.yaml":            testUpdateFixture,
+		"version-constraints.txt": constraints,
+		"repo-mapping.txt":        "k8s-dogu-operator=k8s-dogu-repo\n",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	return dir, []string{
+		"--values", filepath.Join(dir, "values.yaml"),
+		"--chart", filepath.Join(dir, "Chart.yaml"),
+		"--constraints", filepath.Join(dir, "version-constraints.txt"),
+		"--repo-mapping", filepath.Join(dir, "repo-mapping.txt"),
+		"--fixture", filepath.Join(dir, "fixture.yaml"),
+	}
+}
+
+func readTestFile(t *testing.T, path string) string {
+	t.Helper()
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	return string(content)
+}
+
+func Test_runUpdateVersions(t *testing.T) {
+	t.Run("should update the files with the fixture source", func(t *testing.T) {
+		dir, args := writeUpdateVersionsFiles(t, "k8s-dogu-operator=<4.0.0\n")
+		var out bytes.Buffer
+
+		err := runUpdateVersions(context.Background(), append(args, "--source", "fixture"), &out)
+
+		require.NoError(t, err)
+		assert.Equal(t, "- Update components\n"+
+			"  - Bump Version of k8s-dogu-operator from 3.27.0 to 3.28.0\n"+
+			"  - Bump Version of k8s-component-operator from 1.14.0 to 1.15.0\n", out.String())
+		assert.Equal(t, "components:\n  # the operator of the dogus\n  k8s-dogu-operator:\n    version: 3.28.0\n"+
+			"k8s-component-operator:\n  manager:\n    image:\n      tag: 1.15.0\n", readTestFile(t, filepath.Join(dir, "values.yaml")))
+		assert.Equal(t, "dependencies:\n  - name: k8s-component-operator\n    version: \"^1.15.0\"\n", readTestFile(t, filepath.Join(dir, "Chart.yaml")))
+	})
+
+	t.Run("should not write the files on dry run", func(t *testing.T) {
+		dir, args := writeUpdateVersionsFiles(t, "")
+		var out bytes.Buffer
+
+		err := runUpdateVersions(context.Background(), append(args, "--source", "fixture", "--dry-run"), &out)
+
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "Bump Version of k8s-dogu-operator from 3.27.0 to 4.0.0\n")
+		assert.Equal(t, testUpdateValues, readTestFile(t, filepath.Join(dir, "values.yaml")))
+		assert.Equal(t, testUpdateChart, readTestFile(t, filepath.Join(dir, "Chart.yaml")))
+	})
+
+	t.Run("should query the github api with the repo mapping", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+			switch r.URL.Path {
+			case "/repos/cloudogu/k8s-dogu-repo/releases":
+				_, _ = w.Write([]byte(`[{"tag_name":"v3.29.0"}]`))
+			case "/repos/cloudogu/k8s-component-operator/releases":
+				_, _ = w.Write([]byte(`[{"tag_name":"v1.14.0"}]`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+		t.Setenv("GITHUB_API_URL", server.URL)
+		t.Setenv("GITHUB_TOKEN", "secret")
+		_, args := writeUpdateVersionsFiles(t, "")
+		var out bytes.Buffer
+
+		err := runUpdateVersions(context.Background(), append(args, "--dry-run"), &out)
+
+		require.NoError(t, err)
+		assert.Equal(t, "- Update components\n  - Bump Version of k8s-dogu-operator from 3.27.0 to 3.29.0\n", out.String())
+	})
+
+	t.Run("should fail if the releases of a component cannot be listed", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+		t.Setenv("GITHUB_API_URL", server.URL)
+		_, args := writeUpdateVersionsFiles(t, "")
+
+		err := runUpdateVersions(context.Background(), append(args, "--dry-run"), &bytes.Buffer{})
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to resolve the versions of some components")
+		assert.ErrorContains(t, err, "component k8s-dogu-operator")
+	})
+
+	t.Run("should fail on invalid options", func(t *testing.T) {
+		_, args := writeUpdateVersionsFiles(t, "k8s-dogu-operator=<latest\n")
+		tests := map[string][]string{
+			"unknown flag":       {"--unknown"},
+			"unknown source":     {"--source", "svn"},
+			"missing fixture":    {"--source", "fixture", "--fixture", ""},
+			"invalid constraint": append(args, "--source", "fixture"),
+			"missing values":     {"--source", "fixture", "--fixture", args[9], "--values", filepath.Join(t.TempDir(), "values.yaml")},
+		}
+		for name, args := range tests {
+			t.Run(name, func(t *testing.T) {
+				err := runUpdateVersions(context.Background(), args, &bytes.Buffer{})
+
+				require.Error(t, err)
+				assert.ErrorIs(t, err, errInvalidOptions)
+			})
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package updater
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is a YAML file whose versions are replaced in place, so that comments and formatting are preserved.
+type Document struct {
+	lines [][]byte
+	root  *yaml.Node
+}
+
+// Field is a scalar of a document, e.g. the version of a component.
+type Field struct {
+	// Path is the dot-separated path of the field, e.g. "components.k8s-dogu-operator.version".
+	Path  string
+	Value string
+	node  *yaml.Node
+}
+
+func ParseDocument(raw []byte) (*Document, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse yaml: %w", err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("yaml document must be a mapping")
+	}
+
+	return &Document{lines: bytes.SplitAfter(raw, []byte("\n")), root: root.Content[0]}, nil
+}
+
+// ComponentVersions returns the version field of every mapping that has one, like the components of the values.yaml.
+// The component is the key of the mapping.
+func (d *Document) ComponentVersions() map[string]Field {
+	fields := map[string]Field{}
+	var walk func(node *yaml.Node, path []string)
+	walk = func(node *yaml.Node, path []string) {
+		if node.Kind != yaml.MappingNode {
+			return
+		}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "version" && value.Kind == yaml.ScalarNode && len(path) > 0 {
+				fields[path[len(path)-1]] = newField(append(path, key.Value), value)
+				continue
+			}
+			walk(value, append(path[:len(path):len(path)], key.Value))
+		}
+	}
+	walk(d.root, nil)
+
+	return fields
+}
+
+// Lookup returns the scalar at the path of mapping keys.
+func (d *Document) Lookup(path ...string) (Field, bool) {
+	node := d.root
+	for _, key := range path {
+		node = mappingValue(node, key)
+		if node == nil {
+			return Field{}, false
+		}
+	}
+	if node.Kind != yaml.ScalarNode {
+		return Field{}, false
+	}
+
+	return newField(path, node), true
+}
+
+// Dependency returns the version of the dependency of a Chart.yaml.
+func (d *Document) Dependency(name string) (Field, bool) {
+	dependencies := mappingValue(d.root, "dependencies")
+	if dependencies == nil || dependencies.Kind != yaml.SequenceNode {
+		return Field{}, false
+	}
+
+	for i, dependency := range dependencies.Content {
+		if nameNode := mappingValue(dependency, "name"); nameNode == nil || nameNode.Value != name {
+			continue
+		}
+		if version := mappingValue(dependency, "version"); version != nil && version.Kind == yaml.ScalarNode {
+			return newField([]string{"dependencies", fmt.Sprint(i), "version"}, version), true
+		}
+	}
+
+	return Field{}, false
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func newField(path []string, node *yaml.Node) Field {
+	return Field{Path: strings.Join(path, "."), Value: node.Value, node: node}
+}
+
+// Set replaces the value of the field in the document. Quotes of the value are kept.
+func (d *Document) Set(field Field, value string) error {
+	node := field.node
+	if node == nil || node.Line < 1 || node.Line > len(d.lines) {
+		return fmt.Errorf("field %s is not part of the document", field.Path)
+	}
+
+	quote := ""
+	switch node.Style {
+	case 0:
+	case yaml.DoubleQuotedStyle:
+		quote = `"`
+	case yaml.SingleQuotedStyle:
+		quote = "'"
+	default:
+		return fmt.Errorf("field %s has an unsupported style", field.Path)
+	}
+
+	// the column counts characters, not bytes
+	line := []rune(string(d.lines[node.Line-1]))
+	start := node.Column - 1
+	old := quote + node.Value + quote
+	if start < 0 || start+len([]rune(old)) > len(line) || string(line[start:start+len([]rune(old))]) != old {
+		return fmt.Errorf("field %s does not contain %s in line %d", field.Path, old, node.Line)
+	}
+
+	replaced := string(line[:start]) + quote + value + quote + string(line[start+len([]rune(old)):])
+	d.lines[node.Line-1] = []byte(replaced)
+	node.Value = value
+
+	return nil
+}
+
+// Bytes returns the document with the replaced values.
+func (d *Document) Bytes() []byte {
+	return bytes.Join(d.lines, nil)
+}
@@ -0,0 +1,147 @@
+package updater
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testValues = `# Default values for ecosystem-core.
+k8s-component-operator:
+  manager:
+    image:
+      tag: 1.14.0 # the chart version
+components:
+  k8s-dogu-operator:
+    version: 3.27.0
+    valuesObject:
+      env:
+        version: 1
+
+  k8s-ces-gateway:
+    version: "3.3.3"
+monitoring:
+  # the stack is optional
+  components:
+    k8s-prometheus:
+      version: '75.3.5-7'
+`
+
+const testChart = `apiVersion: v2
+name: ecosystem-core
+version: 0.0.0-replaceme
+dependencies:
+  - name: other
+    version: "1.0.0"
+  - name: k8s-component-operator
+    version: "^1.14.0"
+    repository: "oci://registry.cloudogu.com/k8s"
+`
+
+func TestDocument_ComponentVersions(t *testing.T) {
+	document, err := ParseDocument([]byte(testValues))
+	require.NoError(t, err)
+
+	fields := document.ComponentVersions()
+
+	require.Len(t, fields, 4)
+	assert.Equal(t, "components.k8s-dogu-operator.version", fields["k8s-dogu-operator"].Path)
+	assert.Equal(t, "3.27.0", fields["k8s-dogu-operator"].Value)
+	assert.Equal(t, "3.3.3", fields["k8s-ces-gateway"].Value)
+	assert.Equal(t, "monitoring.components.k8s-prometheus.version", fields["k8s-prometheus"].Path)
+	assert.Equal(t, "75.3.5-7", fields["k8s-prometheus"].Value)
+	// nested versions are found like components, they are skipped by the updater if they are no versions
+	assert.Equal(t, "1", fields["env"].Value)
+}
+
+func TestDocument_Set(t *testing.T) {
+	t.Run("should replace the values and keep comments, quotes and empty lines", func(t *testing.T) {
+		document, err := ParseDocument([]byte(testValues))
+		require.NoError(t, err)
+		fields := document.ComponentVersions()
+		tag, ok := document.Lookup("k8s-component-operator", "manager", "image", "tag")
+		require.True(t, ok)
+
+		require.NoError(t, document.Set(fields["k8s-dogu-operator"], "3.28.0"))
+		require.NoError(t, document.Set(fields["k8s-ces-gateway"], "3.10.0"))
+		require.NoError(t, document.Set(fields["k8s-prometheus"], "75.3.5-8"))
+		require.NoError(t, document.Set(tag, "1.15.0"))
+
+		expected := `# Default values for ecosystem-core.
+k8s-component-operator:
+  manager:
+    image:
+      tag: 1.15.0 # the chart version
+components:
+  k8s-dogu-operator:
+    version: 3.28.0
+    valuesObject:
+      env:
+        version: 1
+
+  k8s-ces-gateway:
+    version: "3.10.0"
+monitoring:
+  # the stack is optional
+  components:
+    k8s-prometheus:
+      version: '75.3.5-8'
+`
+		assert.Equal(t, expected, string(document.Bytes()))
+	})
+
+	t.Run("should replace the version of a dependency", func(t *testing.T) {
+		document, err := ParseDocument([]byte(testChart))
+		require.NoError(t, err)
+		dependency, ok := document.Dependency("k8s-component-operator")
+		require.True(t, ok)
+		assert.Equal(t, "^1.14.0", dependency.Value)
+		assert.Equal(t, "dependencies.1.version", dependency.Path)
+
+		require.NoError(t, document.Set(dependency, "^1.15.0"))
+
+		assert.Contains(t, string(document.Bytes()), "  - name: other\n    version: \"1.0.0\"\n")
+		assert.Contains(t, string(document.Bytes()), "    version: \"^1.15.0\"\n    repository:")
+	})
+
+	t.Run("should fail on a field of another document", func(t *testing.T) {
+		document, err := ParseDocument([]byte(testValues))
+		require.NoError(t, err)
+
+		err = document.Set(Field{Path: "components"}, "1.0.0")
+
+		assert.ErrorContains(t, err, "field components is not part of the document")
+	})
+
+	t.Run("should fail on a block scalar", func(t *testing.T) {
+		document, err := ParseDocument([]byte("component:\n  version: |\n    1.0.0\n"))
+		require.NoError(t, err)
+
+		err = document.Set(document.ComponentVersions()["component"], "1.0.1")
+
+		assert.ErrorContains(t, err, "unsupported style")
+	})
+}
+
+func TestDocument_Lookup(t *testing.T) {
+	document, err := ParseDocument([]byte(testValues))
+	require.NoError(t, err)
+
+	_, ok := document.Lookup("k8s-component-operator", "manager", "image", "repository")
+	assert.False(t, ok)
+	_, ok = document.Lookup("components")
+	assert.False(t, ok)
+	_, ok = document.Dependency("k8s-component-operator")
+	assert.False(t, ok)
+}
+
+func TestParseDocument(t *testing.T) {
+	for name, raw := range map[string]string{"sequence": "- a\n", "invalid": "a: [\n", "empty": ""} {
+		t.Run("should fail on "+name, func(t *testing.T) {
+			_, err := ParseDocument([]byte(raw))
+
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package updater
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/cloudogu/ecosystem-core/default-config/versions"
+)
+
+// ReadMapping reads the component=value lines of a file like the repo-mapping.txt.
+// Empty lines and comments are skipped. A file that does not exist is an empty mapping.
+func ReadMapping(path string) (map[string]string, error) {
+	mapping := map[string]string{}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return mapping, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open mapping: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		component, value, found := strings.Cut(line, "=")
+		component, value = strings.TrimSpace(component), strings.TrimSpace(value)
+		if !found || component == "" || value == "" {
+			return nil, fmt.Errorf("%s:%d: expected component=value", path, lineNumber)
+		}
+		mapping[component] = value
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read mapping: %w", err)
+	}
+
+	return mapping, nil
+}
+
+// ReadConstraints reads the component=constraint lines of a file, e.g. "k8s-prometheus=<76.0.0".
+func ReadConstraints(path string) (map[string]versions.Constraint, error) {
+	mapping, err := ReadMapping(path)
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := make(map[string]versions.Constraint, len(mapping))
+	for component, raw := range mapping {
+		constraint, err := versions.ParseConstraint(raw)
+		if err != nil {
+			return nil, fmt.Errorf("component %s: %w", component, err)
+		}
+		constraints[component] = constraint
+	}
+
+	return constraints, nil
+}
@@ -0,0 +1,57 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeMapping(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "mapping.txt")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestReadMapping(t *testing.T) {
+	t.Run("should skip comments and empty lines", func(t *testing.T) {
+		path := writeMapping(t, "# comment\n\nk8s-dogu-operator-crd=k8s-dogu-lib\n k8s-backup-operator-crd = k8s-backup-lib \n")
+
+		mapping, err := ReadMapping(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"k8s-dogu-operator-crd": "k8s-dogu-lib", "k8s-backup-operator-crd": "k8s-backup-lib"}, mapping)
+	})
+
+	t.Run("should return an empty mapping if the file does not exist", func(t *testing.T) {
+		mapping, err := ReadMapping(filepath.Join(t.TempDir(), "mapping.txt"))
+
+		require.NoError(t, err)
+		assert.Empty(t, mapping)
+	})
+
+	t.Run("should fail on invalid line", func(t *testing.T) {
+		_, err := ReadMapping(writeMapping(t, "k8s-dogu-operator-crd=k8s-dogu-lib\nk8s-velero\n"))
+
+		assert.ErrorContains(t, err, "mapping.txt:2: expected component=value")
+	})
+}
+
+func TestReadConstraints(t *testing.T) {
+	t.Run("should parse the constraints", func(t *testing.T) {
+		constraints, err := ReadConstraints(writeMapping(t, "k8s-prometheus=<76.0.0\nk8s-dogu-operator=^3.27.0, !=3.28.0\n"))
+
+		require.NoError(t, err)
+		assert.Equal(t, "<76.0.0", constraints["k8s-prometheus"].String())
+		assert.Equal(t, "^3.27.0, !=3.28.0", constraints["k8s-dogu-operator"].String())
+	})
+
+	t.Run("should fail on invalid constraint", func(t *testing.T) {
+		_, err := ReadConstraints(writeMapping(t, "k8s-prometheus=<latest\n"))
+
+		assert.ErrorContains(t, err, "component k8s-prometheus: invalid constraint")
+	})
+}
@@ -0,0 +1,275 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Source returns the released versions of a component. Versions that cannot be parsed are ignored by the caller.
+type Source interface {
+	Versions(ctx context.Context, component string) ([]string, error)
+}
+
+const (
+	DefaultGitHubURL   = "https://api.github.com"
+	DefaultGitHubOwner = "cloudogu"
+)
+
+// GitHubSource returns the releases of the GitHub repository of a component. Drafts and pre-releases are skipped.
+type GitHubSource struct {
+	client  *http.Client
+	baseURL string
+	owner   string
+	token   string
+	// repositories maps components to repositories whose name differs, e.g. CRDs that are released in lib repositories.
+	repositories map[string]string
+}
+
+func NewGitHubSource(client *http.Client, baseURL, owner, token string, repositories map[string]string) *GitHubSource {
+	return &GitHubSource{client: client, baseURL: strings.TrimSuffix(baseURL, "/"), owner: owner, token: token, repositories: repositories}
+}
+
+type gitHubRelease struct {
+	TagName    string `json:"tag_name"`
+	Draft      bool   `json:"draft"`
+	PreRelease bool   `json:"prerelease"`
+}
+
+func (s *GitHubSource) Versions(ctx context.Context, component string) ([]string, error) {
+	repository := component
+	if mapped, ok := s.repositories[component]; ok {
+		repository = mapped
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	if s.token != "" {
+		header.Set("Authorization", "Bearer "+s.token)
+	}
+
+	var versions []string
+	next := fmt.Sprintf("%s/repos/%s/%s/releases?per_page=100", s.baseURL, s.owner, repository)
+	for next != "" {
+		var releases []gitHubRelease
+		var err error
+		if next, err = getJSON(ctx, s.client, next, header, &releases); err != nil {
+			return nil, fmt.Errorf("failed to list releases of %s/%s: %w", s.owner, repository, err)
+		}
+
+		for _, release := range releases {
+			if !release.Draft && !release.PreRelease {
+				versions = append(versions, release.TagName)
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+// HelmSource returns the tags of the chart of a component in an OCI registry, e.g. registry.cloudogu.com/k8s.
+type HelmSource struct {
+	client    *http.Client
+	baseURL   string
+	namespace string
+	username  string
+	password  string
+}
+
+// NewHelmSource creates a source for the charts in the namespace of the registry. The host may contain a scheme,
+// otherwise https is used.
+func NewHelmSource(client *http.Client, host, namespace, username, password string) *HelmSource {
+	baseURL := strings.TrimSuffix(host, "/")
+	if !strings.Contains(baseURL, "://") {
+		baseURL = "https://" + baseURL
+	}
+	return &HelmSource{client: client, baseURL: baseURL, namespace: namespace, username: username, password: password}
+}
+
+type tagList struct {
+	Tags []string `json:"tags"`
+}
+
+func (s *HelmSource) Versions(ctx context.Context, component string) ([]string, error) {
+	repository := s.namespace + "/" + component
+	header, err := s.authorize(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authorize at %s: %w", s.baseURL, err)
+	}
+
+	var versions []string
+	next := fmt.Sprintf("%s/v2/%s/tags/list?n=1000", s.baseURL, repository)
+	for next != "" {
+		var tags tagList
+		if next, err = getJSON(ctx, s.client, next, header, &tags); err != nil {
+			return nil, fmt.Errorf("failed to list tags of %s: %w", repository, err)
+		}
+		versions = append(versions, tags.Tags...)
+	}
+
+	return versions, nil
+}
+
+// authorize returns the header that authorizes the pull of the repository. It follows the challenge of the registry,
+// which is either basic authentication or a bearer token from the realm of the registry.
+func (s *HelmSource) authorize(ctx context.Context, repository string) (http.Header, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/v2/", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	_ = resp.Body.Close()
+
+	header := http.Header{}
+	if resp.StatusCode != http.StatusUnauthorized {
+		return header, nil
+	}
+
+	scheme, params := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	switch strings.ToLower(scheme) {
+	case "basic":
+		req.SetBasicAuth(s.username, s.password)
+		header.Set("Authorization", req.Header.Get("Authorization"))
+		return header, nil
+	case "bearer":
+		token, err := s.fetchToken(ctx, params, "repository:"+repository+":pull")
+		if err != nil {
+			return nil, err
+		}
+		header.Set("Authorization", "Bearer "+token)
+		return header, nil
+	default:
+		return nil, fmt.Errorf("unsupported authentication scheme %q", scheme)
+	}
+}
+
+func (s *HelmSource) fetchToken(ctx context.Context, params map[string]string, scope string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid token realm %q", params["realm"])
+	}
+
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if s.username != "" {
+		req.SetBasicAuth(s.username, s.password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token request returned %s", resp.Status)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to parse token: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+var challengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// parseChallenge parses a WWW-Authenticate header like `Bearer realm="https://auth",service="registry"`.
+func parseChallenge(header string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(header), " ")
+	params := map[string]string{}
+	for _, match := range challengeParam.FindAllStringSubmatch(rest, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	return scheme, params
+}
+
+var nextLink = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// getJSON decodes the response into target and returns the URL of the next page from the Link header, if any.
+func getJSON(ctx context.Context, client *http.Client, rawURL string, header http.Header, target any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = header.Clone()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("%s returned %s: %s", req.URL.Redacted(), resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return "", fmt.Errorf("failed to parse response of %s: %w", req.URL.Redacted(), err)
+	}
+
+	match := nextLink.FindStringSubmatch(resp.Header.Get("Link"))
+	if match == nil {
+		return "", nil
+	}
+	// registries return the next page relative to the host
+	next, err := req.URL.Parse(match[1])
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %w", match[1], err)
+	}
+	return next.String(), nil
+}
+
+// FixtureSource returns the versions of a file, e.g. to test the update offline.
+type FixtureSource struct {
+	versions map[string][]string
+}
+
+// ReadFixture reads a YAML file that maps components to their released versions:
+//
+//	k8s-dogu-operator:
+//	  - 3.27.0
+//	  - 3.28.0
+func ReadFixture(path string) (*FixtureSource, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+
+	var versions map[string][]string
+	if err = yaml.UnmarshalStrict(raw, &versions); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture %s: %w", path, err)
+	}
+
+	return &FixtureSource{versions: versions}, nil
+}
+
+func (s *FixtureSource) Versions(_ context.Context, component string) ([]string, error) {
+	return s.versions[component], nil
+}
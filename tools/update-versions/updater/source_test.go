@@ -0,0 +1,164 @@
+package updater
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubSource_Versions(t *testing.T) {
+	t.Run("should return the releases of all pages", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/repos/cloudogu/k8s-dogu-lib/releases", r.URL.Path)
+			assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+			if r.URL.Query().Get("page") == "" {
+				w.Header().Set("Link", fmt.Sprintf(`<http://%s/repos/cloudogu/k8s-dogu-lib/releases?per_page=100&page=2>; rel="next"`, r.Host))
+				_ = json.NewEncoder(w).Encode([]gitHubRelease{{TagName: "v2.14.0"}, {TagName: "v3.0.0-rc1", PreRelease: true}, {TagName: "v3.0.0", Draft: true}})
+				return
+			}
+			_ = json.NewEncoder(w).Encode([]gitHubRelease{{TagName: "v2.13.0"}})
+		}))
+		defer server.Close()
+		source := NewGitHubSource(server.Client(), server.URL+"/", "cloudogu", "secret", map[string]string{"k8s-dogu-operator-crd": "k8s-dogu-lib"})
+
+		versions, err := source.Versions(context.Background(), "k8s-dogu-operator-crd")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"v2.14.0", "v2.13.0"}, versions)
+	})
+
+	t.Run("should fail if the repository does not exist", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("Authorization"))
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+		}))
+		defer server.Close()
+		source := NewGitHubSource(server.Client(), server.URL, "cloudogu", "", nil)
+
+		_, err := source.Versions(context.Background(), "unknown")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to list releases of cloudogu/unknown")
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+}
+
+func TestHelmSource_Versions(t *testing.T) {
+	t.Run("should list the tags with a bearer token", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/token":
+				username, password, _ := r.BasicAuth()
+				assert.Equal(t, "helm-user", username)
+				assert.Equal(t, "helm-password", password)
+				assert.Equal(t, "repository:k8s/k8s-dogu-operator:pull", r.URL.Query().Get("scope"))
+				assert.Equal(t, "registry", r.URL.Query().Get("service"))
+				_, _ = w.Write([]byte(`{"token":"abc"}`))
+			case "/v2/":
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+			case "/v2/k8s/k8s-dogu-operator/tags/list":
+				assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+				if r.URL.Query().Get("last") == "" {
+					w.Header().Set("Link", `</v2/k8s/k8s-dogu-operator/tags/list?n=1000&last=3.27.0>; rel="next"`)
+					_, _ = w.Write([]byte(`{"tags":["3.26.0","3.27.0"]}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"tags":["3.28.0"]}`))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+		source := NewHelmSource(server.Client(), server.URL, "k8s", "helm-user", "helm-password")
+
+		versions, err := source.Versions(context.Background(), "k8s-dogu-operator")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3.26.0", "3.27.0", "3.28.0"}, versions)
+	})
+
+	t.Run("should list the tags with basic authentication", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, _, ok := r.BasicAuth(); !ok {
+				w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"tags":["1.0.0"]}`))
+		}))
+		defer server.Close()
+		source := NewHelmSource(server.Client(), server.URL, "k8s", "helm-user", "helm-password")
+
+		versions, err := source.Versions(context.Background(), "k8s-ces-gateway")
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1.0.0"}, versions)
+	})
+
+	t.Run("should fail if the token is rejected", func(t *testing.T) {
+		var server *httptest.Server
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+		source := NewHelmSource(server.Client(), server.URL, "k8s", "helm-user", "wrong")
+
+		_, err := source.Versions(context.Background(), "k8s-ces-gateway")
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "token request returned 401 Unauthorized")
+	})
+}
+
+func TestNewHelmSource(t *testing.T) {
+	assert.Equal(t, "https://registry.cloudogu.com", NewHelmSource(nil, "registry.cloudogu.com", "k8s", "", "").baseURL)
+	assert.Equal(t, "http://localhost:5000", NewHelmSource(nil, "http://localhost:5000/", "k8s", "", "").baseURL)
+}
+
+func TestReadFixture(t *testing.T) {
+	t.Run("should return the versions of the file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fixture.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("k8s-dogu-operator:\n  - 3.27.0\n  - v3.28.0\n"), 0o600))
+		source, err := ReadFixture(path)
+		require.NoError(t, err)
+
+		versions, err := source.Versions(context.Background(), "k8s-dogu-operator")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3.27.0", "v3.28.0"}, versions)
+
+		versions, err = source.Versions(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+	})
+
+	t.Run("should fail on invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "fixture.yaml")
+		require.NoError(t, os.WriteFile(path, []byte("k8s-dogu-operator: 3.27.0\n"), 0o600))
+
+		_, err := ReadFixture(path)
+
+		assert.ErrorContains(t, err, "failed to parse fixture")
+	})
+
+	t.Run("should fail on missing file", func(t *testing.T) {
+		_, err := ReadFixture(filepath.Join(t.TempDir(), "fixture.yaml"))
+
+		assert.ErrorContains(t, err, "failed to read fixture")
+	})
+}
@@ -0,0 +1,149 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/ecosystem-core/default-config/versions"
+)
+
+// ComponentOperator is installed as a dependency of the chart instead of a component.
+const ComponentOperator = "k8s-component-operator"
+
+// Update is a changed version of a component.
+type Update struct {
+	Component string
+	From      string
+	To        string
+}
+
+// Updater updates the versions of the components to the newest release that meets their constraint.
+type Updater struct {
+	source      Source
+	constraints map[string]versions.Constraint
+}
+
+func NewUpdater(source Source, constraints map[string]versions.Constraint) *Updater {
+	return &Updater{source: source, constraints: constraints}
+}
+
+// Update updates the components of the values.yaml and the component operator, whose version is the dependency
+// of the Chart.yaml and the image tag of the values.yaml. Components whose releases cannot be listed are kept and
+// returned as error after all other components are updated.
+func (u *Updater) Update(ctx context.Context, values, chart *Document) ([]Update, error) {
+	var updates []Update
+	var errs []error
+
+	fields := values.ComponentVersions()
+	for _, component := range slices.Sorted(maps.Keys(fields)) {
+		field := fields[component]
+		latest, err := u.latest(ctx, component, field.Value)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if latest == "" {
+			continue
+		}
+
+		if err = values.Set(field, latest); err != nil {
+			return nil, err
+		}
+		updates = append(updates, Update{Component: component, From: field.Value, To: latest})
+	}
+
+	update, err := u.updateComponentOperator(ctx, values, chart)
+	if err != nil {
+		errs = append(errs, err)
+	} else if update != nil {
+		updates = append(updates, *update)
+	}
+
+	return updates, errors.Join(errs...)
+}
+
+func (u *Updater) updateComponentOperator(ctx context.Context, values, chart *Document) (*Update, error) {
+	dependency, ok := chart.Dependency(ComponentOperator)
+	if !ok {
+		slog.Warn("chart has no dependency", "dependency", ComponentOperator)
+		return nil, nil
+	}
+
+	// the dependency is a range like "^1.14.0", whose operator is kept
+	current := strings.TrimLeft(dependency.Value, "^~=v")
+	operator := strings.TrimSuffix(dependency.Value, current)
+	latest, err := u.latest(ctx, ComponentOperator, current)
+	if err != nil || latest == "" {
+		return nil, err
+	}
+
+	if err = chart.Set(dependency, operator+latest); err != nil {
+		return nil, err
+	}
+	if tag, ok := values.Lookup(ComponentOperator, "manager", "image", "tag"); ok {
+		if err = values.Set(tag, latest); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Update{Component: ComponentOperator, From: current, To: latest}, nil
+}
+
+// latest returns the newest release of the component that meets its constraint if it is newer than the current
+// version, otherwise an empty string.
+func (u *Updater) latest(ctx context.Context, component, current string) (string, error) {
+	currentVersion, err := versions.ParseVersion(current)
+	if err != nil {
+		slog.Warn("skipping component with invalid version", "component", component, "version", current, "err", err)
+		return "", nil
+	}
+
+	releases, err := u.source.Versions(ctx, component)
+	if err != nil {
+		return "", fmt.Errorf("component %s: %w", component, err)
+	}
+
+	constraint := u.constraints[component]
+	var latest *versions.Version
+	for _, release := range releases {
+		version, err := versions.ParseVersion(release)
+		if err != nil || version.PreRelease != "" || !constraint.Check(version) {
+			continue
+		}
+		if latest == nil || version.Compare(*latest) > 0 {
+			latest = &version
+		}
+	}
+
+	if latest == nil {
+		slog.Warn("no release version found", "component", component, "constraint", constraint.String())
+		return "", nil
+	}
+	if latest.Compare(currentVersion) <= 0 {
+		slog.Debug("component is up to date", "component", component, "version", current, "latest", latest.String())
+		return "", nil
+	}
+
+	return latest.String(), nil
+}
+
+// WriteChangelog writes the updates as entry of the CHANGELOG.md.
+func WriteChangelog(w io.Writer, updates []Update) error {
+	if len(updates) == 0 {
+		return nil
+	}
+
+	lines := []string{"- Update components"}
+	for _, update := range updates {
+		lines = append(lines, fmt.Sprintf("  - Bump Version of %s from %s to %s", update.Component, update.From, update.To))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
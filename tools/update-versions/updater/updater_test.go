@@ -0,0 +1,138 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cloudogu/ecosystem-core/default-config/versions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingSource struct {
+	*FixtureSource
+	failing string
+}
+
+func (s failingSource) Versions(ctx context.Context, component string) ([]string, error) {
+	if component == s.failing {
+		return nil, errors.New("rate limit exceeded")
+	}
+	return s.FixtureSource.Versions(ctx, component)
+}
+
+func parseTestDocuments(t *testing.T) (*Document, *Document) {
+	t.Helper()
+	values, err := ParseDocument([]byte(testValues))
+	require.NoError(t, err)
+	chart, err := ParseDocument([]byte(testChart))
+	require.NoError(t, err)
+	return values, chart
+}
+
+func TestUpdater_Update(t *testing.T) {
+	source := &FixtureSource{versions: map[string][]string{
+		"k8s-dogu-operator":      {"v3.26.0", "v3.28.0", "v3.27.0", "v4.0.0-rc1"},
+		"k8s-ces-gateway":        {"3.3.3", "invalid"},
+		"k8s-prometheus":         {"75.3.5-7", "75.3.5-8", "76.0.0-1"},
+		"k8s-component-operator": {"v1.14.0", "v1.15.0"},
+	}}
+
+	t.Run("should update the components to the newest releases", func(t *testing.T) {
+		values, chart := parseTestDocuments(t)
+
+		updates, err := NewUpdater(source, nil).Update(context.Background(), values, chart)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Update{
+			{Component: "k8s-dogu-operator", From: "3.27.0", To: "3.28.0"},
+			{Component: "k8s-prometheus", From: "75.3.5-7", To: "76.0.0-1"},
+			{Component: ComponentOperator, From: "1.14.0", To: "1.15.0"},
+		}, updates)
+		assert.Contains(t, string(values.Bytes()), "      tag: 1.15.0 # the chart version\n")
+		assert.Contains(t, string(values.Bytes()), "    version: 3.28.0\n")
+		assert.Contains(t, string(values.Bytes()), "    version: \"3.3.3\"\n")
+		assert.Contains(t, string(chart.Bytes()), "    version: \"^1.15.0\"\n")
+	})
+
+	t.Run("should respect the constraints", func(t *testing.T) {
+		values, chart := parseTestDocuments(t)
+		prometheus, err := versions.ParseConstraint("<76.0.0")
+		require.NoError(t, err)
+		operator, err := versions.ParseConstraint("~1.14.0")
+		require.NoError(t, err)
+
+		updates, err := NewUpdater(source, map[string]versions.Constraint{"k8s-prometheus": prometheus, ComponentOperator: operator}).
+			Update(context.Background(), values, chart)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Update{
+			{Component: "k8s-dogu-operator", From: "3.27.0", To: "3.28.0"},
+			{Component: "k8s-prometheus", From: "75.3.5-7", To: "75.3.5-8"},
+		}, updates)
+		assert.Contains(t, string(chart.Bytes()), "    version: \"^1.14.0\"\n")
+	})
+
+	t.Run("should not downgrade a component", func(t *testing.T) {
+		values, chart := parseTestDocuments(t)
+		constraint, err := versions.ParseConstraint("<3.27.0")
+		require.NoError(t, err)
+
+		updates, err := NewUpdater(source, map[string]versions.Constraint{"k8s-dogu-operator": constraint}).Update(context.Background(), values, chart)
+
+		require.NoError(t, err)
+		assert.NotContains(t, updates, Update{Component: "k8s-dogu-operator", From: "3.27.0", To: "3.26.0"})
+		assert.Contains(t, string(values.Bytes()), "    version: 3.27.0\n")
+	})
+
+	t.Run("should update the other components if the releases of a component cannot be listed", func(t *testing.T) {
+		values, chart := parseTestDocuments(t)
+
+		updates, err := NewUpdater(failingSource{FixtureSource: source, failing: "k8s-dogu-operator"}, nil).
+			Update(context.Background(), values, chart)
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "component k8s-dogu-operator: rate limit exceeded")
+		assert.Len(t, updates, 2)
+		assert.Contains(t, string(values.Bytes()), "    version: 3.27.0\n")
+		assert.Contains(t, string(values.Bytes()), "      version: '76.0.0-1'\n")
+	})
+
+	t.Run("should skip the component operator if the chart has no dependency", func(t *testing.T) {
+		values, _ := parseTestDocuments(t)
+		chart, err := ParseDocument([]byte("apiVersion: v2\n"))
+		require.NoError(t, err)
+
+		updates, err := NewUpdater(source, nil).Update(context.Background(), values, chart)
+
+		require.NoError(t, err)
+		assert.Len(t, updates, 2)
+		assert.Contains(t, string(values.Bytes()), "      tag: 1.14.0 # the chart version\n")
+	})
+}
+
+func TestWriteChangelog(t *testing.T) {
+	t.Run("should write the updates like the entries of the CHANGELOG.md", func(t *testing.T) {
+		var out bytes.Buffer
+
+		err := WriteChangelog(&out, []Update{
+			{Component: "k8s-dogu-operator", From: "3.27.0", To: "3.28.0"},
+			{Component: "k8s-prometheus", From: "75.3.5-7", To: "75.3.5-8"},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, "- Update components\n"+
+			"  - Bump Version of k8s-dogu-operator from 3.27.0 to 3.28.0\n"+
+			"  - Bump Version of k8s-prometheus from 75.3.5-7 to 75.3.5-8\n", out.String())
+	})
+
+	t.Run("should write nothing without updates", func(t *testing.T) {
+		var out bytes.Buffer
+
+		require.NoError(t, WriteChangelog(&out, nil))
+
+		assert.Empty(t, out.String())
+	})
+}